@@ -2,26 +2,21 @@ package main
 
 import (
 	"context"
-	"encoding/base64"
 	_ "encoding/hex"
 	"fmt"
 	_ "log"
 	"math/big"
 	"sync"
-	"time"
 	_ "time"
 
 	ethereumcrypto "github.com/ethereum/go-ethereum/crypto"
-	"golang.org/x/crypto/sha3"
 
 	_ "github.com/davecgh/go-spew/spew"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/fxamacker/cbor/v2"
 	"github.com/klaytn/klaytn/common/hexutil"
-	"github.com/klaytn/klaytn/rlp"
 
 	"github.com/w3-key/mps-lean/pkg/ecdsa"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
@@ -30,20 +25,19 @@ import (
 	"github.com/w3-key/mps-lean/pkg/protocol"
 	"github.com/w3-key/mps-lean/pkg/test"
 	"github.com/w3-key/mps-lean/protocols/cmp"
+	"github.com/w3-key/mps-lean/protocols/cmp/ethereum"
 	"github.com/w3-key/mps-lean/protocols/cmp/sign"
 	"github.com/w3-key/mps-lean/protocols/example"
 )
 
-//var signatureConfigArray []sign.SignatureParts
-//var signaturesArray curve.Scalar
-var signatureConfigArray = []sign.SignatureParts{}
-var signaturesArray = []curve.Scalar{}
+var (
+	signatureShares    = map[party.ID]*sign.SignatureShare{}
+	signatureSharesMtx sync.Mutex
+)
 var masterPublicAddress common.Address
 var finalDataToSign []byte
-var finalEmptyTx *types.Transaction
-var tempArray1 = []curve.Scalar{}
-var tempArray2 = []curve.Scalar{}
-var tempArray3 = []curve.Scalar{}
+var finalTx *types.Transaction
+var finalChainID *big.Int
 //var endpoint string = "https://goerli.infura.io/v3/f0b33e4b953e4306b6d5e8b9f9d51567"
 //var endpoint string = "https://sepolia.infura.io/v3/f0b33e4b953e4306b6d5e8b9f9d51567"
 var endpoint string = "https://rpc.sepolia.org/"
@@ -92,47 +86,18 @@ func CMPRefresh(c *cmp.Config, n *test.Network, pl *pool.Pool) (*cmp.Config, err
 	return r.(*cmp.Config), nil
 }
 
-func SingleSign(specialConfig sign.SignatureParts, message []byte) (signresult curve.Scalar) {
-	//this signs a message for a single participant using their signatureparts
-	group := specialConfig.Group
-
-
-	KShare := specialConfig.GroupKShare
-	BigR := specialConfig.GroupBigR // R = [δ⁻¹] Γ
-	R := BigR.XScalar()             // r = R|ₓ
-	km := curve.FromHash(group, message)
-	km.Mul(KShare)
-	SigmaShare := group.NewScalar().Set(R).Mul(specialConfig.GroupChiShare).Add(km)
-	return SigmaShare
-}
-
-func CombineShares(SigmaShares []curve.Scalar, specialConfig sign.SignatureParts) (signature ecdsa.Signature) {
-	//this combines signatures from vault participants
-	var Sigma curve.Scalar
-	var BigR curve.Point
-
-		Sigma = specialConfig.Group.NewScalar()
-		BigR = specialConfig.GroupBigR
-		//maybe also try the GetBigR function
-	for _, SS := range SigmaShares {
-		Sigma.Add(SS)
-	}
-
-	combinedSig := ecdsa.Signature{
-		R: BigR,
-		S: Sigma,
+func CMPPresign(c *cmp.Config, signers party.IDSlice, n *test.Network, pl *pool.Pool) (*ecdsa.PreSignature, error) {
+	//offline phase, the resulting presignature can only be used once
+	h, err := protocol.NewMultiHandler(cmp.Presign(c, signers, pl), nil)
+	if err != nil {
+		return nil, err
 	}
-
-	return combinedSig
-}
-
-func GenerateSignatureConfigs(c *cmp.Config, m []byte, signers party.IDSlice, n *test.Network, pl *pool.Pool, justinfo bool) (sign.SignatureParts, error) {
-	//this is my special function to get the users Groupsignature parts
-	h, _ := protocol.NewMultiHandler(cmp.Sign(c, signers, m, pl, justinfo), nil)
 	test.HandlerLoop(c.ID, h, n)
-	signResult, _ := h.Result()
-	sigparts := signResult.(sign.SignatureParts)
-	return sigparts, nil
+	r, err := h.Result()
+	if err != nil {
+		return nil, err
+	}
+	return r.(*ecdsa.PreSignature), nil
 }
 
 func FundEOA(client1 *ethclient.Client, eoa common.Address) error {
@@ -182,61 +147,24 @@ func FormTransaction(client1 *ethclient.Client) ([]byte, error) {
 		AccessList: nil,
 	})
 
-	//fmt.Println("IMPORTANT TX FIGURING OUT STUFF")
-	//fmt.Println(emptyNewTx)
-	var txplaceholder types.Transaction
-
-	zzzz, _ := emptyNewTx.MarshalJSON()
-	//fmt.Println(zzzz)
-	stringit := base64.StdEncoding.EncodeToString(zzzz)
+	finalTx = emptyNewTx
+	finalChainID = chainID1
 
-	unstringit, _ := base64.StdEncoding.DecodeString(stringit)
-	err := txplaceholder.UnmarshalJSON(unstringit)
+	hashBytes, err := ethereum.TransactionHash(emptyNewTx, chainID1)
 	if err != nil {
-		fmt.Println(err)
-	}
-
-	//fmt.Println(txplaceholder)
-	
-	finalEmptyTx = emptyNewTx
-
-	emptyNewTxInterface := []interface{}{
-		chainID1,
-		emptyNewTx.Nonce(),
-		emptyNewTx.GasTipCap(),
-		emptyNewTx.GasFeeCap(),
-		emptyNewTx.Gas(),
-		emptyNewTx.To(),
-		emptyNewTx.Value(),
-		emptyNewTx.Data(),
-		emptyNewTx.AccessList(),
+		return nil, err
 	}
-
-	hashtoSign := prefixedRlpHash(emptyNewTx.Type(), emptyNewTxInterface)
-	hashBytes := hashtoSign.Bytes()
 	finalDataToSign = hashBytes
 	fmt.Println("DATATOSIGN")
 	fmt.Println(finalDataToSign)
 	return hashBytes, nil
 }
 
-func SendTransaction(SigmaShares []curve.Scalar, specialConfig []sign.SignatureParts) error {
-	var client1, _ = ethclient.Dial(endpoint)
-	var chainID1, _ = client1.NetworkID(context.Background())
-
-	signature := CombineShares(SigmaShares, signatureConfigArray[0])
-
-  //IMPORTANT CALLOUT HERE, I CHANGED THE the function below to use GetRecoverId instead of GetEthRecoverId. This allowed the rest of this stuff to verify.	
-	sigForVerification, _ := signature.SigEthereum()
-
-	sig := hexutil.MustDecode("0x" + common.Bytes2Hex(sigForVerification))
-	//Comemnting this out, it continually resolves to the EOA ADDRESS / masterpublickey, keeping it here in case we need to test it again.
-	//recovered, err := crypto.SigToPub(finalDataToSign, sig)
-	//if err != nil {
-	//	fmt.Println("ERROR: ", err)
-	//}
-	//recoveredAddr := crypto.PubkeyToAddress(*recovered)
-	//fmt.Println("recoveredaddr", recoveredAddr)
+func SendTransaction(shares map[party.ID]*sign.SignatureShare, publicKey curve.Point) error {
+	signature, err := sign.CombineShares(publicKey, finalDataToSign, shares)
+	if err != nil {
+		return err
+	}
 
 	//var toAddress1 = common.HexToAddress("0x94fD43dE0095165eE054554E1A84ccEfa8fdA47F")
 
@@ -247,7 +175,11 @@ func SendTransaction(SigmaShares []curve.Scalar, specialConfig []sign.SignatureP
 	//fmt.Println("BALANCEOFEOASTART")
 	//fmt.Println(balance)
 	//fmt.Println("BALANCEOFEOAEND")
-	emptyNewTxSigned, _ := finalEmptyTx.WithSignature(types.LatestSignerForChainID(chainID1), sig)
+	// the sender of the signed transaction is checked to be the address of publicKey
+	emptyNewTxSigned, err := ethereum.SignedTransaction(finalTx, finalChainID, signature, publicKey)
+	if err != nil {
+		return err
+	}
 	
 	//fmt.Println("TIMESTARTTRANSACTIONSENT")
 	//time.Sleep(20 * time.Second)
@@ -281,20 +213,6 @@ func GetTransactionMessage(tx *types.Transaction) types.Message {
 	return msg
 }
 
-var hasherPool = sync.Pool{
-	New: func() interface{} { return sha3.NewLegacyKeccak256() },
-}
-
-func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
-	sha := hasherPool.Get().(crypto.KeccakState)
-	defer hasherPool.Put(sha)
-	sha.Reset()
-	sha.Write([]byte{prefix})
-	rlp.Encode(sha, x)
-	sha.Read(h[:])
-	return h
-}
-
 
 func All(id party.ID, ids party.IDSlice, threshold int, message []byte, n *test.Network, wg *sync.WaitGroup, pl *pool.Pool) error {
 
@@ -326,49 +244,29 @@ func All(id party.ID, ids party.IDSlice, threshold int, message []byte, n *test.
 		FormTransaction(client1)
 		}
 
-	var unmarshalledSigData *sign.SignatureParts
-	unmarshalledConfig := unmarshalledSigData.EmptyConfig()
-
-	participantShares, _ := GenerateSignatureConfigs(refreshConfig, message, signers, n, pl, true)
-	signatureConfigArray = append(signatureConfigArray, participantShares)
-	marshalledConfig, err := cbor.Marshal(participantShares)
-	if err != nil {
-		fmt.Println(err)
-	}
-	//store marshalledconfigs
-	err = cbor.Unmarshal(marshalledConfig, &unmarshalledConfig)
+	preSignature, err := CMPPresign(refreshConfig, signers, n, pl)
 	if err != nil {
-		fmt.Println(err)
-	}
-fmt.Println("keygendone")
-	if (id == "a") {
-		share := SingleSign(signatureConfigArray[0], finalDataToSign)
-		signaturesArray = append(signaturesArray,share)
-		fmt.Println("A SEND TX")
-		SendTransaction(signaturesArray, signatureConfigArray)
+		return err
 	}
-	time.Sleep(5 * time.Second)
-
-	if (id == "b") {
-		share := SingleSign(signatureConfigArray[1], finalDataToSign)
-		signaturesArray = append(signaturesArray,share)
-		//tempArray2 := append(tempArray1,share)
-		fmt.Println("b SEND TX")
-		SendTransaction(signaturesArray, signatureConfigArray)
+	store := sign.NewMemoryStore()
+	if err = store.Put(preSignature); err != nil {
+		return err
 	}
+	fmt.Println("presign done")
 
+	//the presignature is consumed here, a second SingleSign with the same ID fails
+	share, err := sign.SingleSign(store, preSignature.ID, finalDataToSign)
+	if err != nil {
+		return err
+	}
 
-	//if (id == "c") {
-	//	share := SingleSign(signatureConfigArray[2], finalDataToSign)
-	//	signaturesArray = append(signaturesArray,share)
-	//	//tempArray2 := append(tempArray1,share)
-	//	//SendTransaction(tempArray2, signatureConfigArray)
-	//}
-	//time.Sleep(2 * time.Second)
-	
-	//if (id == "b" || id == "c" || id == "d" || id == "e") {
-	//SendTransaction(signaturesArray, signatureConfigArray)
-	//}
+	signatureSharesMtx.Lock()
+	defer signatureSharesMtx.Unlock()
+	signatureShares[id] = share
+	if len(signatureShares) == len(signers) {
+		fmt.Println(id, "SEND TX")
+		return SendTransaction(signatureShares, refreshConfig.PublicPoint())
+	}
 
 	return nil
 }
@@ -0,0 +1,168 @@
+package bip32
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// MainnetPublic is the version of mainnet extended public keys (xpub).
+	MainnetPublic uint32 = 0x0488B21E
+	// TestnetPublic is the version of testnet extended public keys (tpub).
+	TestnetPublic uint32 = 0x043587CF
+
+	// HardenedOffset is the first index of a hardened child.
+	HardenedOffset uint32 = 1 << 31
+
+	// extendedKeyLen is the length of a serialized extended key, without checksum.
+	extendedKeyLen = 78
+)
+
+// ParsePath parses a derivation path of the form "m/44/60/0/0/7" into a list of indices.
+//
+// Since threshold keys can only be derived without the private key, hardened segments,
+// such as "44'" or "44h", are rejected.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("bip32: path %q must start with \"m\"", path)
+	}
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		if strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h") || strings.HasSuffix(segment, "H") {
+			return nil, fmt.Errorf("bip32: hardened segment %q cannot be derived from a public key", segment)
+		}
+		i, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("bip32: invalid segment %q", segment)
+		}
+		if uint32(i) >= HardenedOffset {
+			return nil, fmt.Errorf("bip32: index %d is hardened", i)
+		}
+		indices = append(indices, uint32(i))
+	}
+	return indices, nil
+}
+
+// Fingerprint returns the first 4 bytes of HASH160(public), identifying a parent key.
+func Fingerprint(public *curve.Secp256k1Point) uint32 {
+	compressed, _ := public.MarshalBinary()
+	sha := sha256.Sum256(compressed)
+	h := ripemd160.New()
+	_, _ = h.Write(sha[:])
+	return binary.BigEndian.Uint32(h.Sum(nil)[:4])
+}
+
+// ExtendedPublicKey is a public key together with its chain code and position in a BIP-32 tree.
+//
+// It can be used to derive unhardened child public keys without any key share.
+type ExtendedPublicKey struct {
+	// Version is MainnetPublic or TestnetPublic.
+	Version uint32
+	// Depth is 0 for the master key.
+	Depth uint8
+	// ParentFingerprint is the Fingerprint of the parent key, or 0 for the master key.
+	ParentFingerprint uint32
+	// ChildNumber is the index of this key in its parent, or 0 for the master key.
+	ChildNumber uint32
+	// ChainCode is the 32 byte chaining value.
+	ChainCode []byte
+	// PublicKey is the public key.
+	PublicKey *curve.Secp256k1Point
+}
+
+// Derive returns the ith child of k, along with the scalar which was added to the public key.
+//
+// If an error is returned, this index generates an invalid key, and the next one should be used.
+func (k *ExtendedPublicKey) Derive(i uint32) (*ExtendedPublicKey, *curve.Secp256k1Scalar, error) {
+	if i >= HardenedOffset {
+		return nil, nil, fmt.Errorf("bip32: index %d is hardened", i)
+	}
+	if k.Depth == math.MaxUint8 {
+		return nil, nil, errors.New("bip32: maximum depth reached")
+	}
+	scalar, chainCode, err := DeriveScalar(k.PublicKey, k.ChainCode, i)
+	if err != nil {
+		return nil, nil, err
+	}
+	public := scalar.ActOnBase().Add(k.PublicKey)
+	if public.IsIdentity() {
+		return nil, nil, fmt.Errorf("bad index: %d", i)
+	}
+	return &ExtendedPublicKey{
+		Version:           k.Version,
+		Depth:             k.Depth + 1,
+		ParentFingerprint: Fingerprint(k.PublicKey),
+		ChildNumber:       i,
+		ChainCode:         chainCode,
+		PublicKey:         public.(*curve.Secp256k1Point),
+	}, scalar, nil
+}
+
+// DerivePath derives the descendant of k at path, relative to k, such as "m/0/7".
+func (k *ExtendedPublicKey) DerivePath(path string) (*ExtendedPublicKey, error) {
+	indices, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	out := k
+	for _, i := range indices {
+		if out, _, err = out.Derive(i); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// String returns the Base58Check serialization of k, such as "xpub…".
+func (k *ExtendedPublicKey) String() string {
+	data := make([]byte, 0, extendedKeyLen)
+	data = binary.BigEndian.AppendUint32(data, k.Version)
+	data = append(data, k.Depth)
+	data = binary.BigEndian.AppendUint32(data, k.ParentFingerprint)
+	data = binary.BigEndian.AppendUint32(data, k.ChildNumber)
+	data = append(data, k.ChainCode...)
+	compressed, _ := k.PublicKey.MarshalBinary()
+	data = append(data, compressed...)
+	return encodeBase58Check(data)
+}
+
+// ParseExtendedPublicKey decodes the Base58Check serialization of an extended public key.
+func ParseExtendedPublicKey(s string) (*ExtendedPublicKey, error) {
+	data, err := decodeBase58Check(s)
+	if err != nil {
+		return nil, fmt.Errorf("bip32: %w", err)
+	}
+	if len(data) != extendedKeyLen {
+		return nil, fmt.Errorf("bip32: invalid length %d for extended key", len(data))
+	}
+	k := &ExtendedPublicKey{
+		Version:           binary.BigEndian.Uint32(data[0:4]),
+		Depth:             data[4],
+		ParentFingerprint: binary.BigEndian.Uint32(data[5:9]),
+		ChildNumber:       binary.BigEndian.Uint32(data[9:13]),
+		ChainCode:         append([]byte{}, data[13:45]...),
+		PublicKey:         new(curve.Secp256k1Point),
+	}
+	if k.Version != MainnetPublic && k.Version != TestnetPublic {
+		return nil, fmt.Errorf("bip32: unsupported version %x", k.Version)
+	}
+	if k.Depth == 0 && (k.ParentFingerprint != 0 || k.ChildNumber != 0) {
+		return nil, errors.New("bip32: master key with non zero parent fingerprint or index")
+	}
+	if data[45] != 2 && data[45] != 3 {
+		return nil, errors.New("bip32: invalid public key prefix")
+	}
+	if err = k.PublicKey.UnmarshalBinary(data[45:]); err != nil {
+		return nil, fmt.Errorf("bip32: %w", err)
+	}
+	return k, nil
+}
@@ -0,0 +1,65 @@
+package bip32
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// test vectors from https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
+func TestExtendedPublicKeyVectors(t *testing.T) {
+	tests := []struct {
+		parent, path, child string
+	}{
+		// vector 1, m/0H/1/2H/2 -> m/0H/1/2H/2/1000000000
+		{
+			"xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+			"m/1000000000",
+			"xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+		},
+		// vector 2, m -> m/0
+		{
+			"xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+			"m/0",
+			"xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+		},
+	}
+	for _, test := range tests {
+		parent, err := ParseExtendedPublicKey(test.parent)
+		require.NoError(t, err)
+		assert.Equal(t, test.parent, parent.String())
+
+		child, err := parent.DerivePath(test.path)
+		require.NoError(t, err)
+		assert.Equal(t, test.child, child.String())
+		assert.Equal(t, parent.Depth+1, child.Depth)
+		assert.Equal(t, Fingerprint(parent.PublicKey), child.ParentFingerprint)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	indices, err := ParsePath("m/44/60/0/0/7")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{44, 60, 0, 0, 7}, indices)
+
+	indices, err = ParsePath("m")
+	require.NoError(t, err)
+	assert.Empty(t, indices)
+
+	for _, path := range []string{"m/44'/60", "m/44h", "m/2147483648", "44/60", "m//1", "m/-1", "m/1/"} {
+		_, err = ParsePath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestParseExtendedPublicKeyInvalid(t *testing.T) {
+	valid := "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB"
+	_, err := ParseExtendedPublicKey(valid[:len(valid)-1] + "C")
+	assert.Error(t, err, "invalid checksum")
+	_, err = ParseExtendedPublicKey(valid + "0")
+	assert.Error(t, err, "invalid character")
+	// xprv of vector 1
+	_, err = ParseExtendedPublicKey("xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi")
+	assert.Error(t, err, "private keys are not supported")
+}
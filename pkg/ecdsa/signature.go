@@ -1,9 +1,10 @@
 package ecdsa
 
 import (
+	"encoding/asn1"
+	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"math/big"
 
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 )
@@ -18,94 +19,222 @@ func EmptySignature(group curve.Curve) Signature {
 	return Signature{R: group.NewPoint(), S: group.NewScalar()}
 }
 
-func (sig Signature) RecoveryId() byte {
-	r := sig.R.(*curve.Secp256k1Point)
-	s := sig.S.(*curve.Secp256k1Scalar)
-
-	var recid byte = 0
-
-	if !r.HasEvenY() {
-		recid = 1;
-	}
-
-	if s.Value().IsOverHalfOrder() {
-		recid ^= 1
+// Normalize returns the low-S form of sig.
+//
+// Both (R, s) and (−R, −s) are valid signatures of the same hash, since R only enters verification through its x
+// coordinate. Bitcoin and Ethereum only accept s ≤ n/2, so the signature is negated if s is in the upper half.
+// The receiver is never modified.
+func (sig Signature) Normalize() Signature {
+	if sig.S.IsOverHalfOrder() {
+		return Signature{R: sig.R.Negate(), S: sig.S.Curve().NewScalar().Set(sig.S).Negate()}
 	}
+	return Signature{R: sig.R, S: sig.S.Curve().NewScalar().Set(sig.S)}
+}
 
-	//fmt.Println("Recid")
-	//fmt.Println(recid)
-	//fmt.Println("Recid")
+// RecoveryId returns the recovery id of the low-S form of sig, as used by Ethereum.
+//
+// It is sig.Normalize().RawRecoveryId(), so the parity bit is flipped if s > n/2.
+func (sig Signature) RecoveryId() byte {
+	return sig.Normalize().RawRecoveryId()
+}
 
+// RawRecoveryId returns the recovery id of sig as is, used to recover the public key from r, s and the hash.
+//
+// The first bit is the parity of the y coordinate of R, and the second bit is set if the x coordinate of R overflows
+// the group order n, in which case r = x - n.
+func (sig Signature) RawRecoveryId() byte {
+	R := sig.R.ToECDSA()
+	recid := byte(R.Y.Bit(0))
+	if R.X.Cmp(sig.R.Curve().Order().Big()) >= 0 {
+		recid |= 2
+	}
 	return recid
 }
 
+// SigEthereum returns the 65 byte Ethereum encoding r ‖ s ‖ v of the low-S form of sig.
+//
+// Deprecated: use sig.Normalize().SerializeRecoverable().
 func (sig Signature) SigEthereum() ([]byte, error) {
-	IsOverHalfOrder := sig.S.IsOverHalfOrder() // s-values greater than secp256k1n/2 are considered invalid
+	return sig.Normalize().SerializeRecoverable()
+}
+
+// ToEthBytes returns the 65 byte Ethereum encoding r ‖ s ‖ v of the low-S form of sig.
+//
+// Deprecated: use sig.Normalize().SerializeRecoverable().
+func (sig Signature) ToEthBytes() ([]byte, error) {
+	return sig.Normalize().SerializeRecoverable()
+}
+
+// GetRecoverIdIntu returns the parity of the y coordinate of R.
+//
+// Deprecated: use sig.RawRecoveryId(), or sig.RecoveryId() for the low-S form.
+func (sig Signature) GetRecoverIdIntu() byte {
+	return sig.RawRecoveryId() & 1
+}
 
-	if IsOverHalfOrder {
-		sig.S.Negate()
+// rs returns the 32 byte big-endian encodings of r = R.x mod n and s.
+func (sig Signature) rs() (r, s []byte, err error) {
+	if sig.R.IsIdentity() || sig.S.IsZero() {
+		return nil, nil, errors.New("ecdsa: invalid signature")
+	}
+	if r, err = sig.R.XScalar().MarshalBinary(); err != nil {
+		return nil, nil, err
+	}
+	if s, err = sig.S.MarshalBinary(); err != nil {
+		return nil, nil, err
 	}
+	return r, s, nil
+}
 
-	r, err := sig.R.MarshalBinary()
+// SerializeCompact returns the 64 byte encoding r ‖ s of sig.
+func (sig Signature) SerializeCompact() ([]byte, error) {
+	r, s, err := sig.rs()
 	if err != nil {
 		return nil, err
 	}
-	s, err := sig.S.MarshalBinary()
+	return append(r, s...), nil
+}
+
+// SerializeRecoverable returns the 65 byte encoding r ‖ s ‖ v of sig, where v ∈ {0, 1, 2, 3} is the recovery id
+// of sig as is, given by RawRecoveryId.
+//
+// This is the format expected by go-ethereum's crypto.Ecrecover, once the signature is normalized.
+func (sig Signature) SerializeRecoverable() ([]byte, error) {
+	rs, err := sig.SerializeCompact()
 	if err != nil {
 		return nil, err
 	}
+	return append(rs, sig.RawRecoveryId()), nil
+}
 
-	rs := make([]byte, 0, 65)
-	rs = append(rs, r...)
-	rs = append(rs, s...)
-
-	if IsOverHalfOrder {
-		v := rs[0] - 2 // Convert to Ethereum signature format with 'recovery id' v at the end.
-		copy(rs, rs[1:])
-		rs[64] = v ^ 1
-	} else {
-		v := rs[0] - 2
-		copy(rs, rs[1:])
-		rs[64] = v
-	}
+type derSignature struct {
+	R, S *big.Int
+}
 
-	r[0] = rs[64] + 2
-	if err := sig.R.UnmarshalBinary(r); err != nil {
+// SerializeDER returns the ASN.1 DER encoding of sig, as produced by crypto/ecdsa.SignASN1.
+//
+// Bitcoin requires the signature to be normalized first.
+func (sig Signature) SerializeDER() ([]byte, error) {
+	r, s, err := sig.rs()
+	if err != nil {
 		return nil, err
 	}
-
-	return rs, nil
+	return asn1.Marshal(derSignature{R: new(big.Int).SetBytes(r), S: new(big.Int).SetBytes(s)})
 }
 
-// Marshal marshals a signature to a byte slice.
-func (sig Signature) ToEthBytes() ([]byte, error) {
-	rb, err := sig.R.MarshalBinary()
+// ParseCompact decodes the 64 byte encoding r ‖ s of a signature of hash by publicKey.
+//
+// Since the encoding only contains the x coordinate of R, R is recomputed from publicKey and hash,
+// which fails if the signature is invalid.
+func ParseCompact(publicKey curve.Point, hash, data []byte) (*Signature, error) {
+	if len(data) != 64 {
+		return nil, fmt.Errorf("ecdsa: invalid length for compact signature: %d", len(data))
+	}
+	return fromRS(publicKey, hash, data[:32], data[32:])
+}
 
+// ParseDER decodes the strict ASN.1 DER encoding of a signature of hash by publicKey.
+//
+// As with ParseCompact, R is recomputed from publicKey and hash, which fails if the signature is invalid.
+func ParseDER(publicKey curve.Point, hash, data []byte) (*Signature, error) {
+	var der derSignature
+	rest, err := asn1.Unmarshal(data, &der)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("ecdsa: %w", err)
 	}
+	if len(rest) != 0 {
+		return nil, errors.New("ecdsa: trailing data after DER signature")
+	}
+	if der.R.Sign() <= 0 || der.S.Sign() <= 0 || der.R.BitLen() > 256 || der.S.BitLen() > 256 {
+		return nil, errors.New("ecdsa: DER signature out of range")
+	}
+	// reject non canonical encodings, such as padded integers
+	if canonical, _ := asn1.Marshal(der); string(canonical) != string(data) {
+		return nil, errors.New("ecdsa: signature is not strict DER")
+	}
+	return fromRS(publicKey, hash, der.R.FillBytes(make([]byte, 32)), der.S.FillBytes(make([]byte, 32)))
+}
 
-	sb, err := sig.S.MarshalBinary()
-
-	if err != nil {
-		return nil, err
+// fromRS returns the signature (R, s) of hash by publicKey, where R = s⁻¹(m⋅G + r⋅X) must have x coordinate r.
+func fromRS(publicKey curve.Point, hash, rBytes, sBytes []byte) (*Signature, error) {
+	group := publicKey.Curve()
+	r, s := group.NewScalar(), group.NewScalar()
+	if err := r.UnmarshalBinary(rBytes); err != nil {
+		return nil, fmt.Errorf("ecdsa: r: %w", err)
+	}
+	if err := s.UnmarshalBinary(sBytes); err != nil {
+		return nil, fmt.Errorf("ecdsa: s: %w", err)
+	}
+	if r.IsZero() || s.IsZero() {
+		return nil, errors.New("ecdsa: invalid signature")
+	}
+	m := curve.FromHash(group, hash)
+	sInv := group.NewScalar().Set(s).Invert()
+	R := sInv.Act(m.ActOnBase().Add(r.Act(publicKey)))
+	if R.IsIdentity() || !R.XScalar().Equal(r) {
+		return nil, errors.New("ecdsa: invalid signature")
 	}
+	return &Signature{R: R, S: s}, nil
+}
 
-	recoverId := sig.GetRecoverIdIntu()
+// ParseRecoverable decodes the 65 byte encoding r ‖ s ‖ v of a secp256k1 signature, where v ∈ {0, 1, 2, 3}.
+//
+// R is recovered from r and v, so neither the public key nor the hash is needed.
+func ParseRecoverable(data []byte) (*Signature, error) {
+	if len(data) != 65 {
+		return nil, fmt.Errorf("ecdsa: invalid length for recoverable signature: %d", len(data))
+	}
+	recid := data[64]
+	if recid > 3 {
+		return nil, fmt.Errorf("ecdsa: invalid recovery id: %d", recid)
+	}
+	group := curve.Secp256k1{}
+	r, s := group.NewScalar(), group.NewScalar()
+	if err := r.UnmarshalBinary(data[:32]); err != nil {
+		return nil, fmt.Errorf("ecdsa: r: %w", err)
+	}
+	if err := s.UnmarshalBinary(data[32:64]); err != nil {
+		return nil, fmt.Errorf("ecdsa: s: %w", err)
+	}
+	if r.IsZero() || s.IsZero() {
+		return nil, errors.New("ecdsa: invalid signature")
+	}
 
-	sigbytes := append(rb[1:], sb...)
-	sigbytes = append(sigbytes, recoverId)
-	return sigbytes, nil
+	// x = r, or r + n if x overflowed the group order
+	x := new(big.Int).SetBytes(data[:32])
+	if recid&2 != 0 {
+		x.Add(x, group.Order().Big())
+		if x.BitLen() > 256 {
+			return nil, errors.New("ecdsa: invalid recovery id")
+		}
+	}
+	compressed := make([]byte, 33)
+	compressed[0] = 2 + recid&1
+	x.FillBytes(compressed[1:])
+	R := group.NewPoint()
+	if err := R.UnmarshalBinary(compressed); err != nil {
+		return nil, fmt.Errorf("ecdsa: invalid recovery id: %w", err)
+	}
+	return &Signature{R: R, S: s}, nil
 }
 
-func (sig Signature) GetRecoverIdIntu() byte {
-	toECDSA := sig.R.ToECDSA()
-	stringY := fmt.Sprint(toECDSA.Y)
-	a := strings.Split(stringY, "")
-	s := a[len(a)-1]
-	i, _ := strconv.Atoi(s)
-	finalV := i % 2
-	return byte(finalV)
+// RecoverPublicKey returns the secp256k1 public key X = r⁻¹(s⋅R - m⋅G) which produced the 65 byte recoverable
+// signature sig of hash, as encoded by SerializeRecoverable.
+//
+// This is equivalent to go-ethereum's crypto.SigToPub.
+func RecoverPublicKey(hash, sig []byte) (curve.Point, error) {
+	signature, err := ParseRecoverable(sig)
+	if err != nil {
+		return nil, err
+	}
+	group := curve.Secp256k1{}
+	m := curve.FromHash(group, hash)
+	rInv := signature.R.XScalar().Invert()
+	X := rInv.Act(signature.S.Act(signature.R).Sub(m.ActOnBase()))
+	if X.IsIdentity() {
+		return nil, errors.New("ecdsa: invalid signature")
+	}
+	return X, nil
 }
 
 // Verify is a custom signature format using curve data.
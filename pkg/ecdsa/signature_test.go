@@ -1,9 +1,14 @@
 package ecdsa
 
 import (
+	stdecdsa "crypto/ecdsa"
 	"crypto/rand"
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/math/sample"
 )
@@ -36,3 +41,194 @@ func TestSignature_Verify(t *testing.T) {
 		t.Error("verify failed")
 	}
 }
+
+func TestSignatureCodecs(t *testing.T) {
+	group := curve.Secp256k1{}
+	for i := 0; i < 32; i++ {
+		x := sample.Scalar(rand.Reader, group)
+		X := x.ActOnBase()
+		hash := make([]byte, 32)
+		_, _ = rand.Read(hash)
+		sig := NewSignature(x, hash, nil)
+		before, err := sig.SerializeCompact()
+		require.NoError(t, err)
+
+		normalized := sig.Normalize()
+		assert.False(t, normalized.S.IsOverHalfOrder())
+		assert.True(t, normalized.Verify(X, hash))
+		after, err := sig.SerializeCompact()
+		require.NoError(t, err)
+		assert.Equal(t, before, after, "Normalize should not modify the signature")
+
+		// recoverable, as understood by go-ethereum
+		rs, err := normalized.SerializeRecoverable()
+		require.NoError(t, err)
+		require.Len(t, rs, 65)
+		recovered, err := crypto.SigToPub(hash, rs)
+		require.NoError(t, err)
+		assert.Equal(t, X.ToAddress(), crypto.PubkeyToAddress(*recovered))
+		recoveredX, err := RecoverPublicKey(hash, rs)
+		require.NoError(t, err)
+		assert.True(t, recoveredX.Equal(X))
+		parsed, err := ParseRecoverable(rs)
+		require.NoError(t, err)
+		assert.True(t, parsed.R.Equal(normalized.R))
+		assert.True(t, parsed.S.Equal(normalized.S))
+
+		// the recovery id of a high s signature is the one of sig itself
+		rs, err = sig.SerializeRecoverable()
+		require.NoError(t, err)
+		recoveredX, err = RecoverPublicKey(hash, rs)
+		require.NoError(t, err)
+		assert.True(t, recoveredX.Equal(X))
+
+		// go-ethereum signatures are parsed and re-encoded unchanged
+		xBytes, err := x.MarshalBinary()
+		require.NoError(t, err)
+		privateKey, err := crypto.ToECDSA(xBytes)
+		require.NoError(t, err)
+		ethSig, err := crypto.Sign(hash, privateKey)
+		require.NoError(t, err)
+		parsed, err = ParseRecoverable(ethSig)
+		require.NoError(t, err)
+		assert.True(t, parsed.Verify(X, hash))
+		rs, err = parsed.SerializeRecoverable()
+		require.NoError(t, err)
+		assert.Equal(t, ethSig, rs)
+
+		compact, err := sig.SerializeCompact()
+		require.NoError(t, err)
+		require.Len(t, compact, 64)
+		parsed, err = ParseCompact(X, hash, compact)
+		require.NoError(t, err)
+		assert.True(t, parsed.R.Equal(sig.R))
+
+		der, err := sig.SerializeDER()
+		require.NoError(t, err)
+		parsed, err = ParseDER(X, hash, der)
+		require.NoError(t, err)
+		assert.True(t, parsed.R.Equal(sig.R))
+		assert.True(t, parsed.S.Equal(sig.S))
+
+		// signatures do not parse for another hash
+		_, err = ParseCompact(X, append([]byte{hash[0] ^ 1}, hash[1:]...), compact)
+		assert.Error(t, err)
+	}
+}
+
+func TestSignatureDERP256(t *testing.T) {
+	group := curve.P256{}
+	x := sample.Scalar(rand.Reader, group)
+	X := x.ActOnBase()
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	sig := NewSignature(x, hash, nil)
+
+	der, err := sig.SerializeDER()
+	require.NoError(t, err)
+	assert.True(t, stdecdsa.VerifyASN1(X.ToECDSA(), hash, der))
+	parsed, err := ParseDER(X, hash, der)
+	require.NoError(t, err)
+	assert.True(t, parsed.R.Equal(sig.R))
+
+	// padded integers are not strict DER
+	padded := append([]byte{0x30, der[1] + 1, 0x02, der[3] + 1, 0}, der[4:]...)
+	_, err = ParseDER(X, hash, padded)
+	assert.Error(t, err)
+	_, err = ParseDER(X, hash, append(der, 0))
+	assert.Error(t, err)
+}
+
+func TestRecoveryIdOverflow(t *testing.T) {
+	group := curve.Secp256k1{}
+	order := group.Order().Big()
+
+	// find a point R whose x coordinate overflows the group order, which happens with probability 2⁻¹²⁸ when signing
+	var R curve.Point
+	for i := int64(1); R == nil; i++ {
+		x := new(big.Int).Add(order, big.NewInt(i))
+		compressed := make([]byte, 33)
+		compressed[0] = 2
+		x.FillBytes(compressed[1:])
+		point := group.NewPoint()
+		if point.UnmarshalBinary(compressed) == nil {
+			R = point
+		}
+	}
+	// any (R, s) is a valid signature of hash for X = r⁻¹(s⋅R - m⋅G)
+	s := sample.Scalar(rand.Reader, group)
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	m := curve.FromHash(group, hash)
+	X := R.XScalar().Invert().Act(s.Act(R).Sub(m.ActOnBase()))
+	sig := Signature{R: R, S: s}.Normalize()
+	require.True(t, sig.Verify(X, hash))
+
+	assert.Equal(t, byte(2), sig.RecoveryId()&2)
+	rs, err := sig.SerializeRecoverable()
+	require.NoError(t, err)
+	recovered, err := crypto.SigToPub(hash, rs)
+	require.NoError(t, err)
+	assert.Equal(t, X.ToAddress(), crypto.PubkeyToAddress(*recovered))
+	recoveredX, err := RecoverPublicKey(hash, rs)
+	require.NoError(t, err)
+	assert.True(t, recoveredX.Equal(X))
+
+	// without the overflow bit, a different key is recovered
+	rs[64] &= 1
+	recoveredX, err = RecoverPublicKey(hash, rs)
+	if err == nil {
+		assert.False(t, recoveredX.Equal(X))
+	}
+}
+
+func TestParseRecoverableErrors(t *testing.T) {
+	group := curve.Secp256k1{}
+	x := sample.Scalar(rand.Reader, group)
+	hash := make([]byte, 32)
+	rs, err := NewSignature(x, hash, nil).SerializeRecoverable()
+	require.NoError(t, err)
+
+	_, err = ParseRecoverable(rs[:64])
+	assert.Error(t, err)
+	invalid := append([]byte{}, rs...)
+	invalid[64] = 4
+	_, err = ParseRecoverable(invalid)
+	assert.Error(t, err)
+	invalid = append(make([]byte, 32), rs[32:]...)
+	_, err = ParseRecoverable(invalid)
+	assert.Error(t, err, "r must not be zero")
+	_, err = RecoverPublicKey(hash, rs[1:])
+	assert.Error(t, err)
+}
+
+func TestRecoveryIdHighS(t *testing.T) {
+	group := curve.Secp256k1{}
+	x := sample.Scalar(rand.Reader, group)
+	X := x.ActOnBase()
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	sig := *NewSignature(x, hash, nil)
+	if !sig.S.IsOverHalfOrder() {
+		sig = Signature{R: sig.R.Negate(), S: group.NewScalar().Set(sig.S).Negate()}
+	}
+	require.True(t, sig.Verify(X, hash))
+	require.True(t, sig.S.IsOverHalfOrder())
+
+	// RecoveryId is the id of the low-S form, which Ethereum expects
+	assert.Equal(t, sig.Normalize().RawRecoveryId(), sig.RecoveryId())
+	assert.Equal(t, sig.RawRecoveryId()^1, sig.RecoveryId())
+
+	normalized, err := sig.Normalize().SerializeRecoverable()
+	require.NoError(t, err)
+	assert.Equal(t, sig.RecoveryId(), normalized[64])
+	recovered, err := crypto.SigToPub(hash, normalized)
+	require.NoError(t, err)
+	assert.Equal(t, X.ToAddress(), crypto.PubkeyToAddress(*recovered))
+	for _, encode := range []func() ([]byte, error){sig.SigEthereum, sig.ToEthBytes} {
+		encoded, err := encode()
+		require.NoError(t, err)
+		assert.Equal(t, normalized, encoded)
+	}
+	assert.Equal(t, sig.RawRecoveryId()&1, sig.GetRecoverIdIntu())
+}
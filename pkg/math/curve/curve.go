@@ -138,9 +138,12 @@ type Point interface {
 	//
 	// This is used in ECDSA, but isn't available on every curve, necessarily.
 	//
-	// If you choose not to implement this method, simply return nil.
+	// If you choose not to implement this method, panic, so that ECDSA is never used with this curve by mistake.
 	XScalar() Scalar
 
+	// ToAddress returns the Ethereum address of this Point.
+	//
+	// This is only defined for secp256k1, other curves panic.
 	ToAddress() common.Address
 
 	ToECDSA() *ecdsa.PublicKey
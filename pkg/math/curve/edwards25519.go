@@ -0,0 +1,294 @@
+package curve
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/cronokirby/saferith"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Edwards25519 is the prime order subgroup of the twisted Edwards curve used by Ed25519.
+//
+// Points are encoded as in RFC 8032, so that the encoding of a public key Y = x⋅G
+// is an Ed25519 public key.
+type Edwards25519 struct{}
+
+var edwards25519OrderNat, _ = new(saferith.Nat).SetHex("1000000000000000000000000000000014DEF9DEA2F79CD65812631A5CF5D3ED")
+var edwards25519Order = saferith.ModulusFromNat(edwards25519OrderNat)
+
+// edwards25519HalfOrder = (ℓ-1)/2
+var edwards25519HalfOrder = new(saferith.Nat).Rsh(edwards25519OrderNat, 1, -1)
+
+// edwards25519OrderMinusOne = ℓ-1, used to check that points are in the prime order subgroup.
+var edwards25519OrderMinusOne = func() *edwards25519.Scalar {
+	data := new(saferith.Nat).Sub(edwards25519OrderNat, new(saferith.Nat).SetUint64(1), -1).Bytes()
+	s, err := new(edwards25519.Scalar).SetCanonicalBytes(reverse(data))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}()
+
+func (Edwards25519) NewPoint() Point {
+	out := new(Edwards25519Point)
+	out.value.Set(edwards25519.NewIdentityPoint())
+	return out
+}
+
+func (Edwards25519) NewBasePoint() Point {
+	out := new(Edwards25519Point)
+	out.value.Set(edwards25519.NewGeneratorPoint())
+	return out
+}
+
+func (Edwards25519) NewScalar() Scalar {
+	out := new(Edwards25519Scalar)
+	out.value.Set(edwards25519.NewScalar())
+	return out
+}
+
+func (Edwards25519) ScalarBits() int {
+	return 253
+}
+
+func (Edwards25519) SafeScalarBytes() int {
+	return 64
+}
+
+func (Edwards25519) Order() *saferith.Modulus {
+	return edwards25519Order
+}
+
+func (Edwards25519) Name() string {
+	return "edwards25519"
+}
+
+// reverse returns a reversed copy of data, to convert between big and little endian.
+func reverse(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i := range data {
+		out[len(data)-1-i] = data[i]
+	}
+	return out
+}
+
+type Edwards25519Scalar struct {
+	value edwards25519.Scalar
+}
+
+func edwards25519CastScalar(generic Scalar) *Edwards25519Scalar {
+	out, ok := generic.(*Edwards25519Scalar)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to edwards25519Scalar: %v", generic))
+	}
+	return out
+}
+
+func (*Edwards25519Scalar) Curve() Curve {
+	return Edwards25519{}
+}
+
+// MarshalBinary returns the 32 byte big endian encoding of the scalar, as required by Scalar.
+//
+// Use Bytes for the little endian encoding of RFC 8032.
+func (s *Edwards25519Scalar) MarshalBinary() ([]byte, error) {
+	return reverse(s.value.Bytes()), nil
+}
+
+func (s *Edwards25519Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for edwards25519 scalar: %d", len(data))
+	}
+	if _, err := s.value.SetCanonicalBytes(reverse(data)); err != nil {
+		return errors.New("invalid bytes for edwards25519 scalar")
+	}
+	return nil
+}
+
+// Bytes returns the 32 byte little endian encoding of the scalar, as used in RFC 8032.
+func (s *Edwards25519Scalar) Bytes() []byte {
+	return s.value.Bytes()
+}
+
+// SetUniformBytes sets the scalar to a 64 byte little endian value reduced modulo ℓ,
+// as done with the SHA-512 outputs of RFC 8032.
+func (s *Edwards25519Scalar) SetUniformBytes(data []byte) (*Edwards25519Scalar, error) {
+	if _, err := s.value.SetUniformBytes(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Edwards25519Scalar) Add(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Add(&s.value, &other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Sub(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Subtract(&s.value, &other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Mul(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Multiply(&s.value, &other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) IsOverHalfOrder() bool {
+	_, _, gt := new(saferith.Nat).SetBytes(reverse(s.value.Bytes())).Cmp(edwards25519HalfOrder)
+	return gt == 1
+}
+
+func (s *Edwards25519Scalar) Invert() Scalar {
+	s.value.Invert(&s.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Negate() Scalar {
+	s.value.Negate(&s.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) Equal(that Scalar) bool {
+	other := edwards25519CastScalar(that)
+
+	return s.value.Equal(&other.value) == 1
+}
+
+func (s *Edwards25519Scalar) IsZero() bool {
+	return s.value.Equal(edwards25519.NewScalar()) == 1
+}
+
+func (s *Edwards25519Scalar) Set(that Scalar) Scalar {
+	other := edwards25519CastScalar(that)
+
+	s.value.Set(&other.value)
+	return s
+}
+
+func (s *Edwards25519Scalar) SetNat(x *saferith.Nat) Scalar {
+	reduced := new(saferith.Nat).Mod(x, edwards25519Order)
+	data := make([]byte, 32)
+	reduced.FillBytes(data)
+	if _, err := s.value.SetCanonicalBytes(reverse(data)); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *Edwards25519Scalar) Act(that Point) Point {
+	other := edwards25519CastPoint(that)
+	out := new(Edwards25519Point)
+	out.value.ScalarMult(&s.value, &other.value)
+	return out
+}
+
+func (s *Edwards25519Scalar) ActOnBase() Point {
+	out := new(Edwards25519Point)
+	out.value.ScalarBaseMult(&s.value)
+	return out
+}
+
+type Edwards25519Point struct {
+	value edwards25519.Point
+}
+
+func edwards25519CastPoint(generic Point) *Edwards25519Point {
+	out, ok := generic.(*Edwards25519Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to edwards25519Point: %v", generic))
+	}
+	return out
+}
+
+// ToAddress is not defined for Edwards25519, and panics.
+func (*Edwards25519Point) ToAddress() common.Address {
+	panic("curve: ToAddress is not defined for Edwards25519")
+}
+
+// ToECDSA is not defined for Edwards25519, and returns nil.
+func (*Edwards25519Point) ToECDSA() *ecdsa.PublicKey {
+	return nil
+}
+
+func (*Edwards25519Point) Curve() Curve {
+	return Edwards25519{}
+}
+
+// MarshalBinary returns the 32 byte encoding of the point, as defined in RFC 8032.
+func (p *Edwards25519Point) MarshalBinary() ([]byte, error) {
+	return p.value.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a point in the encoding of RFC 8032.
+//
+// Points outside of the prime order subgroup are rejected.
+func (p *Edwards25519Point) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for edwards25519Point: %d", len(data))
+	}
+	var value edwards25519.Point
+	if _, err := value.SetBytes(data); err != nil {
+		return fmt.Errorf("edwards25519Point.UnmarshalBinary: %w", err)
+	}
+	// (ℓ-1)⋅P + P = ℓ⋅P is the identity if and only if P is in the prime order subgroup.
+	check := new(edwards25519.Point).ScalarMult(edwards25519OrderMinusOne, &value)
+	if check.Add(check, &value).Equal(edwards25519.NewIdentityPoint()) != 1 {
+		return errors.New("edwards25519Point.UnmarshalBinary: point is not in the prime order subgroup")
+	}
+	p.value.Set(&value)
+	return nil
+}
+
+func (p *Edwards25519Point) Add(that Point) Point {
+	other := edwards25519CastPoint(that)
+
+	out := new(Edwards25519Point)
+	out.value.Add(&p.value, &other.value)
+	return out
+}
+
+func (p *Edwards25519Point) Sub(that Point) Point {
+	other := edwards25519CastPoint(that)
+
+	out := new(Edwards25519Point)
+	out.value.Subtract(&p.value, &other.value)
+	return out
+}
+
+func (p *Edwards25519Point) Set(that Point) Point {
+	other := edwards25519CastPoint(that)
+
+	p.value.Set(&other.value)
+	return p
+}
+
+func (p *Edwards25519Point) Negate() Point {
+	out := new(Edwards25519Point)
+	out.value.Negate(&p.value)
+	return out
+}
+
+func (p *Edwards25519Point) Equal(that Point) bool {
+	other := edwards25519CastPoint(that)
+
+	return p.value.Equal(&other.value) == 1
+}
+
+func (p *Edwards25519Point) IsIdentity() bool {
+	return p == nil || p.value.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// XScalar is not used with Edwards25519, and panics.
+func (*Edwards25519Point) XScalar() Scalar {
+	panic("curve: XScalar is not defined for Edwards25519")
+}
@@ -0,0 +1,68 @@
+package curve
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdwards25519PublicKey(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	// RFC 8032: the secret scalar is the clamped first half of SHA-512(seed), in little endian
+	digest := sha512.Sum512(privateKey.Seed())
+	digest[0] &= 248
+	digest[31] &= 127
+	digest[31] |= 64
+	s := Edwards25519{}.NewScalar().SetNat(new(saferith.Nat).SetBytes(reverse(digest[:32])))
+
+	data, err := s.ActOnBase().MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte(publicKey), data)
+
+	P := Edwards25519{}.NewPoint()
+	require.NoError(t, P.UnmarshalBinary(publicKey))
+	assert.True(t, P.Equal(s.ActOnBase()))
+}
+
+func TestEdwards25519Marshal(t *testing.T) {
+	group := Edwards25519{}
+	s := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1234))
+
+	data, err := s.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0xd2}, data[30:], "scalars should be big endian")
+	s2 := group.NewScalar()
+	require.NoError(t, s2.UnmarshalBinary(data))
+	assert.True(t, s.Equal(s2))
+
+	orderBytes := group.Order().Bytes()
+	assert.Error(t, s2.UnmarshalBinary(orderBytes), "non canonical scalars should be rejected")
+
+	identity, err := group.NewPoint().MarshalBinary()
+	require.NoError(t, err)
+	P := group.NewPoint()
+	require.NoError(t, P.UnmarshalBinary(identity))
+	assert.True(t, P.IsIdentity())
+
+	// (0, -1) has order 2
+	smallOrder := make([]byte, 32)
+	smallOrder[0] = 0xec
+	for i := 1; i < 31; i++ {
+		smallOrder[i] = 0xff
+	}
+	smallOrder[31] = 0x7f
+	assert.Error(t, P.UnmarshalBinary(smallOrder), "points of small order should be rejected")
+}
+
+func TestEdwards25519NoECDSA(t *testing.T) {
+	P := Edwards25519{}.NewBasePoint()
+	assert.Panics(t, func() { P.ToAddress() }, "Edwards25519 has no Ethereum address")
+	assert.Panics(t, func() { P.XScalar() }, "Edwards25519 is not used with ECDSA")
+}
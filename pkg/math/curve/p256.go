@@ -0,0 +1,296 @@
+package curve
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cronokirby/saferith"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// P256 is the NIST P-256 curve, also known as secp256r1 or prime256v1.
+//
+// Point operations use crypto/elliptic, which is constant time for P-256,
+// and scalar operations use saferith.
+type P256 struct{}
+
+var p256OrderNat = new(saferith.Nat).SetBig(elliptic.P256().Params().N, 256)
+var p256Order = saferith.ModulusFromNat(p256OrderNat)
+
+// p256HalfOrder = (n-1)/2
+var p256HalfOrder = new(saferith.Nat).Rsh(p256OrderNat, 1, -1)
+
+func (P256) NewPoint() Point {
+	return new(P256Point)
+}
+
+func (P256) NewBasePoint() Point {
+	params := elliptic.P256().Params()
+	return &P256Point{x: params.Gx, y: params.Gy}
+}
+
+func (P256) NewScalar() Scalar {
+	out := new(P256Scalar)
+	out.value.Resize(256)
+	return out
+}
+
+func (P256) ScalarBits() int {
+	return 256
+}
+
+func (P256) SafeScalarBytes() int {
+	return 64
+}
+
+func (P256) Order() *saferith.Modulus {
+	return p256Order
+}
+
+func (P256) Name() string {
+	return "p256"
+}
+
+type P256Scalar struct {
+	value saferith.Nat
+}
+
+func p256CastScalar(generic Scalar) *P256Scalar {
+	out, ok := generic.(*P256Scalar)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to p256Scalar: %v", generic))
+	}
+	return out
+}
+
+func (*P256Scalar) Curve() Curve {
+	return P256{}
+}
+
+func (s *P256Scalar) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 32)
+	s.value.FillBytes(data)
+	return data, nil
+}
+
+func (s *P256Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 32 {
+		return fmt.Errorf("invalid length for p256 scalar: %d", len(data))
+	}
+	var value saferith.Nat
+	value.SetBytes(data)
+	if _, _, lt := value.CmpMod(p256Order); lt != 1 {
+		return errors.New("invalid bytes for p256 scalar")
+	}
+	s.value.SetNat(&value)
+	return nil
+}
+
+func (s *P256Scalar) Add(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.ModAdd(&s.value, &other.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Sub(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.ModSub(&s.value, &other.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Mul(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.ModMul(&s.value, &other.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) IsOverHalfOrder() bool {
+	_, _, gt := s.value.Cmp(p256HalfOrder)
+	return gt == 1
+}
+
+func (s *P256Scalar) Invert() Scalar {
+	s.value.ModInverse(&s.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Negate() Scalar {
+	s.value.ModNeg(&s.value, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Equal(that Scalar) bool {
+	other := p256CastScalar(that)
+
+	return s.value.Eq(&other.value) == 1
+}
+
+func (s *P256Scalar) IsZero() bool {
+	return s.value.EqZero() == 1
+}
+
+func (s *P256Scalar) Set(that Scalar) Scalar {
+	other := p256CastScalar(that)
+
+	s.value.SetNat(&other.value)
+	return s
+}
+
+func (s *P256Scalar) SetNat(x *saferith.Nat) Scalar {
+	s.value.Mod(x, p256Order)
+	return s
+}
+
+func (s *P256Scalar) Act(that Point) Point {
+	other := p256CastPoint(that)
+	if other.IsIdentity() {
+		return new(P256Point)
+	}
+	data, _ := s.MarshalBinary()
+	x, y := elliptic.P256().ScalarMult(other.x, other.y, data)
+	return newP256Point(x, y)
+}
+
+func (s *P256Scalar) ActOnBase() Point {
+	data, _ := s.MarshalBinary()
+	x, y := elliptic.P256().ScalarBaseMult(data)
+	return newP256Point(x, y)
+}
+
+// P256Point is a point on P-256 in affine coordinates.
+//
+// The identity is represented by nil coordinates.
+type P256Point struct {
+	x, y *big.Int
+}
+
+// newP256Point converts the output of crypto/elliptic, which uses (0, 0) for the identity.
+func newP256Point(x, y *big.Int) *P256Point {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return new(P256Point)
+	}
+	return &P256Point{x: x, y: y}
+}
+
+func p256CastPoint(generic Point) *P256Point {
+	out, ok := generic.(*P256Point)
+	if !ok {
+		panic(fmt.Sprintf("failed to convert to p256Point: %v", generic))
+	}
+	return out
+}
+
+// ToAddress is specific to Ethereum, and panics for P256.
+func (*P256Point) ToAddress() common.Address {
+	panic("curve: ToAddress is not defined for P256")
+}
+
+// ToECDSA returns the point as a P-256 public key, or nil for the identity.
+func (p *P256Point) ToECDSA() *ecdsa.PublicKey {
+	if p.IsIdentity() {
+		return nil
+	}
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).Set(p.x),
+		Y:     new(big.Int).Set(p.y),
+	}
+}
+
+func (*P256Point) Curve() Curve {
+	return P256{}
+}
+
+// MarshalBinary returns the 33 byte compressed SEC1 encoding of the point.
+//
+// The identity is encoded as a single 0 byte.
+func (p *P256Point) MarshalBinary() ([]byte, error) {
+	if p.IsIdentity() {
+		return []byte{0}, nil
+	}
+	return elliptic.MarshalCompressed(elliptic.P256(), p.x, p.y), nil
+}
+
+// UnmarshalBinary decodes a point in compressed or uncompressed SEC1 encoding.
+func (p *P256Point) UnmarshalBinary(data []byte) error {
+	var x, y *big.Int
+	switch len(data) {
+	case 1:
+		if data[0] != 0 {
+			return errors.New("p256Point.UnmarshalBinary: invalid encoding of the identity")
+		}
+		p.x, p.y = nil, nil
+		return nil
+	case 33:
+		x, y = elliptic.UnmarshalCompressed(elliptic.P256(), data)
+	case 65:
+		x, y = elliptic.Unmarshal(elliptic.P256(), data)
+	default:
+		return fmt.Errorf("invalid length for p256Point: %d", len(data))
+	}
+	if x == nil {
+		return errors.New("p256Point.UnmarshalBinary: point not on curve")
+	}
+	p.x, p.y = x, y
+	return nil
+}
+
+func (p *P256Point) Add(that Point) Point {
+	other := p256CastPoint(that)
+
+	if p.IsIdentity() {
+		return &P256Point{x: other.x, y: other.y}
+	}
+	if other.IsIdentity() {
+		return &P256Point{x: p.x, y: p.y}
+	}
+	return newP256Point(elliptic.P256().Add(p.x, p.y, other.x, other.y))
+}
+
+func (p *P256Point) Sub(that Point) Point {
+	return p.Add(that.Negate())
+}
+
+func (p *P256Point) Set(that Point) Point {
+	other := p256CastPoint(that)
+
+	p.x, p.y = other.x, other.y
+	return p
+}
+
+func (p *P256Point) Negate() Point {
+	if p.IsIdentity() {
+		return new(P256Point)
+	}
+	y := new(big.Int).Sub(elliptic.P256().Params().P, p.y)
+	return &P256Point{x: p.x, y: y}
+}
+
+func (p *P256Point) Equal(that Point) bool {
+	other := p256CastPoint(that)
+
+	if p.IsIdentity() || other.IsIdentity() {
+		return p.IsIdentity() && other.IsIdentity()
+	}
+	return p.x.Cmp(other.x) == 0 && p.y.Cmp(other.y) == 0
+}
+
+func (p *P256Point) IsIdentity() bool {
+	return p == nil || p.x == nil
+}
+
+// XScalar returns the x coordinate of the point reduced modulo the order, as used in ECDSA.
+func (p *P256Point) XScalar() Scalar {
+	out := new(P256Scalar)
+	if p.IsIdentity() {
+		return out
+	}
+	out.SetNat(new(saferith.Nat).SetBig(p.x, 256))
+	return out
+}
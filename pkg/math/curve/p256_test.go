@@ -0,0 +1,73 @@
+package curve
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/cronokirby/saferith"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestP256ECDSA(t *testing.T) {
+	group := P256{}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	x := group.NewScalar().SetNat(new(saferith.Nat).SetBig(key.D, 256))
+	X := x.ActOnBase()
+	assert.True(t, X.Equal(&P256Point{x: key.X, y: key.Y}))
+	assert.Equal(t, key.PublicKey, *X.ToECDSA())
+
+	// verify a signature from crypto/ecdsa using our arithmetic
+	digest := sha256.Sum256([]byte("hello"))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	m := FromHash(group, digest[:])
+	rScalar := group.NewScalar().SetNat(new(saferith.Nat).SetBig(r, 256))
+	sInv := group.NewScalar().SetNat(new(saferith.Nat).SetBig(s, 256)).Invert()
+	R := sInv.Act(m.ActOnBase().Add(rScalar.Act(X)))
+	assert.True(t, R.XScalar().Equal(rScalar))
+
+	assert.Panics(t, func() { X.ToAddress() }, "P256 has no Ethereum address")
+}
+
+func TestP256Marshal(t *testing.T) {
+	group := P256{}
+	s := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1234))
+	S := s.ActOnBase()
+
+	data, err := s.MarshalBinary()
+	require.NoError(t, err)
+	s2 := group.NewScalar()
+	require.NoError(t, s2.UnmarshalBinary(data))
+	assert.True(t, s.Equal(s2))
+	assert.Error(t, s2.UnmarshalBinary(group.Order().Bytes()), "non canonical scalars should be rejected")
+
+	data, err = S.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, 33)
+	S2 := group.NewPoint()
+	require.NoError(t, S2.UnmarshalBinary(data))
+	assert.True(t, S.Equal(S2))
+
+	ecdsaKey := S.ToECDSA()
+	require.NoError(t, S2.UnmarshalBinary(elliptic.Marshal(elliptic.P256(), ecdsaKey.X, ecdsaKey.Y)))
+	assert.True(t, S.Equal(S2), "uncompressed points should be accepted")
+
+	for i := 1; i < len(data); i++ {
+		data[i] = 0xff
+	}
+	assert.Error(t, S2.UnmarshalBinary(data), "x coordinates out of range should be rejected")
+
+	identity := group.NewPoint()
+	data, err = identity.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, S2.UnmarshalBinary(data))
+	assert.True(t, S2.IsIdentity())
+	assert.True(t, S.Add(S.Negate()).IsIdentity())
+	assert.True(t, S.Sub(identity).Equal(S))
+}
@@ -72,3 +72,32 @@ func lagrange(group curve.Curve, interpolationDomain map[party.ID]curve.Scalar,
 	lJ.Mul(numerator)
 	return lJ
 }
+
+// LagrangeAt returns the Lagrange coefficients at x for all parties in the interpolation domain.
+//
+// That is, for a polynomial f of degree < len(interpolationDomain),
+// f(x) = ∑ⱼ lⱼ(x)⋅f(xⱼ), where
+//
+//	lⱼ(x) = ∏ᵢ≠ⱼ (x - xᵢ)/(xⱼ - xᵢ).
+func LagrangeAt(group curve.Curve, interpolationDomain []party.ID, x curve.Scalar) map[party.ID]curve.Scalar {
+	scalars, _ := getScalarsAndNumerator(group, interpolationDomain)
+	tmp := group.NewScalar()
+	coefficients := make(map[party.ID]curve.Scalar, len(interpolationDomain))
+	for j, xJ := range scalars {
+		numerator := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+		denominator := group.NewScalar().SetNat(new(saferith.Nat).SetUint64(1))
+		for i, xI := range scalars {
+			if i == j {
+				continue
+			}
+			// numerator *= x - xᵢ
+			tmp.Set(xI).Negate().Add(x)
+			numerator.Mul(tmp)
+			// denominator *= xⱼ - xᵢ
+			tmp.Set(xI).Negate().Add(xJ)
+			denominator.Mul(tmp)
+		}
+		coefficients[j] = denominator.Invert().Mul(numerator)
+	}
+	return coefficients
+}
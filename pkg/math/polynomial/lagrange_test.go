@@ -1,12 +1,14 @@
 package polynomial_test
 
 import (
+	"crypto/rand"
 	"testing"
 
 	"github.com/cronokirby/saferith"
 	"github.com/stretchr/testify/assert"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
 	"github.com/w3-key/mps-lean/pkg/test"
 )
 
@@ -29,3 +31,27 @@ func TestLagrange(t *testing.T) {
 	assert.True(t, sumEven.Equal(one))
 	assert.True(t, sumOdd.Equal(one))
 }
+
+func TestLagrangeAt(t *testing.T) {
+	group := curve.Secp256k1{}
+
+	N := 5
+	allIDs := test.PartyIDs(N + 1)
+	domain, target := allIDs[:N], allIDs[N]
+	secret := sample.Scalar(rand.Reader, group)
+	poly := polynomial.NewPolynomial(rand.Reader, group, N-1, secret)
+
+	x := target.Scalar(group)
+	coefs := polynomial.LagrangeAt(group, domain, x)
+	result := group.NewScalar()
+	for _, j := range domain {
+		result.Add(group.NewScalar().Set(coefs[j]).Mul(poly.Evaluate(j.Scalar(group))))
+	}
+	assert.True(t, result.Equal(poly.Evaluate(x)))
+
+	// at 0, the coefficients are the same as Lagrange
+	coefsZero := polynomial.LagrangeAt(group, domain, group.NewScalar())
+	for j, c := range polynomial.Lagrange(group, domain) {
+		assert.True(t, c.Equal(coefsZero[j]))
+	}
+}
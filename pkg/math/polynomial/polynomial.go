@@ -1,7 +1,7 @@
 package polynomial
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/math/sample"
@@ -15,7 +15,9 @@ type Polynomial struct {
 
 // NewPolynomial generates a Polynomial f(X) = secret + a₁⋅X + … + aₜ⋅Xᵗ,
 // with coefficients in ℤₚ, and degree t.
-func NewPolynomial(group curve.Curve, degree int, constant curve.Scalar) *Polynomial {
+//
+// The coefficients a₁, …, aₜ are sampled from rand.
+func NewPolynomial(rand io.Reader, group curve.Curve, degree int, constant curve.Scalar) *Polynomial {
 	polynomial := &Polynomial{
 		group:        group,
 		coefficients: make([]curve.Scalar, degree+1),
@@ -28,7 +30,7 @@ func NewPolynomial(group curve.Curve, degree int, constant curve.Scalar) *Polyno
 	polynomial.coefficients[0] = constant
 
 	for i := 1; i <= degree; i++ {
-		polynomial.coefficients[i] = sample.Scalar(rand.Reader, group)
+		polynomial.coefficients[i] = sample.Scalar(rand, group)
 	}
 
 	return polynomial
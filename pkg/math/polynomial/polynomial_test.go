@@ -18,7 +18,7 @@ func TestPolynomial_Constant(t *testing.T) {
 
 	deg := 10
 	secret := sample.Scalar(rand.Reader, group)
-	poly := NewPolynomial(group, deg, secret)
+	poly := NewPolynomial(rand.Reader, group, deg, secret)
 	require.True(t, poly.Constant().Equal(secret))
 }
 
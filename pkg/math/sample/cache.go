@@ -0,0 +1,309 @@
+package sample
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/params"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// primeCacheAD is the additional data authenticated with the encrypted cache file.
+var primeCacheAD = []byte("mps-lean prime cache v1")
+
+// primeCache is the cache used by Paillier, if any.
+var primeCache atomic.Pointer[PrimeCache]
+
+// SetPrimeCache makes Paillier take its primes from c, so that Paillier keys generated during keygen and
+// refresh do not have to wait for new primes. Passing nil restores the default behavior.
+func SetPrimeCache(c *PrimeCache) {
+	primeCache.Store(c)
+}
+
+// PrimeCacheOptions configures a PrimeCache.
+type PrimeCacheOptions struct {
+	// Capacity is the maximum number of primes held by the cache.
+	Capacity int
+	// LowWaterMark is the number of primes below which the cache is refilled up to Capacity.
+	// It must be at most Capacity. If it is 0, half of Capacity, rounded up, is used.
+	LowWaterMark int
+	// Workers is the number of workers generating primes in the background.
+	// If Workers ⩽ 0, a single worker is used.
+	Workers int
+	// Path is the file in which the cache is persisted. If empty, the cache is only held in memory.
+	Path string
+	// Key is the 32 byte key used to encrypt the cache file. It is required if Path is set.
+	Key []byte
+}
+
+// PrimeCacheStats contains metrics about a PrimeCache.
+type PrimeCacheStats struct {
+	// Depth is the number of primes currently available.
+	Depth int
+	// Capacity and LowWaterMark are the values the cache was created with.
+	Capacity, LowWaterMark int
+	// Generated is the number of primes generated in the background.
+	Generated uint64
+	// Served is the number of primes taken from the cache.
+	Served uint64
+	// Misses is the number of primes which had to be generated on demand because the cache was empty.
+	Misses uint64
+}
+
+// PrimeCache generates safe Blum primes in the background, so that they are available when a Paillier key
+// is needed.
+//
+// Primes taken from the cache are removed from the file before they are returned, so that they are never
+// used twice, even if the process restarts.
+type PrimeCache struct {
+	opts PrimeCacheOptions
+	aead cipher.AEAD
+
+	// mtx protects everything below, as well as the cache file.
+	mtx    sync.Mutex
+	primes []*saferith.Nat
+	stats  PrimeCacheStats
+	err    error
+
+	wake chan struct{}
+	quit chan struct{}
+	done chan struct{}
+}
+
+// NewPrimeCache creates a PrimeCache, loading the primes persisted in opts.Path if it exists,
+// and starts filling it in the background.
+//
+// Close must be called to stop the background generation.
+func NewPrimeCache(opts PrimeCacheOptions) (*PrimeCache, error) {
+	if opts.Capacity <= 0 {
+		return nil, errors.New("prime cache: capacity must be positive")
+	}
+	if opts.LowWaterMark < 0 || opts.LowWaterMark > opts.Capacity {
+		return nil, fmt.Errorf("prime cache: low water mark %d is invalid for capacity %d", opts.LowWaterMark, opts.Capacity)
+	}
+	// with a low water mark of 0, the cache would never be refilled
+	if opts.LowWaterMark == 0 {
+		opts.LowWaterMark = (opts.Capacity + 1) / 2
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+
+	c := &PrimeCache{
+		opts: opts,
+		wake: make(chan struct{}, 1),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	c.stats.Capacity = opts.Capacity
+	c.stats.LowWaterMark = opts.LowWaterMark
+
+	if opts.Path != "" {
+		aead, err := chacha20poly1305.NewX(opts.Key)
+		if err != nil {
+			return nil, fmt.Errorf("prime cache: %w", err)
+		}
+		c.aead = aead
+		if c.primes, err = c.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	go c.fill()
+	c.refill()
+	return c, nil
+}
+
+// Stats returns the current metrics of the cache.
+func (c *PrimeCache) Stats() PrimeCacheStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	stats := c.stats
+	stats.Depth = len(c.primes)
+	return stats
+}
+
+// Err returns the last error which occurred while persisting the cache, if any.
+func (c *PrimeCache) Err() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.err
+}
+
+// Paillier returns two primes for a Paillier key pair, taken from the cache when available.
+// Missing primes are generated using rand and pl.
+func (c *PrimeCache) Paillier(rand io.Reader, pl *pool.Pool) (p, q *saferith.Nat) {
+	primes := c.take(2)
+	if missing := 2 - len(primes); missing > 0 {
+		primes = append(primes, blumPrimes(rand, pl, missing)...)
+	}
+	return primes[0], primes[1]
+}
+
+// take removes up to count primes from the cache.
+func (c *PrimeCache) take(count int) []*saferith.Nat {
+	defer c.refill()
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if count > len(c.primes) {
+		count = len(c.primes)
+	}
+	rest := len(c.primes) - count
+	primes := c.primes[rest:]
+	c.primes = c.primes[:rest:rest]
+
+	// if the primes are still on disk, they would be reused after a restart
+	if err := c.persist(); err != nil {
+		c.err = err
+		c.stats.Misses += 2
+		return nil
+	}
+	c.stats.Served += uint64(count)
+	c.stats.Misses += uint64(2 - count)
+	return primes
+}
+
+// refill wakes up the background worker if the cache is below its low water mark.
+func (c *PrimeCache) refill() {
+	c.mtx.Lock()
+	low := len(c.primes) < c.opts.LowWaterMark
+	c.mtx.Unlock()
+	if !low {
+		return
+	}
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// fill generates primes in the background whenever it is woken up, until the cache is full.
+func (c *PrimeCache) fill() {
+	defer close(c.done)
+	pl := pool.NewPool(c.opts.Workers)
+	defer pl.TearDown()
+	for {
+		select {
+		case <-c.quit:
+			return
+		case <-c.wake:
+		}
+		for {
+			c.mtx.Lock()
+			full := len(c.primes) >= c.opts.Capacity
+			c.mtx.Unlock()
+			if full {
+				break
+			}
+
+			prime := blumPrimes(rand.Reader, pl, 1)[0]
+
+			c.mtx.Lock()
+			c.primes = append(c.primes, prime)
+			c.stats.Generated++
+			if err := c.persist(); err != nil {
+				c.err = err
+			}
+			c.mtx.Unlock()
+
+			select {
+			case <-c.quit:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// Close stops the background generation, waiting for the prime being generated, if any.
+// The primes remaining in the cache are kept in the cache file.
+func (c *PrimeCache) Close() error {
+	if primeCache.Load() == c {
+		SetPrimeCache(nil)
+	}
+	close(c.quit)
+	<-c.done
+	return c.Err()
+}
+
+// persist writes the primes in the cache to the cache file. It must be called with c.mtx held.
+//
+// The file contains a random nonce, followed by the encryption of the concatenated primes.
+func (c *PrimeCache) persist() error {
+	if c.opts.Path == "" {
+		return nil
+	}
+	size := (params.BitsBlumPrime + 7) / 8
+	plaintext := make([]byte, 0, size*len(c.primes))
+	for _, p := range c.primes {
+		plaintext = append(plaintext, p.FillBytes(make([]byte, size))...)
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("prime cache: %w", err)
+	}
+	data := c.aead.Seal(nonce, nonce, plaintext, primeCacheAD)
+
+	// write to a temporary file first, so that the cache file is never left partially written
+	tmp := c.opts.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("prime cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.opts.Path); err != nil {
+		return fmt.Errorf("prime cache: %w", err)
+	}
+	return nil
+}
+
+// load reads the primes from the cache file, returning an empty slice if it does not exist.
+func (c *PrimeCache) load() ([]*saferith.Nat, error) {
+	data, err := os.ReadFile(c.opts.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("prime cache: %w", err)
+	}
+	if len(data) < c.aead.NonceSize() {
+		return nil, errors.New("prime cache: file is too short")
+	}
+	nonce, ciphertext := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, primeCacheAD)
+	if err != nil {
+		return nil, errors.New("prime cache: failed to decrypt file")
+	}
+
+	size := (params.BitsBlumPrime + 7) / 8
+	if len(plaintext)%size != 0 {
+		return nil, errors.New("prime cache: invalid file length")
+	}
+	primes := make([]*saferith.Nat, 0, len(plaintext)/size)
+	for len(plaintext) > 0 {
+		p := new(big.Int).SetBytes(plaintext[:size])
+		plaintext = plaintext[size:]
+		if !isBlumPrime(p) {
+			return nil, errors.New("prime cache: file contains an invalid prime")
+		}
+		primes = append(primes, new(saferith.Nat).SetBig(p, params.BitsBlumPrime))
+	}
+	return primes, nil
+}
+
+// isBlumPrime checks that p is a safe Blum prime of the expected size.
+func isBlumPrime(p *big.Int) bool {
+	if p.BitLen() != params.BitsBlumPrime || p.Bit(0) != 1 || p.Bit(1) != 1 {
+		return false
+	}
+	q := new(big.Int).Rsh(p, 1)
+	return q.ProbablyPrime(0) && p.ProbablyPrime(0)
+}
@@ -0,0 +1,81 @@
+package sample
+
+import (
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForDepth(t *testing.T, c *PrimeCache, depth int) {
+	deadline := time.Now().Add(10 * time.Minute)
+	for c.Stats().Depth < depth {
+		require.True(t, time.Now().Before(deadline), "cache was not filled in time")
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestPrimeCache(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	opts := PrimeCacheOptions{
+		Capacity:     2,
+		LowWaterMark: 1,
+		Path:         filepath.Join(t.TempDir(), "primes"),
+		Key:          key,
+	}
+
+	c, err := NewPrimeCache(opts)
+	require.NoError(t, err)
+	waitForDepth(t, c, 2)
+	require.NoError(t, c.Close())
+	assert.EqualValues(t, 2, c.Stats().Generated)
+
+	wrongKey := make([]byte, 32)
+	_, err = NewPrimeCache(PrimeCacheOptions{Capacity: 2, LowWaterMark: 1, Path: opts.Path, Key: wrongKey})
+	assert.Error(t, err, "decrypting with the wrong key should fail")
+
+	// the persisted primes are available immediately
+	c, err = NewPrimeCache(opts)
+	require.NoError(t, err)
+	require.Equal(t, 2, c.Stats().Depth)
+
+	SetPrimeCache(c)
+	p, q := Paillier(rand.Reader, nil)
+	assert.True(t, isBlumPrime(p.Big()), "p is not a safe Blum prime")
+	assert.True(t, isBlumPrime(q.Big()), "q is not a safe Blum prime")
+	assert.NotEqual(t, 1, int(p.Eq(q)))
+	stats := c.Stats()
+	assert.EqualValues(t, 2, stats.Served)
+	assert.EqualValues(t, 0, stats.Misses)
+	require.NoError(t, c.Close())
+	assert.Nil(t, primeCache.Load(), "closing the cache should unset it")
+
+	// primes which were served are never loaded again
+	c, err = NewPrimeCache(opts)
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+	for _, prime := range c.primes {
+		assert.NotEqual(t, 1, int(prime.Eq(p)))
+		assert.NotEqual(t, 1, int(prime.Eq(q)))
+	}
+}
+
+func TestPrimeCacheOptions(t *testing.T) {
+	_, err := NewPrimeCache(PrimeCacheOptions{Capacity: 0})
+	assert.Error(t, err)
+	_, err = NewPrimeCache(PrimeCacheOptions{Capacity: 1, LowWaterMark: 2})
+	assert.Error(t, err)
+	_, err = NewPrimeCache(PrimeCacheOptions{Capacity: 1, Path: filepath.Join(t.TempDir(), "primes")})
+	assert.Error(t, err, "a key is required to persist the cache")
+
+	// the default low water mark still fills the cache
+	c, err := NewPrimeCache(PrimeCacheOptions{Capacity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Stats().LowWaterMark)
+	waitForDepth(t, c, 1)
+	require.NoError(t, c.Close())
+}
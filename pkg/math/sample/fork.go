@@ -0,0 +1,35 @@
+package sample
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/zeebo/blake3"
+)
+
+// IsDefault returns true if r is nil or crypto/rand.Reader.
+func IsDefault(r io.Reader) bool {
+	return r == nil || r == rand.Reader
+}
+
+// Fork returns n readers derived from r, which can be used concurrently.
+//
+// If r is crypto/rand.Reader, it is simply returned n times. Otherwise, a seed is read
+// from r for each of the n readers, so that their output only depends on r,
+// and not on the order in which they are used.
+func Fork(r io.Reader, n int) []io.Reader {
+	out := make([]io.Reader, n)
+	if IsDefault(r) {
+		for i := range out {
+			out[i] = rand.Reader
+		}
+		return out
+	}
+	for i := range out {
+		seed := make([]byte, 32)
+		mustReadBits(r, seed)
+		h, _ := blake3.NewKeyed(seed)
+		out[i] = h.Digest()
+	}
+	return out
+}
@@ -159,9 +159,28 @@ func tryBlumPrime(rand io.Reader) *saferith.Nat {
 // Paillier generate the necessary integers for a Paillier key pair.
 // p, q are safe primes ((p - 1) / 2 is also prime), and Blum primes (p = 3 mod 4)
 // n = pq.
+//
+// If a PrimeCache was set with SetPrimeCache, the primes are taken from it when available,
+// in which case rand is not used.
+//
+// If rand is not crypto/rand.Reader, the cache is bypassed, and the primes are searched
+// sequentially, so that they only depend on rand.
 func Paillier(rand io.Reader, pl *pool.Pool) (p, q *saferith.Nat) {
+	if !IsDefault(rand) {
+		primes := blumPrimes(rand, nil, 2)
+		return primes[0], primes[1]
+	}
+	if c := primeCache.Load(); c != nil {
+		return c.Paillier(rand, pl)
+	}
+	primes := blumPrimes(rand, pl, 2)
+	return primes[0], primes[1]
+}
+
+// blumPrimes generates count safe Blum primes.
+func blumPrimes(rand io.Reader, pl *pool.Pool, count int) []*saferith.Nat {
 	reader := pool.NewLockedReader(rand)
-	results := pl.Search(2, func() interface{} {
+	results := pl.Search(count, func() interface{} {
 		q := tryBlumPrime(reader)
 		// You have to do this, because of how Go handles nil.
 		if q == nil {
@@ -169,6 +188,9 @@ func Paillier(rand io.Reader, pl *pool.Pool) (p, q *saferith.Nat) {
 		}
 		return q
 	})
-	p, q = results[0].(*saferith.Nat), results[1].(*saferith.Nat)
-	return
+	primes := make([]*saferith.Nat, count)
+	for i, result := range results {
+		primes[i] = result.(*saferith.Nat)
+	}
+	return primes
 }
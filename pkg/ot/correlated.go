@@ -1,12 +1,13 @@
 package ot
 
 import (
-	"crypto/rand"
 	"errors"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
 	"github.com/w3-key/mps-lean/pkg/params"
 	"github.com/w3-key/mps-lean/pkg/pool"
 	"github.com/zeebo/blake3"
@@ -26,6 +27,7 @@ type CorreOTSendSetup struct {
 // This struct is needed, because there are multiple rounds in the setup.
 type CorreOTSetupSender struct {
 	// After setup
+	rand io.Reader
 	pl   *pool.Pool
 	hash *hash.Hash
 	// After Round 1
@@ -40,8 +42,10 @@ type CorreOTSetupSender struct {
 // NewCorreOTSetupSender initializes the state for setting up the Sender part of a Correlated OT.
 //
 // This follows the Initialize part of Figure 3, in https://eprint.iacr.org/2015/546.
-func NewCorreOTSetupSender(pl *pool.Pool, hash *hash.Hash) *CorreOTSetupSender {
-	return &CorreOTSetupSender{pl: pl, hash: hash}
+//
+// The correlation vector, and the secrets of the underlying Random OTs, are sampled from rand.
+func NewCorreOTSetupSender(rand io.Reader, pl *pool.Pool, hash *hash.Hash) *CorreOTSetupSender {
+	return &CorreOTSetupSender{rand: rand, pl: pl, hash: hash}
 }
 
 // CorreOTSetupSendRound1Message is the first message sent by the Sender in the Correlated OT setup.
@@ -58,17 +62,19 @@ func (r *CorreOTSetupSender) Round1(msg *CorreOTSetupReceiveRound1Message) (*Cor
 		return nil, err
 	}
 
-	_, _ = rand.Read(r._Delta[:])
+	_, _ = io.ReadFull(r.rand, r._Delta[:])
 
 	randomOTNonces := r.hash.Fork(&hash.BytesWithDomain{
 		TheDomain: "CorreOT Random OT Nonces",
 		Bytes:     nil,
 	}).Digest()
+	// The receivers run in parallel, so each of them gets its own reader.
+	readers := sample.Fork(r.rand, params.OTParam)
 	for i := 0; i < params.OTParam; i++ {
 		choice := saferith.Choice(bitAt(i, r._Delta[:]))
 		nonce := make([]byte, 32)
 		_, _ = randomOTNonces.Read(nonce)
-		r.randomOTReceivers[i] = NewRandomOTReceiver(nonce, r.setup, choice)
+		r.randomOTReceivers[i] = NewRandomOTReceiver(readers[i], nonce, r.setup, choice)
 	}
 
 	outMsg := new(CorreOTSetupSendRound1Message)
@@ -128,6 +134,7 @@ type CorreOTReceiveSetup struct {
 // This is necessary, because the setup process takes multiple rounds.
 type CorreOTSetupReceiver struct {
 	// After setup
+	rand  io.Reader
 	pl    *pool.Pool
 	hash  *hash.Hash
 	group curve.Curve
@@ -141,8 +148,10 @@ type CorreOTSetupReceiver struct {
 // NewCorreOTSetupReceiver initializes the state for setting up the Receiver part of a Correlated OT.
 //
 // This follows the Initialize part of Figure 3, in https://eprint.iacr.org/2015/546.
-func NewCorreOTSetupReceiver(pl *pool.Pool, hash *hash.Hash, group curve.Curve) *CorreOTSetupReceiver {
-	return &CorreOTSetupReceiver{pl: pl, hash: hash, group: group}
+//
+// The secret of the Random OT setup is sampled from rand.
+func NewCorreOTSetupReceiver(rand io.Reader, pl *pool.Pool, hash *hash.Hash, group curve.Curve) *CorreOTSetupReceiver {
+	return &CorreOTSetupReceiver{rand: rand, pl: pl, hash: hash, group: group}
 }
 
 // CorreOTSetupReceiveRound1Message is the first message sent by the Receiver in a Correlated OT Setup.
@@ -157,7 +166,7 @@ func EmptyCorreOTSetupReceiveRound1Message(group curve.Curve) *CorreOTSetupRecei
 
 // Round1 runs the first round of a Receiver's correlated OT Setup.
 func (r *CorreOTSetupReceiver) Round1() *CorreOTSetupReceiveRound1Message {
-	msg, setup := RandomOTSetupSend(r.hash, r.group)
+	msg, setup := RandomOTSetupSend(r.rand, r.hash, r.group)
 	r.setup = setup
 
 	randomOTNonces := r.hash.Fork(&hash.BytesWithDomain{
@@ -12,8 +12,8 @@ import (
 )
 
 func runCorreOTSetup(pl *pool.Pool, hash *hash.Hash) (*CorreOTSendSetup, *CorreOTReceiveSetup, error) {
-	sender := NewCorreOTSetupSender(pl, hash.Clone())
-	receiver := NewCorreOTSetupReceiver(pl, hash.Clone(), testGroup)
+	sender := NewCorreOTSetupSender(rand.Reader, pl, hash.Clone())
+	receiver := NewCorreOTSetupReceiver(rand.Reader, pl, hash.Clone(), testGroup)
 	msgR1 := receiver.Round1()
 	msgS1, err := sender.Round1(msgR1)
 	if err != nil {
@@ -107,6 +107,44 @@ func TestCorreOT(t *testing.T) {
 	}
 }
 
+func TestCorreOTSetupMarshal(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	sendSetup, receiveSetup, err := runCorreOTSetup(pl, hash.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := sendSetup.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendSetup2 := new(CorreOTSendSetup)
+	if err = sendSetup2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if *sendSetup != *sendSetup2 {
+		t.Error("send setup doesn't match after unmarshalling")
+	}
+
+	data, err = receiveSetup.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiveSetup2 := new(CorreOTReceiveSetup)
+	if err = receiveSetup2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if *receiveSetup != *receiveSetup2 {
+		t.Error("receive setup doesn't match after unmarshalling")
+	}
+
+	if err = receiveSetup2.UnmarshalBinary(data[1:]); err == nil {
+		t.Error("unmarshalling a truncated setup should fail")
+	}
+}
+
 func BenchmarkCorreOTSetup(b *testing.B) {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
@@ -1,9 +1,9 @@
 package ot
 
 import (
-	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"io"
 
 	"github.com/w3-key/mps-lean/pkg/hash"
 	"github.com/w3-key/mps-lean/pkg/params"
@@ -161,12 +161,12 @@ type ExtendedOTReceiveMessage struct {
 // This follows Figure 7 of https://eprint.iacr.org/2015/546.
 //
 // A single setup can be used for many invocations of this protocol, so long as the
-// hash is initialized with some kind of nonce.
-func ExtendedOTReceive(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, choices []byte) (*ExtendedOTReceiveMessage, *ExtendedOTReceiveResult) {
+// hash is initialized with some kind of nonce. The extra choice bits are sampled from rand.
+func ExtendedOTReceive(rand io.Reader, ctxHash *hash.Hash, setup *CorreOTReceiveSetup, choices []byte) (*ExtendedOTReceiveMessage, *ExtendedOTReceiveResult) {
 	inflatedBatchSize := 8*len(choices) + params.OTParam + params.StatParam
 	extraChoices := make([]byte, inflatedBatchSize/8)
 	copy(extraChoices, choices)
-	_, _ = rand.Read(extraChoices[len(choices):])
+	_, _ = io.ReadFull(rand, extraChoices[len(choices):])
 
 	correMsg, correResult := CorreOTReceive(ctxHash, setup, extraChoices)
 
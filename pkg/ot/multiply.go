@@ -1,8 +1,8 @@
 package ot
 
 import (
-	"crypto/rand"
 	"errors"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -21,13 +21,13 @@ func scalarBytes(group curve.Curve) int {
 // The noise should be public, but the encoding will be unpredictable, but still decodable.
 //
 // The noise vector should have a length that's a multiple of 8
-func encode(beta curve.Scalar, noise []curve.Scalar) ([]byte, error) {
+func encode(rand io.Reader, beta curve.Scalar, noise []curve.Scalar) ([]byte, error) {
 	// This follows Algorithm 4 in Doerner's paper:
 	//   https://eprint.iacr.org/2018/499
 	group := beta.Curve()
 
 	gamma := make([]byte, len(noise)/8)
-	_, _ = rand.Read(gamma)
+	_, _ = io.ReadFull(rand, gamma)
 
 	acc := group.NewScalar().Set(beta)
 	mulNat := new(saferith.Nat)
@@ -87,12 +87,13 @@ type MultiplySender struct {
 // sharing of alpha * beta.
 //
 // This follows Protocol 5 of https://eprint.iacr.org/2018/4990.
-func NewMultiplySender(ctxHash *hash.Hash, setup *CorreOTSendSetup, alpha curve.Scalar) *MultiplySender {
+// The extra scalar masking alpha is sampled from rand.
+func NewMultiplySender(rand io.Reader, ctxHash *hash.Hash, setup *CorreOTSendSetup, alpha curve.Scalar) *MultiplySender {
 	group := alpha.Curve()
 	gadget := makeGadget(ctxHash, group)
 	var doubleAlpha [2]curve.Scalar
 	doubleAlpha[0] = alpha
-	doubleAlpha[1] = sample.Scalar(rand.Reader, group)
+	doubleAlpha[1] = sample.Scalar(rand, group)
 	return &MultiplySender{
 		ctxHash:     ctxHash,
 		group:       group,
@@ -174,10 +175,11 @@ type MultiplyReceiver struct {
 // sharing of alpha * beta.
 //
 // This follows Protocol 5 of https://eprint.iacr.org/2018/4990.
-func NewMultiplyReceiver(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, beta curve.Scalar) (*MultiplyReceiver, error) {
+// The encoding of beta, and the extra choice bits of the underlying OTs, are sampled from rand.
+func NewMultiplyReceiver(rand io.Reader, ctxHash *hash.Hash, setup *CorreOTReceiveSetup, beta curve.Scalar) (*MultiplyReceiver, error) {
 	group := beta.Curve()
 	gadget := makeGadget(ctxHash, group)
-	choices, err := encode(beta, gadget[scalarBytes(group):])
+	choices, err := encode(rand, beta, gadget[scalarBytes(group):])
 	if err != nil {
 		return nil, err
 	}
@@ -188,7 +190,7 @@ func NewMultiplyReceiver(ctxHash *hash.Hash, setup *CorreOTReceiveSetup, beta cu
 		beta:     beta,
 		gadget:   gadget,
 		choices:  choices,
-		receiver: NewAdditiveOTReceiver(ctxHash, setup, group, choices),
+		receiver: NewAdditiveOTReceiver(rand, ctxHash, setup, group, choices),
 	}, nil
 }
 
@@ -11,8 +11,8 @@ import (
 )
 
 func runMultiply(hash *hash.Hash, sendSetup *CorreOTSendSetup, receiveSetup *CorreOTReceiveSetup, alpha, beta curve.Scalar) (curve.Scalar, curve.Scalar, error) {
-	sender := NewMultiplySender(hash.Clone(), sendSetup, alpha)
-	receiver, err := NewMultiplyReceiver(hash.Clone(), receiveSetup, beta)
+	sender := NewMultiplySender(rand.Reader, hash.Clone(), sendSetup, alpha)
+	receiver, err := NewMultiplyReceiver(rand.Reader, hash.Clone(), receiveSetup, beta)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,9 +1,9 @@
 package ot
 
 import (
-	"crypto/rand"
 	"crypto/subtle"
 	"fmt"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -44,10 +44,10 @@ type RandomOTSendSetup struct {
 // if that's desired.
 //
 // This setup can be done once and then used for multiple executions.
-func RandomOTSetupSend(hash *hash.Hash, group curve.Curve) (*RandomOTSetupSendMessage, *RandomOTSendSetup) {
-	b := sample.Scalar(rand.Reader, group)
+func RandomOTSetupSend(rand io.Reader, hash *hash.Hash, group curve.Curve) (*RandomOTSetupSendMessage, *RandomOTSendSetup) {
+	b := sample.Scalar(rand, group)
 	B := b.ActOnBase()
-	BProof := zksch.NewProof(hash, B, b, nil)
+	BProof := zksch.NewProof(rand, hash, B, b, nil)
 	return &RandomOTSetupSendMessage{B: B, BProof: BProof}, &RandomOTSendSetup{_B: B, b: b, _bB: b.Act(B)}
 }
 
@@ -76,6 +76,7 @@ func RandomOTSetupReceive(hash *hash.Hash, msg *RandomOTSetupSendMessage) (*Rand
 // This should be created from a saved setup, for each execution.
 type RandomOTReceiever struct {
 	// After setup
+	rand  io.Reader
 	hash  *blake3.Hasher
 	group curve.Curve
 	// Which random message we want to receive.
@@ -98,14 +99,15 @@ type RandomOTReceiever struct {
 //
 // The nonce should be 32 bytes, and must be different if a single setup is used for multiple OTs.
 //
-// choice indicates which of the two random messages should be received.
-func NewRandomOTReceiver(nonce []byte, result *RandomOTReceiveSetup, choice saferith.Choice) (out RandomOTReceiever) {
+// choice indicates which of the two random messages should be received, and rand is used to sample the receiver's secret.
+func NewRandomOTReceiver(rand io.Reader, nonce []byte, result *RandomOTReceiveSetup, choice saferith.Choice) (out RandomOTReceiever) {
 	// This will only error if the nonce has the wrong length, which is a programmer error
 	var err error
 	out.hash, err = blake3.NewKeyed(nonce)
 	if err != nil {
 		panic(err)
 	}
+	out.rand = rand
 	out.group = result._B.Curve()
 	out.choice = choice
 	out._B = result._B
@@ -125,7 +127,7 @@ func (r *RandomOTReceiever) Round1() (outMsg RandomOTReceiveRound1Message, err e
 	// We sample a <- Z_q, and then compute
 	//   A = a * G + w * B
 	//   randChoice = H(a * B)
-	a := sample.Scalar(rand.Reader, r.group)
+	a := sample.Scalar(r.rand, r.group)
 	A := a.ActOnBase()
 	outMsg.ABytes, err = A.MarshalBinary()
 	if err != nil {
@@ -0,0 +1,62 @@
+package ot
+
+import (
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/params"
+)
+
+// correOTSetupSize is the size of a marshalled CorreOTSendSetup or CorreOTReceiveSetup.
+const correOTSetupSize = params.OTBytes + params.OTParam*params.OTBytes
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The setup contains secret values, and should be stored as carefully as a private key.
+func (s *CorreOTSendSetup) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, correOTSetupSize)
+	out = append(out, s._Delta[:]...)
+	for i := 0; i < params.OTParam; i++ {
+		out = append(out, s._K_Delta[i][:]...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *CorreOTSendSetup) UnmarshalBinary(data []byte) error {
+	if len(data) != correOTSetupSize {
+		return fmt.Errorf("CorreOTSendSetup: invalid length %d", len(data))
+	}
+	data = data[copy(s._Delta[:], data):]
+	for i := 0; i < params.OTParam; i++ {
+		data = data[copy(s._K_Delta[i][:], data):]
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The setup contains secret values, and should be stored as carefully as a private key.
+func (s *CorreOTReceiveSetup) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 0, 2*params.OTParam*params.OTBytes)
+	for i := 0; i < params.OTParam; i++ {
+		out = append(out, s._K_0[i][:]...)
+	}
+	for i := 0; i < params.OTParam; i++ {
+		out = append(out, s._K_1[i][:]...)
+	}
+	return out, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *CorreOTReceiveSetup) UnmarshalBinary(data []byte) error {
+	if len(data) != 2*params.OTParam*params.OTBytes {
+		return fmt.Errorf("CorreOTReceiveSetup: invalid length %d", len(data))
+	}
+	for i := 0; i < params.OTParam; i++ {
+		data = data[copy(s._K_0[i][:], data):]
+	}
+	for i := 0; i < params.OTParam; i++ {
+		data = data[copy(s._K_1[i][:], data):]
+	}
+	return nil
+}
@@ -32,7 +32,7 @@ func init() {
 func reinit() {
 	pl := pool.NewPool(0)
 	defer pl.TearDown()
-	paillierPublic, paillierSecret = KeyGen(pl)
+	paillierPublic, paillierSecret = KeyGen(rand.Reader, pl)
 }
 
 func TestCiphertextValidate(t *testing.T) {
@@ -62,7 +62,7 @@ func testEncDecRoundTrip(x uint64, xNeg bool) bool {
 	if xNeg {
 		m.Neg(1)
 	}
-	ciphertext, _ := paillierPublic.Enc(m)
+	ciphertext, _ := paillierPublic.Enc(rand.Reader, m)
 	shouldBeM, err := paillierSecret.Dec(ciphertext)
 	if err != nil {
 		return false
@@ -89,8 +89,8 @@ func testEncDecHomomorphic(a, b uint64, aNeg, bNeg bool) bool {
 	if bNeg {
 		mb.Neg(1)
 	}
-	ca, _ := paillierPublic.Enc(ma)
-	cb, _ := paillierPublic.Enc(mb)
+	ca, _ := paillierPublic.Enc(rand.Reader, ma)
+	cb, _ := paillierPublic.Enc(rand.Reader, mb)
 	expected := new(saferith.Int).Add(ma, mb, -1)
 	actual, err := paillierSecret.Dec(ca.Add(paillierPublic, cb))
 	if err != nil {
@@ -118,7 +118,7 @@ func testEncDecScalingHomomorphic(s, x uint64, sNeg, xNeg bool) bool {
 	if sNeg {
 		sInt.Neg(1)
 	}
-	c, _ := paillierPublic.Enc(m)
+	c, _ := paillierPublic.Enc(rand.Reader, m)
 	expected := new(saferith.Int).Mul(m, sInt, -1)
 	actual, err := paillierSecret.Dec(c.Mul(paillierPublic, sInt))
 	if err != nil {
@@ -172,14 +172,14 @@ func BenchmarkEncryption(b *testing.B) {
 	m := sample.IntervalLEps(rand.Reader)
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
-		resultCiphertext, _ = paillierPublic.Enc(m)
+		resultCiphertext, _ = paillierPublic.Enc(rand.Reader, m)
 	}
 }
 
 func BenchmarkAddCiphertext(b *testing.B) {
 	b.StopTimer()
 	m := sample.IntervalLEps(rand.Reader)
-	c, _ := paillierPublic.Enc(m)
+	c, _ := paillierPublic.Enc(rand.Reader, m)
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
 		resultCiphertext = c.Add(paillierPublic, c)
@@ -189,7 +189,7 @@ func BenchmarkAddCiphertext(b *testing.B) {
 func BenchmarkMulCiphertext(b *testing.B) {
 	b.StopTimer()
 	m := sample.IntervalLEps(rand.Reader)
-	c, _ := paillierPublic.Enc(m)
+	c, _ := paillierPublic.Enc(rand.Reader, m)
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
 		resultCiphertext = c.Mul(paillierPublic, m)
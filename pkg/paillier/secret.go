@@ -1,9 +1,9 @@
 package paillier
 
 import (
-	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/math/arith"
@@ -56,16 +56,17 @@ func (sk *SecretKey) Phi() *saferith.Nat {
 }
 
 // KeyGen generates a new PublicKey and it's associated SecretKey.
-func KeyGen(pl *pool.Pool) (pk *PublicKey, sk *SecretKey) {
-	sk = NewSecretKey(pl)
+func KeyGen(rand io.Reader, pl *pool.Pool) (pk *PublicKey, sk *SecretKey) {
+	sk = NewSecretKey(rand, pl)
 	pk = sk.PublicKey
 	return
 }
 
 // NewSecretKey generates primes p and q suitable for the scheme, and returns the initialized SecretKey.
-func NewSecretKey(pl *pool.Pool) *SecretKey {
-	// TODO maybe we could take the reader as argument?
-	return NewSecretKeyFromPrimes(sample.Paillier(rand.Reader, pl))
+//
+// The primes are sampled from rand.
+func NewSecretKey(rand io.Reader, pl *pool.Pool) *SecretKey {
+	return NewSecretKeyFromPrimes(sample.Paillier(rand, pl))
 }
 
 // NewSecretKeyFromPrimes generates a new SecretKey. Assumes that P and Q are prime.
@@ -148,8 +149,8 @@ func (sk *SecretKey) DecWithRandomness(ct *Ciphertext) (*saferith.Int, *saferith
 	return m, r, nil
 }
 
-func (sk SecretKey) GeneratePedersen() (*pedersen.Parameters, *saferith.Nat) {
-	s, t, lambda := sample.Pedersen(rand.Reader, sk.phi, sk.n.Modulus)
+func (sk SecretKey) GeneratePedersen(rand io.Reader) (*pedersen.Parameters, *saferith.Nat) {
+	s, t, lambda := sample.Pedersen(rand, sk.phi, sk.n.Modulus)
 	ped := pedersen.New(sk.n, s, t)
 	return ped, lambda
 }
@@ -48,6 +48,7 @@ type command struct {
 //
 // We need to keep searching for successful queries of f while *ctr > 0.
 // When we find a successful result, we decrement *ctr.
+// We only signal results which were actually stored, so that there are exactly as many signals as results.
 func workerSearch(results []interface{}, ctrChanged chan<- struct{}, f func(int) interface{}, ctr *int64) {
 	for atomic.LoadInt64(ctr) > 0 {
 		res := f(0)
@@ -55,9 +56,10 @@ func workerSearch(results []interface{}, ctrChanged chan<- struct{}, f func(int)
 			continue
 		}
 		i := atomic.AddInt64(ctr, -1)
-		if i >= 0 {
-			results[i] = res
+		if i < 0 {
+			return
 		}
+		results[i] = res
 		ctrChanged <- struct{}{}
 	}
 }
@@ -143,15 +145,19 @@ func (p *Pool) Search(count int, f func() interface{}) []interface{} {
 		f:          func(i int) interface{} { return f() },
 		results:    results,
 	}
+	// Workers decrement ctr before signalling, so we wait for exactly count signals
+	// instead of checking ctr, otherwise a worker could block forever on its last signal.
+	received := 0
 	cmdI := 0
 	for cmdI < p.workerCount {
 		select {
 		case p.commands <- cmd:
 			cmdI++
 		case <-ctrChanged:
+			received++
 		}
 	}
-	for atomic.LoadInt64(&ctr) > 0 {
+	for ; received < count; received++ {
 		<-ctrChanged
 	}
 
@@ -170,6 +176,8 @@ func (p *Pool) Parallelize(count int, f func(int) interface{}) []interface{} {
 
 	ctr := int64(count)
 	ctrChanged := make(chan struct{})
+	// Each command is signalled exactly once, see Search.
+	received := 0
 	cmdI := 0
 	for cmdI < count {
 		cmd := command{
@@ -187,9 +195,10 @@ func (p *Pool) Parallelize(count int, f func(int) interface{}) []interface{} {
 		case p.commands <- cmd:
 			cmdI++
 		case <-ctrChanged:
+			received++
 		}
 	}
-	for atomic.LoadInt64(&ctr) > 0 {
+	for ; received < count; received++ {
 		<-ctrChanged
 	}
 
@@ -0,0 +1,42 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPoolNoDeadlock checks that every worker finishes signalling its results before
+// Search or Parallelize return, so that repeated calls never find all workers blocked.
+func TestPoolNoDeadlock(t *testing.T) {
+	const (
+		workers    = 4
+		iterations = 2000
+	)
+	p := NewPool(workers)
+	defer p.TearDown()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for n := 0; n < iterations; n++ {
+			for _, res := range p.Parallelize(workers, func(i int) interface{} { return i }) {
+				if res == nil {
+					t.Error("missing Parallelize result")
+					return
+				}
+			}
+			for _, res := range p.Search(workers, func() interface{} { return struct{}{} }) {
+				if res == nil {
+					t.Error("missing Search result")
+					return
+				}
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Minute):
+		t.Fatal("pool deadlocked")
+	}
+}
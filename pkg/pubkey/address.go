@@ -0,0 +1,186 @@
+package pubkey
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/taproot"
+)
+
+// BitcoinAddressType is the type of output a Bitcoin address pays to.
+type BitcoinAddressType int
+
+const (
+	// P2PKH pays to the hash of a compressed public key, in a base58 address.
+	P2PKH BitcoinAddressType = iota + 1
+	// P2WPKH pays to the hash of a compressed public key, in a segwit v0 bech32 address.
+	P2WPKH
+	// P2TR pays to the BIP-86 tweak of a public key, in a segwit v1 bech32m address.
+	P2TR
+)
+
+// BitcoinAddress is a decoded Bitcoin address.
+type BitcoinAddress struct {
+	Type BitcoinAddressType
+	// Program is the 20 byte public key hash for P2PKH and P2WPKH, or the 32 byte x-only output key for P2TR.
+	Program []byte
+}
+
+func secp256k1Point(p curve.Point) (*curve.Secp256k1Point, error) {
+	point, ok := p.(*curve.Secp256k1Point)
+	if !ok {
+		return nil, errors.New("pubkey: public key is not on secp256k1")
+	}
+	if point.IsIdentity() {
+		return nil, errors.New("pubkey: public key is the identity")
+	}
+	return point, nil
+}
+
+// hash160 returns RIPEMD-160(SHA-256(compressed p)), which identifies p in P2PKH, P2WPKH and Cosmos addresses.
+func hash160(p curve.Point) ([]byte, error) {
+	point, err := secp256k1Point(p)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := point.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return btcutil.Hash160(compressed), nil
+}
+
+// TaprootOutputKey returns the output key Q = P + H_TapTweak(P)⋅G committing to no script, as defined in BIP-86,
+// where P is p with an even y coordinate.
+//
+// Q is the key which signs taproot key path spends of a P2TR address of p.
+func TaprootOutputKey(p curve.Point) (*curve.Secp256k1Point, error) {
+	point, err := secp256k1Point(p)
+	if err != nil {
+		return nil, err
+	}
+	if !point.HasEvenY() {
+		point = point.Negate().(*curve.Secp256k1Point)
+	}
+	tweak := new(curve.Secp256k1Scalar)
+	if err := tweak.UnmarshalBinary(taproot.TaggedHash("TapTweak", point.XBytes())); err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+	return point.Add(tweak.ActOnBase()).(*curve.Secp256k1Point), nil
+}
+
+// program returns the program of an address of type addressType paying to p.
+func (addressType BitcoinAddressType) program(p curve.Point) ([]byte, error) {
+	switch addressType {
+	case P2PKH, P2WPKH:
+		return hash160(p)
+	case P2TR:
+		q, err := TaprootOutputKey(p)
+		if err != nil {
+			return nil, err
+		}
+		return q.XBytes(), nil
+	default:
+		return nil, fmt.Errorf("pubkey: unknown Bitcoin address type %d", addressType)
+	}
+}
+
+// BitcoinP2PKH returns the legacy address of p on the network net, such as 1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH.
+func BitcoinP2PKH(p curve.Point, net *chaincfg.Params) (string, error) {
+	program, err := P2PKH.program(p)
+	if err != nil {
+		return "", err
+	}
+	return base58.CheckEncode(program, net.PubKeyHashAddrID), nil
+}
+
+// BitcoinP2WPKH returns the native segwit address of p on the network net,
+// such as bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4.
+func BitcoinP2WPKH(p curve.Point, net *chaincfg.Params) (string, error) {
+	program, err := P2WPKH.program(p)
+	if err != nil {
+		return "", err
+	}
+	return encodeSegwitAddress(net.Bech32HRPSegwit, 0, program)
+}
+
+// BitcoinP2TR returns the taproot address of p on the network net, without a script tree as defined in BIP-86.
+func BitcoinP2TR(p curve.Point, net *chaincfg.Params) (string, error) {
+	program, err := P2TR.program(p)
+	if err != nil {
+		return "", err
+	}
+	return encodeSegwitAddress(net.Bech32HRPSegwit, 1, program)
+}
+
+// ParseBitcoinAddress decodes a P2PKH, P2WPKH or P2TR address on the network net.
+//
+// An address only contains a hash or a tweak of the public key, so the key itself can not be recovered,
+// but BitcoinAddress.Matches checks whether the address pays to a given key.
+func ParseBitcoinAddress(address string, net *chaincfg.Params) (BitcoinAddress, error) {
+	version, program, err := decodeSegwitAddress(net.Bech32HRPSegwit, address)
+	if err == nil {
+		switch {
+		case version == 0 && len(program) == 20:
+			return BitcoinAddress{Type: P2WPKH, Program: program}, nil
+		case version == 1 && len(program) == 32:
+			return BitcoinAddress{Type: P2TR, Program: program}, nil
+		default:
+			return BitcoinAddress{}, fmt.Errorf("pubkey: unsupported witness program of version %d", version)
+		}
+	}
+	program, netID, err := base58.CheckDecode(address)
+	if err != nil {
+		return BitcoinAddress{}, fmt.Errorf("pubkey: invalid Bitcoin address: %w", err)
+	}
+	if netID != net.PubKeyHashAddrID || len(program) != 20 {
+		return BitcoinAddress{}, errors.New("pubkey: unsupported Bitcoin address")
+	}
+	return BitcoinAddress{Type: P2PKH, Program: program}, nil
+}
+
+// Matches returns true if the address pays to p.
+func (a BitcoinAddress) Matches(p curve.Point) bool {
+	program, err := a.Type.program(p)
+	return err == nil && bytes.Equal(program, a.Program)
+}
+
+// CosmosAddress returns the bech32 account address of p with the human readable part hrp,
+// such as "cosmos" for the Cosmos Hub.
+func CosmosAddress(p curve.Point, hrp string) (string, error) {
+	program, err := hash160(p)
+	if err != nil {
+		return "", err
+	}
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32Encode(hrp, data, bech32Const), nil
+}
+
+// ParseCosmosAddress decodes the 20 byte account of a bech32 Cosmos address with the human readable part hrp.
+//
+// As with Bitcoin addresses, the public key can not be recovered from the address.
+func ParseCosmosAddress(address, hrp string) ([]byte, error) {
+	decodedHRP, data, constant, err := bech32Decode(address)
+	if err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+	if decodedHRP != hrp || constant != bech32Const {
+		return nil, fmt.Errorf("pubkey: not a %s address", hrp)
+	}
+	account, err := convertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+	if len(account) != 20 {
+		return nil, fmt.Errorf("pubkey: invalid account length %d", len(account))
+	}
+	return account, nil
+}
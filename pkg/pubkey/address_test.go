@@ -0,0 +1,124 @@
+package pubkey
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+)
+
+func TestBitcoinAddressVectors(t *testing.T) {
+	g := curve.Secp256k1{}.NewBasePoint()
+
+	// BIP-173
+	address, err := BitcoinP2WPKH(g, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", address)
+	address, err = BitcoinP2PKH(g, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH", address)
+
+	// BIP-86, m/86'/0'/0'/0/0
+	internalKey, _ := hex.DecodeString("02cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115")
+	p, err := ParseSEC1(curve.Secp256k1{}, internalKey)
+	require.NoError(t, err)
+	address, err = BitcoinP2TR(p, &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", address)
+	outputKey, err := TaprootOutputKey(p)
+	require.NoError(t, err)
+	assert.Equal(t, "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c", hex.EncodeToString(outputKey.XBytes()))
+
+	// the address only depends on the x coordinate of the internal key
+	odd, err := BitcoinP2TR(p.Negate(), &chaincfg.MainNetParams)
+	require.NoError(t, err)
+	assert.Equal(t, address, odd)
+}
+
+func TestBitcoinAddress(t *testing.T) {
+	p := sample.Scalar(rand.Reader, curve.Secp256k1{}).ActOnBase()
+	other := sample.Scalar(rand.Reader, curve.Secp256k1{}).ActOnBase()
+	compressed, err := MarshalCompressed(p)
+	require.NoError(t, err)
+
+	encoders := map[BitcoinAddressType]func(curve.Point, *chaincfg.Params) (string, error){
+		P2PKH:  BitcoinP2PKH,
+		P2WPKH: BitcoinP2WPKH,
+		P2TR:   BitcoinP2TR,
+	}
+	for addressType, encode := range encoders {
+		address, err := encode(p, &chaincfg.TestNet3Params)
+		require.NoError(t, err)
+		parsed, err := ParseBitcoinAddress(address, &chaincfg.TestNet3Params)
+		require.NoError(t, err)
+		assert.Equal(t, addressType, parsed.Type)
+		assert.True(t, parsed.Matches(p))
+		assert.False(t, parsed.Matches(other))
+		_, err = ParseBitcoinAddress(address, &chaincfg.MainNetParams)
+		assert.Error(t, err, "address is for another network")
+	}
+
+	// compared to btcutil
+	expected, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(compressed), &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	address, err := BitcoinP2PKH(p, &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	assert.Equal(t, expected.EncodeAddress(), address)
+	expectedSegwit, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(compressed), &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	address, err = BitcoinP2WPKH(p, &chaincfg.TestNet3Params)
+	require.NoError(t, err)
+	assert.Equal(t, expectedSegwit.EncodeAddress(), address)
+
+	_, err = BitcoinP2WPKH(sample.Scalar(rand.Reader, curve.P256{}).ActOnBase(), &chaincfg.MainNetParams)
+	assert.Error(t, err, "Bitcoin addresses are secp256k1 only")
+}
+
+func TestSegwitChecksum(t *testing.T) {
+	program := make([]byte, 32)
+	data, err := convertBits(program, 8, 5, true)
+	require.NoError(t, err)
+
+	// BIP-350: version 0 uses bech32, and later versions bech32m
+	_, _, err = decodeSegwitAddress("bc", bech32Encode("bc", append([]byte{0}, data...), bech32mConst))
+	assert.Error(t, err)
+	_, _, err = decodeSegwitAddress("bc", bech32Encode("bc", append([]byte{1}, data...), bech32Const))
+	assert.Error(t, err)
+	version, decoded, err := decodeSegwitAddress("bc", bech32Encode("bc", append([]byte{1}, data...), bech32mConst))
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), version)
+	assert.Equal(t, program, decoded)
+
+	// uppercase is allowed, but not mixed case
+	_, _, err = decodeSegwitAddress("bc", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4")
+	assert.NoError(t, err)
+	_, _, err = decodeSegwitAddress("bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kV8f3t4")
+	assert.Error(t, err)
+	_, _, err = decodeSegwitAddress("bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5")
+	assert.Error(t, err, "invalid checksum")
+}
+
+func TestCosmosAddress(t *testing.T) {
+	p := sample.Scalar(rand.Reader, curve.Secp256k1{}).ActOnBase()
+	compressed, err := MarshalCompressed(p)
+	require.NoError(t, err)
+
+	address, err := CosmosAddress(p, "cosmos")
+	require.NoError(t, err)
+	expected, err := bech32.EncodeFromBase256("cosmos", btcutil.Hash160(compressed))
+	require.NoError(t, err)
+	assert.Equal(t, expected, address)
+
+	account, err := ParseCosmosAddress(address, "cosmos")
+	require.NoError(t, err)
+	assert.Equal(t, btcutil.Hash160(compressed), account)
+	_, err = ParseCosmosAddress(address, "osmo")
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+// Package pubkey encodes and decodes public keys, such as the group key returned by Config.PublicPoint(),
+// in the formats expected by other systems.
+//
+// Public keys are encoded in SEC1, PKIX (DER and PEM) and JWK form over secp256k1 and P-256,
+// and as Bitcoin and Cosmos addresses over secp256k1.
+package pubkey
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+)
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidP256           = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+)
+
+// namedCurve describes how a curve is identified in PKIX and JWK.
+type namedCurve struct {
+	group curve.Curve
+	oid   asn1.ObjectIdentifier
+	jwk   string
+}
+
+var namedCurves = []namedCurve{
+	{curve.Secp256k1{}, oidSecp256k1, "secp256k1"},
+	{curve.P256{}, oidP256, "P-256"},
+}
+
+func lookupCurve(group curve.Curve) (namedCurve, error) {
+	for _, c := range namedCurves {
+		if c.group.Name() == group.Name() {
+			return c, nil
+		}
+	}
+	return namedCurve{}, fmt.Errorf("pubkey: unsupported curve %s", group.Name())
+}
+
+// coordinates returns the affine coordinates of p, which must be on a supported curve and not the identity.
+func coordinates(p curve.Point) (*ecdsa.PublicKey, error) {
+	if _, err := lookupCurve(p.Curve()); err != nil {
+		return nil, err
+	}
+	if p.IsIdentity() {
+		return nil, errors.New("pubkey: public key is the identity")
+	}
+	return p.ToECDSA(), nil
+}
+
+// fromCoordinates returns the point (x, y) of group, checking that it lies on the curve.
+func fromCoordinates(group curve.Curve, x, y []byte) (curve.Point, error) {
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("pubkey: coordinates must be 32 bytes")
+	}
+	compressed := make([]byte, 33)
+	compressed[0] = 2 + y[31]&1
+	copy(compressed[1:], x)
+	p := group.NewPoint()
+	if err := p.UnmarshalBinary(compressed); err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+	// decompression found the y of the right parity, which is only y itself if (x, y) is on the curve
+	if p.ToECDSA().Y.Cmp(new(big.Int).SetBytes(y)) != 0 {
+		return nil, errors.New("pubkey: point is not on the curve")
+	}
+	return p, nil
+}
+
+// MarshalCompressed returns the 33 byte SEC1 compressed encoding 0x02 or 0x03 ‖ x of p.
+func MarshalCompressed(p curve.Point) ([]byte, error) {
+	if _, err := coordinates(p); err != nil {
+		return nil, err
+	}
+	return p.MarshalBinary()
+}
+
+// MarshalUncompressed returns the 65 byte SEC1 uncompressed encoding 0x04 ‖ x ‖ y of p.
+func MarshalUncompressed(p curve.Point) ([]byte, error) {
+	pk, err := coordinates(p)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 65)
+	out[0] = 4
+	pk.X.FillBytes(out[1:33])
+	pk.Y.FillBytes(out[33:])
+	return out, nil
+}
+
+// ParseSEC1 decodes a compressed or uncompressed SEC1 encoded point of group.
+func ParseSEC1(group curve.Curve, data []byte) (curve.Point, error) {
+	if _, err := lookupCurve(group); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(data) == 33 && (data[0] == 2 || data[0] == 3):
+		p := group.NewPoint()
+		if err := p.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("pubkey: %w", err)
+		}
+		return p, nil
+	case len(data) == 65 && data[0] == 4:
+		return fromCoordinates(group, data[1:33], data[33:])
+	default:
+		return nil, errors.New("pubkey: invalid SEC1 encoding")
+	}
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// MarshalPKIX returns the DER encoded SubjectPublicKeyInfo of p, as defined in RFC 5480.
+//
+// Unlike x509.MarshalPKIXPublicKey, this supports secp256k1, whose curve is identified by the OID 1.3.132.0.10.
+func MarshalPKIX(p curve.Point) ([]byte, error) {
+	c, err := lookupCurve(p.Curve())
+	if err != nil {
+		return nil, err
+	}
+	point, err := MarshalUncompressed(p)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: c.oid},
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// ParsePKIX decodes a DER encoded SubjectPublicKeyInfo of an elliptic curve public key over secp256k1 or P-256.
+func ParsePKIX(data []byte) (curve.Point, error) {
+	var info subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(data, &info)
+	if err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("pubkey: trailing data after PKIX public key")
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, errors.New("pubkey: not an elliptic curve public key")
+	}
+	for _, c := range namedCurves {
+		if info.Algorithm.Parameters.Equal(c.oid) {
+			return ParseSEC1(c.group, info.PublicKey.RightAlign())
+		}
+	}
+	return nil, fmt.Errorf("pubkey: unsupported curve %v", info.Algorithm.Parameters)
+}
+
+const pemType = "PUBLIC KEY"
+
+// MarshalPEM returns the PKIX encoding of p in a PEM block of type "PUBLIC KEY".
+func MarshalPEM(p curve.Point) ([]byte, error) {
+	der, err := MarshalPKIX(p)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: der}), nil
+}
+
+// ParsePEM decodes the first PEM block of data, which must be a PKIX public key.
+func ParsePEM(data []byte) (curve.Point, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("pubkey: no PEM block found")
+	}
+	if block.Type != pemType {
+		return nil, fmt.Errorf("pubkey: unexpected PEM block type %q", block.Type)
+	}
+	return ParsePKIX(block.Bytes)
+}
+
+// JWK is a JSON Web Key holding an elliptic curve public key, as defined in RFC 7518.
+//
+// The curve is "P-256", or "secp256k1" as registered by RFC 8812.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// MarshalJWK returns the JSON encoding of the JWK of p.
+func MarshalJWK(p curve.Point) ([]byte, error) {
+	c, err := lookupCurve(p.Curve())
+	if err != nil {
+		return nil, err
+	}
+	point, err := MarshalUncompressed(p)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(JWK{
+		Kty: "EC",
+		Crv: c.jwk,
+		X:   base64.RawURLEncoding.EncodeToString(point[1:33]),
+		Y:   base64.RawURLEncoding.EncodeToString(point[33:]),
+	})
+}
+
+// ParseJWK decodes the JSON encoding of an elliptic curve JWK.
+func ParseJWK(data []byte) (curve.Point, error) {
+	var jwk JWK
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, fmt.Errorf("pubkey: %w", err)
+	}
+	if jwk.Kty != "EC" {
+		return nil, fmt.Errorf("pubkey: unsupported key type %q", jwk.Kty)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("pubkey: x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("pubkey: y: %w", err)
+	}
+	for _, c := range namedCurves {
+		if jwk.Crv == c.jwk {
+			return fromCoordinates(c.group, x, y)
+		}
+	}
+	return nil, fmt.Errorf("pubkey: unsupported curve %q", jwk.Crv)
+}
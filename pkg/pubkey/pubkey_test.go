@@ -0,0 +1,133 @@
+package pubkey
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+)
+
+var groups = []curve.Curve{curve.Secp256k1{}, curve.P256{}}
+
+func TestSEC1(t *testing.T) {
+	for _, group := range groups {
+		t.Run(group.Name(), func(t *testing.T) {
+			p := sample.Scalar(rand.Reader, group).ActOnBase()
+
+			compressed, err := MarshalCompressed(p)
+			require.NoError(t, err)
+			require.Len(t, compressed, 33)
+			uncompressed, err := MarshalUncompressed(p)
+			require.NoError(t, err)
+			require.Len(t, uncompressed, 65)
+			pk := p.ToECDSA()
+			assert.Equal(t, elliptic.Marshal(pk.Curve, pk.X, pk.Y), uncompressed)
+
+			for _, data := range [][]byte{compressed, uncompressed} {
+				parsed, err := ParseSEC1(group, data)
+				require.NoError(t, err)
+				assert.True(t, parsed.Equal(p))
+			}
+
+			// a y coordinate which is not the one of x
+			invalid := append([]byte{}, uncompressed...)
+			invalid[64] ^= 2
+			_, err = ParseSEC1(group, invalid)
+			assert.Error(t, err)
+			invalid = append([]byte{}, compressed...)
+			invalid[0] = 4
+			_, err = ParseSEC1(group, invalid)
+			assert.Error(t, err)
+
+			_, err = MarshalCompressed(group.NewPoint())
+			assert.Error(t, err, "the identity is not a public key")
+		})
+	}
+
+	// the uncompressed encoding is the one of go-ethereum
+	p := sample.Scalar(rand.Reader, curve.Secp256k1{}).ActOnBase()
+	uncompressed, err := MarshalUncompressed(p)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.FromECDSAPub(p.ToECDSA()), uncompressed)
+}
+
+// generated with openssl ecparam -name secp256k1 -genkey | openssl ec -pubout
+const (
+	openSSLPEM = `-----BEGIN PUBLIC KEY-----
+MFYwEAYHKoZIzj0CAQYFK4EEAAoDQgAE/wCRCd5Cn1+nRN+OKsxR0z6yRx3vYNLw
+1Y30iFW+WWY+L0fHSZoC+rTtFtwZKYYZPsF0xqDgiXqj6icW7PZSdg==
+-----END PUBLIC KEY-----
+`
+	openSSLDER   = "3056301006072a8648ce3d020106052b8104000a03420004ff009109de429f5fa744df8e2acc51d33eb2471def60d2f0d58df48855be59663e2f47c7499a02fab4ed16dc192986193ec174c6a0e0897aa3ea2716ecf65276"
+	openSSLPoint = "04ff009109de429f5fa744df8e2acc51d33eb2471def60d2f0d58df48855be59663e2f47c7499a02fab4ed16dc192986193ec174c6a0e0897aa3ea2716ecf65276"
+)
+
+func TestPKIXSecp256k1(t *testing.T) {
+	point, _ := hex.DecodeString(openSSLPoint)
+	p, err := ParseSEC1(curve.Secp256k1{}, point)
+	require.NoError(t, err)
+
+	der, err := MarshalPKIX(p)
+	require.NoError(t, err)
+	assert.Equal(t, openSSLDER, hex.EncodeToString(der))
+	encoded, err := MarshalPEM(p)
+	require.NoError(t, err)
+	assert.Equal(t, openSSLPEM, string(encoded))
+
+	parsed, err := ParsePEM([]byte(openSSLPEM))
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(p))
+	_, err = ParsePKIX(append(der, 0))
+	assert.Error(t, err)
+}
+
+func TestPKIXP256(t *testing.T) {
+	p := sample.Scalar(rand.Reader, curve.P256{}).ActOnBase()
+	der, err := MarshalPKIX(p)
+	require.NoError(t, err)
+	expected, err := x509.MarshalPKIXPublicKey(p.ToECDSA())
+	require.NoError(t, err)
+	assert.Equal(t, expected, der)
+
+	parsed, err := ParsePKIX(der)
+	require.NoError(t, err)
+	assert.True(t, parsed.Equal(p))
+}
+
+func TestJWK(t *testing.T) {
+	for _, group := range groups {
+		t.Run(group.Name(), func(t *testing.T) {
+			p := sample.Scalar(rand.Reader, group).ActOnBase()
+			data, err := MarshalJWK(p)
+			require.NoError(t, err)
+			parsed, err := ParseJWK(data)
+			require.NoError(t, err)
+			assert.True(t, parsed.Equal(p))
+
+			var jwk JWK
+			require.NoError(t, json.Unmarshal(data, &jwk))
+			assert.Equal(t, "EC", jwk.Kty)
+			c, err := lookupCurve(group)
+			require.NoError(t, err)
+			assert.Equal(t, c.jwk, jwk.Crv)
+
+			// a point on the other curve
+			jwk.Crv = "P-256"
+			if group.Name() == "p256" {
+				jwk.Crv = "secp256k1"
+			}
+			data, err = json.Marshal(jwk)
+			require.NoError(t, err)
+			_, err = ParseJWK(data)
+			assert.Error(t, err)
+		})
+	}
+}
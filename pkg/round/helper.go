@@ -1,8 +1,10 @@
 package round
 
 import (
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"sync"
 
@@ -222,3 +224,11 @@ func (h *Helper) N() int { return len(h.info.PartyIDs) }
 
 // Group returns the curve used for this protocol.
 func (h *Helper) Group() curve.Curve { return h.info.Group }
+
+// Rand returns the source of randomness for this protocol execution.
+func (h *Helper) Rand() io.Reader {
+	if h.info.Rand == nil {
+		return rand.Reader
+	}
+	return h.info.Rand
+}
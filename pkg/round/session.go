@@ -1,6 +1,8 @@
 package round
 
 import (
+	"io"
+
 	"github.com/w3-key/mps-lean/pkg/hash"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/party"
@@ -19,10 +21,27 @@ type Info struct {
 	Threshold int
 	// Group returns the group used for this protocol execution.
 	Group curve.Curve
+	// Rand is the source of randomness for this protocol execution.
+	// If nil, crypto/rand.Reader is used.
+	//
+	// Setting a deterministic reader makes the execution reproducible,
+	// and should only be done for tests and audits.
+	Rand io.Reader
+}
 
-	JustInfo bool
+// Option sets an optional field of the Info of a protocol execution.
+type Option func(*Info)
+
+// WithRand returns an Option setting Info.Rand, so that all randomness of this party is read from rand.
+func WithRand(rand io.Reader) Option {
+	return func(info *Info) { info.Rand = rand }
+}
 
-	PublicPoint curve.Point
+// Apply sets the optional fields of info given by opts.
+func (info *Info) Apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(info)
+	}
 }
 
 // Session represents the current execution of a round-based protocol.
@@ -21,16 +21,16 @@ func TestAffG(t *testing.T) {
 	prover := zk.ProverPaillierPublic
 
 	c := new(saferith.Int).SetUint64(12)
-	C, _ := verifierPaillier.Enc(c)
+	C, _ := verifierPaillier.Enc(rand.Reader, c)
 
 	x := sample.IntervalL(rand.Reader)
-	X, rhoX := prover.Enc(x)
+	X, rhoX := prover.Enc(rand.Reader, x)
 
 	y := sample.IntervalL(rand.Reader)
-	Y, rhoY := prover.Enc(y)
+	Y, rhoY := prover.Enc(rand.Reader, y)
 
 	tmp := C.Clone().Mul(verifierPaillier, x)
-	D, rho := verifierPaillier.Enc(y)
+	D, rho := verifierPaillier.Enc(rand.Reader, y)
 	D.Add(verifierPaillier, tmp)
 
 	public := Public{
@@ -49,7 +49,7 @@ func TestAffG(t *testing.T) {
 		Rx: rhoX,
 		R:  rhoY,
 	}
-	proof := NewProof(group, hash.New(), public, private)
+	proof := NewProof(rand.Reader, group, hash.New(), public, private)
 	assert.True(t, proof.Verify(group, hash.New(), public))
 
 	out, err := cbor.Marshal(proof)
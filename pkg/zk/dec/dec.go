@@ -1,7 +1,7 @@
 package zkdec
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -70,14 +70,14 @@ func (p *Proof) IsValid(public Public) bool {
 	return true
 }
 
-func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+func NewProof(rand io.Reader, group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
-	alpha := sample.IntervalLEps(rand.Reader)
+	alpha := sample.IntervalLEps(rand)
 
-	mu := sample.IntervalLN(rand.Reader)
-	nu := sample.IntervalLEpsN(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
+	mu := sample.IntervalLN(rand)
+	nu := sample.IntervalLEpsN(rand)
+	r := sample.UnitModN(rand, N)
 
 	gamma := group.NewScalar().SetNat(alpha.Mod(group.Order()))
 
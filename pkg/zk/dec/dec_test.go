@@ -22,7 +22,7 @@ func TestDec(t *testing.T) {
 	y := sample.IntervalL(rand.Reader)
 	x := group.NewScalar().SetNat(y.Mod(group.Order()))
 
-	C, rho := prover.Enc(y)
+	C, rho := prover.Enc(rand.Reader, y)
 
 	public := Public{
 		C:      C,
@@ -35,7 +35,7 @@ func TestDec(t *testing.T) {
 		Rho: rho,
 	}
 
-	proof := NewProof(group, hash.New(), public, private)
+	proof := NewProof(rand.Reader, group, hash.New(), public, private)
 	assert.True(t, proof.Verify(hash.New(), public))
 
 	out, err := cbor.Marshal(proof)
@@ -1,7 +1,7 @@
 package zkenc
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -61,14 +61,14 @@ func (p *Proof) IsValid(public Public) bool {
 	return true
 }
 
-func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+func NewProof(rand io.Reader, group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	mu := sample.IntervalLN(rand.Reader)
-	gamma := sample.IntervalLEpsN(rand.Reader)
+	alpha := sample.IntervalLEps(rand)
+	r := sample.UnitModN(rand, N)
+	mu := sample.IntervalLN(rand)
+	gamma := sample.IntervalLEpsN(rand)
 
 	A := public.Prover.EncWithNonce(alpha, r)
 
@@ -1,7 +1,7 @@
 package zkencelg
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -81,16 +81,16 @@ func (p *Proof) IsValid(public Public) bool {
 	return true
 }
 
-func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+func NewProof(rand io.Reader, group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
 
-	alpha := sample.IntervalLEps(rand.Reader)
+	alpha := sample.IntervalLEps(rand)
 	alphaScalar := group.NewScalar().SetNat(alpha.Mod(group.Order()))
-	mu := sample.IntervalLN(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	beta := sample.Scalar(rand.Reader, group)
-	gamma := sample.IntervalLEpsN(rand.Reader)
+	mu := sample.IntervalLN(rand)
+	r := sample.UnitModN(rand, N)
+	beta := sample.Scalar(rand, group)
+	gamma := sample.IntervalLEpsN(rand)
 
 	commitment := &Commitment{
 		S: public.Aux.Commit(private.X, mu),
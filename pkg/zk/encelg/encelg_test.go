@@ -29,7 +29,7 @@ func TestEnc(t *testing.T) {
 	B := b.ActOnBase()
 	X := abx.ActOnBase()
 
-	C, rho := prover.Enc(x)
+	C, rho := prover.Enc(rand.Reader, x)
 	public := Public{
 		C:      C,
 		A:      A,
@@ -39,7 +39,7 @@ func TestEnc(t *testing.T) {
 		Aux:    verifier,
 	}
 
-	proof := NewProof(group, hash.New(), public, Private{
+	proof := NewProof(rand.Reader, group, hash.New(), public, Private{
 		X:   x,
 		Rho: rho,
 		A:   a,
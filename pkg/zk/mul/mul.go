@@ -1,7 +1,7 @@
 package zkmul
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -66,15 +66,15 @@ func (p *Proof) IsValid(public Public) bool {
 	return true
 }
 
-func NewProof(group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
+func NewProof(rand io.Reader, group curve.Curve, hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.Prover.N()
 	NModulus := public.Prover.Modulus()
 
 	prover := public.Prover
 
-	alpha := sample.IntervalLEps(rand.Reader)
-	r := sample.UnitModN(rand.Reader, N)
-	s := sample.UnitModN(rand.Reader, N)
+	alpha := sample.IntervalLEps(rand)
+	r := sample.UnitModN(rand, N)
+	s := sample.UnitModN(rand, N)
 
 	A := public.Y.Clone().Mul(prover, alpha)
 	A.Randomize(prover, r)
@@ -18,10 +18,10 @@ func TestMul(t *testing.T) {
 
 	prover := zk.ProverPaillierPublic
 	x := sample.IntervalL(rand.Reader)
-	X, rhoX := prover.Enc(x)
+	X, rhoX := prover.Enc(rand.Reader, x)
 
 	y := sample.IntervalL(rand.Reader)
-	Y, _ := prover.Enc(y)
+	Y, _ := prover.Enc(rand.Reader, y)
 
 	C := Y.Clone().Mul(prover, x)
 	rho := C.Randomize(prover, nil)
@@ -38,7 +38,7 @@ func TestMul(t *testing.T) {
 		RhoX: rhoX,
 	}
 
-	proof := NewProof(group, hash.New(), public, private)
+	proof := NewProof(rand.Reader, group, hash.New(), public, private)
 	assert.True(t, proof.Verify(group, hash.New(), public))
 
 	out, err := cbor.Marshal(proof)
@@ -3,7 +3,7 @@
 package zknth
 
 import (
-	"crypto/rand"
+	"io"
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -49,10 +49,10 @@ func (p *Proof) IsValid(public Public) bool {
 }
 
 // NewProof generates a proof that r = ρᴺ (mod N²).
-func NewProof(hash *hash.Hash, public Public, private Private) *Proof {
+func NewProof(rand io.Reader, hash *hash.Hash, public Public, private Private) *Proof {
 	N := public.N.N()
 	// α ← ℤₙˣ
-	alpha := sample.UnitModN(rand.Reader, N)
+	alpha := sample.UnitModN(rand, N)
 	// A = αⁿ (mod n²)
 	A := public.N.ModulusSquared().Exp(alpha, N.Nat())
 	commitment := Commitment{
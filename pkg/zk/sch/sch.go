@@ -1,7 +1,6 @@
 package zksch
 
 import (
-	"crypto/rand"
 	"io"
 
 	"github.com/w3-key/mps-lean/pkg/hash"
@@ -32,10 +31,10 @@ type Proof struct {
 }
 
 // NewProof generates a Schnorr proof of knowledge of exponent for public, using the Fiat-Shamir transform.
-func NewProof(hash *hash.Hash, public curve.Point, private curve.Scalar, gen curve.Point) *Proof {
+func NewProof(rand io.Reader, hash *hash.Hash, public curve.Point, private curve.Scalar, gen curve.Point) *Proof {
 	group := private.Curve()
 
-	a := NewRandomness(rand.Reader, group, gen)
+	a := NewRandomness(rand, group, gen)
 	z := a.Prove(hash, public, private, gen)
 	return &Proof{
 		C: *a.Commitment(),
@@ -0,0 +1,43 @@
+package auxinfo
+
+import (
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+const (
+	// Identifier for the aux info protocol.
+	protocolID = "cmp/aux-info"
+	// protocolRounds is the number of rounds of the aux info protocol.
+	protocolRounds round.Number = 4
+)
+
+// Start returns a protocol.StartFunc which generates and proves new Paillier, Pedersen and ElGamal
+// parameters for all `participants`.
+//
+// The result is a *config.AuxInfo, which does not depend on any ECDSA key and can be reused by the keygen and refresh
+// protocols run by the same participants.
+func Start(group curve.Curve, selfID party.ID, participants []party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       protocolID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           selfID,
+			PartyIDs:         participants,
+			// the aux info is not associated with any threshold
+			Threshold: 0,
+			Group:     group,
+		}
+		info.Apply(opts...)
+		helper, err := round.NewSession(info, sessionID, pl)
+		if err != nil {
+			return nil, fmt.Errorf("auxinfo: %w", err)
+		}
+		return &round1{Helper: helper}, nil
+	}
+}
@@ -0,0 +1,64 @@
+package auxinfo
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+func TestAuxInfo(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	partyIDs := test.PartyIDs(N)
+
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := Start(group, partyID, partyIDs, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	auxs := make([]*config.AuxInfo, 0, N)
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		require.IsType(t, &config.AuxInfo{}, r.(*round.Output).Result)
+		aux := r.(*round.Output).Result.(*config.AuxInfo)
+		assert.Equal(t, partyIDs, aux.PartyIDs())
+		assert.True(t, aux.ElGamal.ActOnBase().Equal(aux.Public[aux.ID].ElGamal), "ElGamal key is inconsistent")
+		assert.True(t, aux.Paillier.PublicKey.Equal(aux.Public[aux.ID].Paillier), "Paillier key is inconsistent")
+
+		data, err := cbor.Marshal(aux)
+		require.NoError(t, err)
+		aux2 := config.EmptyAuxInfo(group)
+		require.NoError(t, cbor.Unmarshal(data, aux2))
+		auxs = append(auxs, aux2)
+	}
+
+	first := auxs[0]
+	for _, aux := range auxs {
+		for id, p := range first.Public {
+			assert.True(t, p.ElGamal.Equal(aux.Public[id].ElGamal), "elgamal not the same", id)
+			assert.True(t, p.Paillier.Equal(aux.Public[id].Paillier), "paillier not the same", id)
+			assert.True(t, p.Pedersen.S().Eq(aux.Public[id].Pedersen.S()) == 1, "S not the same", id)
+			assert.True(t, p.Pedersen.T().Eq(aux.Public[id].Pedersen.T()) == 1, "T not the same", id)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package auxinfo
+
+import (
+	"errors"
+
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - sample Paillier (pᵢ, qᵢ)
+// - sample Pedersen Nᵢ, sᵢ, tᵢ
+// - sample ElGamal yᵢ, Yᵢ = yᵢ⋅G
+// - sample ridᵢ <- {0,1}ᵏ
+// - commit to message.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// generate Paillier and Pedersen
+	PaillierSecret := paillier.NewSecretKey(r.Rand(), r.Pool)
+	SelfPedersenPublic, PedersenSecret := PaillierSecret.GeneratePedersen(r.Rand())
+
+	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(r.Rand(), r.Group())
+
+	// Sample RIDᵢ
+	SelfRID, err := types.NewRID(r.Rand())
+	if err != nil {
+		return r, errors.New("failed to sample Rho")
+	}
+
+	// commit to data in message 2
+	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(r.Rand(),
+		SelfRID, ElGamalPublic, SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T())
+	if err != nil {
+		return r, errors.New("failed to commit")
+	}
+
+	if err = r.BroadcastMessage(out, &broadcast2{Commitment: SelfCommitment}); err != nil {
+		return r, err
+	}
+
+	return &round2{
+		round1:         r,
+		Commitments:    map[party.ID]hash.Commitment{r.SelfID(): SelfCommitment},
+		RIDs:           map[party.ID]types.RID{r.SelfID(): SelfRID},
+		ElGamalPublic:  map[party.ID]curve.Point{r.SelfID(): ElGamalPublic},
+		NModulus:       map[party.ID]*saferith.Modulus{r.SelfID(): SelfPedersenPublic.N()},
+		S:              map[party.ID]*saferith.Nat{r.SelfID(): SelfPedersenPublic.S()},
+		T:              map[party.ID]*saferith.Nat{r.SelfID(): SelfPedersenPublic.T()},
+		ElGamalSecret:  ElGamalSecret,
+		PaillierSecret: PaillierSecret,
+		PedersenSecret: PedersenSecret,
+		Decommitment:   Decommitment,
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
@@ -0,0 +1,101 @@
+package auxinfo
+
+import (
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+
+	// Commitments[j] = H(ridⱼ, Yⱼ, Nⱼ, sⱼ, tⱼ, uⱼ)
+	Commitments map[party.ID]hash.Commitment
+
+	// RIDs[j] = ridⱼ
+	RIDs map[party.ID]types.RID
+
+	// ElGamalPublic[j] = Yⱼ
+	ElGamalPublic map[party.ID]curve.Point
+
+	// NModulus[j] = Nⱼ
+	NModulus map[party.ID]*saferith.Modulus
+	// S[j], T[j] = sⱼ, tⱼ
+	S, T map[party.ID]*saferith.Nat
+
+	// ElGamalSecret = yᵢ
+	ElGamalSecret curve.Scalar
+
+	// PaillierSecret = (pᵢ, qᵢ)
+	PaillierSecret *paillier.SecretKey
+
+	// PedersenSecret = λᵢ
+	// Used to generate the Pedersen parameters
+	PedersenSecret *saferith.Nat
+
+	// Decommitment = uᵢ
+	Decommitment hash.Decommitment
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// Commitment = Vᵢ = H(ridᵢ, Yᵢ, Nᵢ, sᵢ, tᵢ, uᵢ)
+	Commitment hash.Commitment
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - save commitment Vⱼ.
+func (r *round2) StoreBroadcastMessage(msg round.Message) error {
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if err := body.Commitment.Validate(); err != nil {
+		return err
+	}
+	r.Commitments[msg.From] = body.Commitment
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - send all committed data.
+func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	err := r.BroadcastMessage(out, &broadcast3{
+		RID:           r.RIDs[r.SelfID()],
+		ElGamalPublic: r.ElGamalPublic[r.SelfID()],
+		N:             r.NModulus[r.SelfID()],
+		S:             r.S[r.SelfID()],
+		T:             r.T[r.SelfID()],
+		Decommitment:  r.Decommitment,
+	})
+	if err != nil {
+		return r, err
+	}
+	return &round3{round2: r}, nil
+}
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (round2) BroadcastContent() round.BroadcastContent { return &broadcast2{} }
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
@@ -0,0 +1,154 @@
+package auxinfo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/pedersen"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	zkmod "github.com/w3-key/mps-lean/pkg/zk/mod"
+	zkprm "github.com/w3-key/mps-lean/pkg/zk/prm"
+)
+
+var _ round.Round = (*round3)(nil)
+
+type round3 struct {
+	*round2
+}
+
+type broadcast3 struct {
+	round.NormalBroadcastContent
+	// RID = ridᵢ
+	RID types.RID
+	// ElGamalPublic = Yᵢ
+	ElGamalPublic curve.Point
+	// N Paillier and Pedersen N = p•q, p ≡ q ≡ 3 mod 4
+	N *saferith.Modulus
+	// S = r² mod N
+	S *saferith.Nat
+	// T = Sˡ mod N
+	T *saferith.Nat
+	// Decommitment = uᵢ decommitment bytes
+	Decommitment hash.Decommitment
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - validate Paillier
+// - validate Pedersen
+// - validate commitments.
+// - store ridⱼ, Yⱼ, Nⱼ, Sⱼ, Tⱼ.
+func (r *round3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	// check nil
+	if body.N == nil || body.S == nil || body.T == nil {
+		return round.ErrNilFields
+	}
+	// check RID length
+	if err := body.RID.Validate(); err != nil {
+		return fmt.Errorf("rid: %w", err)
+	}
+	// check decommitment
+	if err := body.Decommitment.Validate(); err != nil {
+		return err
+	}
+	if body.ElGamalPublic.IsIdentity() {
+		return errors.New("ElGamal public key is identity")
+	}
+
+	// Set Paillier
+	if err := paillier.ValidateN(body.N); err != nil {
+		return err
+	}
+
+	// Verify Pedersen
+	if err := pedersen.ValidateParameters(body.N, body.S, body.T); err != nil {
+		return err
+	}
+	// Verify decommit
+	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment,
+		body.RID, body.ElGamalPublic, body.N, body.S, body.T) {
+		return errors.New("failed to decommit")
+	}
+	r.RIDs[from] = body.RID
+	r.ElGamalPublic[from] = body.ElGamalPublic
+	r.NModulus[from] = body.N
+	r.S[from] = body.S
+	r.T[from] = body.T
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round3) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round3) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - set rid = ⊕ⱼ ridⱼ and update hash state
+// - prove Nᵢ is Blum
+// - prove Pedersen parameters.
+func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// RID = ⊕ⱼ RIDⱼ
+	rid := types.EmptyRID()
+	for _, j := range r.PartyIDs() {
+		rid.XOR(r.RIDs[j])
+	}
+
+	// temporary hash which does not modify the state
+	h := r.Hash()
+	_ = h.WriteAny(rid, r.SelfID())
+
+	// Prove N is a blum prime with zkmod
+	mod := zkmod.NewProof(r.Rand(), h.Clone(), zkmod.Private{
+		P:   r.PaillierSecret.P(),
+		Q:   r.PaillierSecret.Q(),
+		Phi: r.PaillierSecret.Phi(),
+	}, zkmod.Public{N: r.NModulus[r.SelfID()]}, r.Pool)
+
+	// prove s, t are correct as aux parameters with zkprm
+	prm := zkprm.NewProof(r.Rand(), zkprm.Private{
+		Lambda: r.PedersenSecret,
+		Phi:    r.PaillierSecret.Phi(),
+		P:      r.PaillierSecret.P(),
+		Q:      r.PaillierSecret.Q(),
+	}, h.Clone(), zkprm.Public{N: r.NModulus[r.SelfID()], S: r.S[r.SelfID()], T: r.T[r.SelfID()]}, r.Pool)
+
+	if err := r.BroadcastMessage(out, &broadcast4{
+		Mod: mod,
+		Prm: prm,
+	}); err != nil {
+		return r, err
+	}
+
+	// Write rid to the hash state
+	r.UpdateHashState(rid)
+	return &round4{round3: r}, nil
+}
+
+// MessageContent implements round.Round.
+func (round3) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast3) RoundNumber() round.Number { return 3 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round3) BroadcastContent() round.BroadcastContent {
+	return &broadcast3{
+		ElGamalPublic: r.Group().NewPoint(),
+	}
+}
+
+// Number implements round.Round.
+func (round3) Number() round.Number { return 3 }
@@ -0,0 +1,87 @@
+package auxinfo
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pedersen"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zkmod "github.com/w3-key/mps-lean/pkg/zk/mod"
+	zkprm "github.com/w3-key/mps-lean/pkg/zk/prm"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+var _ round.Round = (*round4)(nil)
+
+type round4 struct {
+	*round3
+}
+
+type broadcast4 struct {
+	round.NormalBroadcastContent
+	Mod *zkmod.Proof
+	Prm *zkprm.Proof
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify Mod, Prm proof for N
+func (r *round4) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast4)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	// verify zkmod
+	if !body.Mod.Verify(zkmod.Public{N: r.NModulus[from]}, r.HashForID(from), r.Pool) {
+		return errors.New("failed to validate mod proof")
+	}
+
+	// verify zkprm
+	if !body.Prm.Verify(zkprm.Public{N: r.NModulus[from], S: r.S[from], T: r.T[from]}, r.HashForID(from), r.Pool) {
+		return errors.New("failed to validate prm proof")
+	}
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round4) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round4) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - output the aux info of all parties.
+func (r *round4) Finalize(chan<- *round.Message) (round.Session, error) {
+	Public := make(map[party.ID]*config.Public, r.N())
+	for _, j := range r.PartyIDs() {
+		PaillierPublic := paillier.NewPublicKey(r.NModulus[j])
+		Public[j] = &config.Public{
+			ElGamal:  r.ElGamalPublic[j],
+			Paillier: PaillierPublic,
+			Pedersen: pedersen.New(PaillierPublic.Modulus(), r.S[j], r.T[j]),
+		}
+	}
+	return r.ResultRound(&config.AuxInfo{
+		Group:    r.Group(),
+		ID:       r.SelfID(),
+		ElGamal:  r.ElGamalSecret,
+		Paillier: r.PaillierSecret,
+		Public:   Public,
+	}), nil
+}
+
+// MessageContent implements round.Round.
+func (round4) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast4) RoundNumber() round.Number { return 4 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (round4) BroadcastContent() round.BroadcastContent { return &broadcast4{} }
+
+// Number implements round.Round.
+func (round4) Number() round.Number { return 4 }
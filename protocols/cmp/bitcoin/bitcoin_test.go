@@ -0,0 +1,245 @@
+package bitcoin
+
+import (
+	"bytes"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/bip32"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/cmp"
+)
+
+func p2wpkh(t *testing.T, pubKey []byte) []byte {
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(btcutil.Hash160(pubKey)).Script()
+	require.NoError(t, err)
+	return script
+}
+
+func p2sh(t *testing.T, redeemScript []byte) []byte {
+	script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).AddData(btcutil.Hash160(redeemScript)).AddOp(txscript.OP_EQUAL).Script()
+	require.NoError(t, err)
+	return script
+}
+
+// roundTrip serializes and parses p, and checks that the serialization is stable.
+func roundTrip(t *testing.T, p *Packet) *Packet {
+	encoded, err := p.Base64()
+	require.NoError(t, err)
+	parsed, err := ParsePacketBase64(encoded)
+	require.NoError(t, err)
+	reencoded, err := parsed.Base64()
+	require.NoError(t, err)
+	require.Equal(t, encoded, reencoded)
+	return parsed
+}
+
+func signInput(t *testing.T, configs map[party.ID]*cmp.Config, signers []party.ID, input InputToSign) *ecdsa.Signature {
+	rounds := make([]round.Session, 0, len(signers))
+	for _, id := range signers {
+		r, err := SignInput(configs[id], signers, input, nil)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	return rounds[0].(*round.Output).Result.(*ecdsa.Signature)
+}
+
+func TestSignPSBT(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs := test.GenerateConfig(curve.Secp256k1{}, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	signers := partyIDs[1:]
+	config := configs[partyIDs[0]]
+
+	master := config.PublicPoint().(*curve.Secp256k1Point)
+	masterKey, err := master.MarshalBinary()
+	require.NoError(t, err)
+	xpub, err := config.ExtendedPublicKey(bip32.MainnetPublic)
+	require.NoError(t, err)
+	child, err := xpub.DerivePath("m/0/5")
+	require.NoError(t, err)
+	childKey, err := child.PublicKey.MarshalBinary()
+	require.NoError(t, err)
+	foreign, err := btcec.NewPrivateKey(btcec.S256())
+	require.NoError(t, err)
+	foreignKey := foreign.PubKey().SerializeCompressed()
+
+	// outputs paying to the master key, to a child key nested in P2SH, and to a key which is not ours
+	childRedeemScript := p2wpkh(t, childKey)
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 0}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(100000, p2wpkh(t, masterKey)))
+	prevTx.AddTxOut(wire.NewTxOut(200000, p2sh(t, childRedeemScript)))
+	prevTx.AddTxOut(wire.NewTxOut(300000, p2wpkh(t, foreignKey)))
+
+	tx := wire.NewMsgTx(2)
+	for i := uint32(0); i < 3; i++ {
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: prevTx.TxHash(), Index: i}, nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(590000, p2wpkh(t, foreignKey)))
+
+	p := &Packet{
+		UnsignedTx: tx,
+		Unknowns:   []Unknown{{Key: []byte{0xfc, 1}, Value: []byte("proprietary")}},
+		Inputs: []Input{
+			{WitnessUtxo: prevTx.TxOut[0]},
+			{
+				NonWitnessUtxo: prevTx,
+				RedeemScript:   childRedeemScript,
+				Bip32Derivation: []Bip32Derivation{
+					{PubKey: childKey, Fingerprint: bip32.Fingerprint(master), Path: []uint32{0, 5}},
+				},
+			},
+			{WitnessUtxo: prevTx.TxOut[2], SighashType: txscript.SigHashAll},
+		},
+		Outputs: []Output{{}},
+	}
+	p = roundTrip(t, p)
+
+	inputs, err := InputsToSign(config, p)
+	require.NoError(t, err)
+	require.Len(t, inputs, 2, "only the inputs of the config should be signed")
+	assert.Equal(t, 0, inputs[0].Index)
+	assert.Nil(t, inputs[0].Tweak)
+	assert.Equal(t, 1, inputs[1].Index)
+	assert.NotNil(t, inputs[1].Tweak)
+	assert.True(t, inputs[1].PublicKey.Equal(child.PublicKey))
+
+	for _, input := range inputs {
+		sig := signInput(t, configs, signers, input)
+		require.NoError(t, AddSignature(p, input, sig))
+		assert.Error(t, AddSignature(p, input, sig), "input should only be signed once")
+		// the signature is in DER form, with a low s
+		signature := p.Inputs[input.Index].PartialSigs[0].Signature
+		_, err = btcec.ParseDERSignature(signature[:len(signature)-1], btcec.S256())
+		require.NoError(t, err)
+		assert.Equal(t, byte(txscript.SigHashAll), signature[len(signature)-1])
+	}
+	p = roundTrip(t, p)
+	inputs, err = InputsToSign(config, p)
+	require.NoError(t, err)
+	assert.Empty(t, inputs, "signed inputs should not be returned again")
+
+	// the input which is not ours remains
+	require.Error(t, Finalize(p))
+	_, err = p.Extract()
+	require.Error(t, err)
+	sigHashes := txscript.NewTxSigHashes(p.UnsignedTx)
+	witness, err := txscript.WitnessSignature(p.UnsignedTx, sigHashes, 2, 300000, prevTx.TxOut[2].PkScript, txscript.SigHashAll, foreign, true)
+	require.NoError(t, err)
+	p.Inputs[2].FinalScriptWitness = writeWitness(witness)
+	require.NoError(t, Finalize(p))
+	p = roundTrip(t, p)
+	assert.Nil(t, p.Inputs[1].RedeemScript, "finalized inputs should be cleared")
+	assert.Nil(t, p.Inputs[1].Bip32Derivation, "finalized inputs should be cleared")
+	assert.NotNil(t, p.Inputs[1].NonWitnessUtxo, "the utxo should be kept")
+
+	signed, err := p.Extract()
+	require.NoError(t, err)
+	sigHashes = txscript.NewTxSigHashes(signed)
+	for i, out := range prevTx.TxOut {
+		vm, err := txscript.NewEngine(out.PkScript, signed, i, txscript.StandardVerifyFlags, nil, sigHashes, out.Value)
+		require.NoError(t, err)
+		assert.NoError(t, vm.Execute(), "input %d should be valid", i)
+	}
+}
+
+func TestParsePacketErrors(t *testing.T) {
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 0}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1000, []byte{txscript.OP_TRUE}))
+	p := &Packet{UnsignedTx: tx, Inputs: []Input{{}}, Outputs: []Output{{}}}
+	data, err := p.Serialize()
+	require.NoError(t, err)
+	_, err = ParsePacket(data)
+	require.NoError(t, err)
+
+	_, err = ParsePacket(data[1:])
+	assert.Error(t, err, "invalid magic")
+	_, err = ParsePacket(data[:len(data)-1])
+	assert.Error(t, err, "missing output map")
+	_, err = ParsePacket(append(data, 0))
+	assert.Error(t, err, "trailing data")
+
+	// the same key twice in the global map
+	var txBytes bytes.Buffer
+	require.NoError(t, tx.SerializeNoWitness(&txBytes))
+	var duplicate bytes.Buffer
+	duplicate.Write(psbtMagic)
+	require.NoError(t, writeMap(&duplicate, []Unknown{
+		{Key: []byte{globalUnsignedTx}, Value: txBytes.Bytes()},
+		{Key: []byte{globalUnsignedTx}, Value: txBytes.Bytes()},
+	}))
+	_, err = ParsePacket(duplicate.Bytes())
+	assert.Error(t, err, "duplicate key")
+
+	// a signed transaction is not a valid unsigned transaction
+	tx.TxIn[0].SignatureScript = []byte{txscript.OP_TRUE}
+	data, err = p.Serialize()
+	require.NoError(t, err)
+	_, err = ParsePacket(data)
+	assert.Error(t, err, "signed unsigned transaction")
+}
+
+func TestInputsToSignRejects(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs := test.GenerateConfig(curve.Secp256k1{}, 2, 1, mrand.New(mrand.NewSource(1)), pl)
+	config := configs[partyIDs[0]]
+	masterKey, err := config.PublicPoint().(*curve.Secp256k1Point).MarshalBinary()
+	require.NoError(t, err)
+
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 0}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(100000, p2wpkh(t, masterKey)))
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: prevTx.TxHash(), Index: 0}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(90000, []byte{txscript.OP_TRUE}))
+	newPacket := func(in Input) *Packet {
+		return roundTrip(t, &Packet{UnsignedTx: tx, Inputs: []Input{in}, Outputs: []Output{{}}})
+	}
+
+	t.Run("sighash", func(t *testing.T) {
+		p := newPacket(Input{WitnessUtxo: prevTx.TxOut[0], SighashType: txscript.SigHashSingle | txscript.SigHashAnyOneCanPay})
+		_, err := InputsToSign(config, p)
+		assert.Error(t, err, "only SIGHASH_ALL should be signed by default")
+		_, err = InputsToSign(config, p, txscript.SigHashSingle)
+		assert.Error(t, err, "the anyone can pay flag should be allowed separately")
+		inputs, err := InputsToSign(config, p, txscript.SigHashSingle|txscript.SigHashAnyOneCanPay)
+		require.NoError(t, err)
+		require.Len(t, inputs, 1)
+		assert.Equal(t, txscript.SigHashSingle|txscript.SigHashAnyOneCanPay, inputs[0].SighashType)
+	})
+
+	t.Run("witness utxo", func(t *testing.T) {
+		// an updater lying about the amount spent by the input
+		p := newPacket(Input{WitnessUtxo: wire.NewTxOut(1000000, prevTx.TxOut[0].PkScript), NonWitnessUtxo: prevTx})
+		_, err := InputsToSign(config, p)
+		assert.Error(t, err, "the witness utxo should match the non-witness utxo")
+
+		p = newPacket(Input{WitnessUtxo: prevTx.TxOut[0], NonWitnessUtxo: prevTx})
+		inputs, err := InputsToSign(config, p)
+		require.NoError(t, err)
+		assert.Len(t, inputs, 1)
+	})
+}
@@ -0,0 +1,480 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// psbtMagic starts every serialized PSBT.
+var psbtMagic = []byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// maxPSBTSize bounds the size of a parsed PSBT, and of any of its fields.
+const maxPSBTSize = 10_000_000
+
+// key types of BIP-174 which are interpreted by this package.
+const (
+	globalUnsignedTx byte = 0x00
+
+	inputNonWitnessUtxo     byte = 0x00
+	inputWitnessUtxo        byte = 0x01
+	inputPartialSig         byte = 0x02
+	inputSighashType        byte = 0x03
+	inputRedeemScript       byte = 0x04
+	inputWitnessScript      byte = 0x05
+	inputBip32Derivation    byte = 0x06
+	inputFinalScriptSig     byte = 0x07
+	inputFinalScriptWitness byte = 0x08
+)
+
+// Unknown is a key-value pair of a PSBT map which is not interpreted by this package.
+// It is kept as is, so that it is not lost when the PSBT is serialized again.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// PartialSig is a signature of an input by PubKey, together with its sighash type.
+type PartialSig struct {
+	// PubKey is the SEC1 encoded public key.
+	PubKey []byte
+	// Signature is the DER encoded signature, followed by the sighash type.
+	Signature []byte
+}
+
+// Bip32Derivation describes the BIP-32 derivation of PubKey, from the master key with fingerprint Fingerprint.
+type Bip32Derivation struct {
+	// PubKey is the SEC1 encoded public key.
+	PubKey []byte
+	// Fingerprint is the fingerprint of the master key, as defined by bip32.Fingerprint.
+	Fingerprint uint32
+	// Path is the list of indices from the master key to PubKey.
+	Path []uint32
+}
+
+// Input contains the information about an input of the unsigned transaction of a PSBT.
+type Input struct {
+	NonWitnessUtxo     *wire.MsgTx
+	WitnessUtxo        *wire.TxOut
+	PartialSigs        []PartialSig
+	SighashType        txscript.SigHashType
+	RedeemScript       []byte
+	WitnessScript      []byte
+	Bip32Derivation    []Bip32Derivation
+	FinalScriptSig     []byte
+	FinalScriptWitness []byte
+	Unknowns           []Unknown
+}
+
+// Output contains the information about an output of the unsigned transaction of a PSBT.
+//
+// Since signing does not depend on it, none of its fields are interpreted.
+type Output struct {
+	Unknowns []Unknown
+}
+
+// Packet is a partially signed Bitcoin transaction, as defined by BIP-174.
+type Packet struct {
+	// UnsignedTx is the transaction being signed, with empty signature scripts and witnesses.
+	UnsignedTx *wire.MsgTx
+	// Unknowns are the global fields other than the unsigned transaction.
+	Unknowns []Unknown
+	// Inputs contains an entry for each input of UnsignedTx.
+	Inputs []Input
+	// Outputs contains an entry for each output of UnsignedTx.
+	Outputs []Output
+}
+
+// ParsePacket parses the binary serialization of a PSBT.
+func ParsePacket(data []byte) (*Packet, error) {
+	if len(data) > maxPSBTSize {
+		return nil, errors.New("psbt: too large")
+	}
+	if !bytes.HasPrefix(data, psbtMagic) {
+		return nil, errors.New("psbt: invalid magic bytes")
+	}
+	r := bytes.NewReader(data[len(psbtMagic):])
+
+	global, err := readMap(r)
+	if err != nil {
+		return nil, fmt.Errorf("psbt: global map: %w", err)
+	}
+	p := &Packet{}
+	for _, kv := range global {
+		if kv.Key[0] != globalUnsignedTx {
+			p.Unknowns = append(p.Unknowns, kv)
+			continue
+		}
+		if len(kv.Key) != 1 {
+			return nil, errors.New("psbt: invalid unsigned transaction key")
+		}
+		tx := wire.NewMsgTx(wire.TxVersion)
+		if err = tx.DeserializeNoWitness(bytes.NewReader(kv.Value)); err != nil {
+			return nil, fmt.Errorf("psbt: unsigned transaction: %w", err)
+		}
+		for _, in := range tx.TxIn {
+			if len(in.SignatureScript) != 0 || len(in.Witness) != 0 {
+				return nil, errors.New("psbt: unsigned transaction has signatures")
+			}
+		}
+		p.UnsignedTx = tx
+	}
+	if p.UnsignedTx == nil {
+		return nil, errors.New("psbt: missing unsigned transaction")
+	}
+
+	p.Inputs = make([]Input, len(p.UnsignedTx.TxIn))
+	for i := range p.Inputs {
+		kvs, err := readMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("psbt: input %d: %w", i, err)
+		}
+		if err = p.Inputs[i].parse(kvs); err != nil {
+			return nil, fmt.Errorf("psbt: input %d: %w", i, err)
+		}
+		if utxo := p.Inputs[i].NonWitnessUtxo; utxo != nil && utxo.TxHash() != p.UnsignedTx.TxIn[i].PreviousOutPoint.Hash {
+			return nil, fmt.Errorf("psbt: input %d: non-witness utxo does not match the outpoint", i)
+		}
+	}
+	p.Outputs = make([]Output, len(p.UnsignedTx.TxOut))
+	for i := range p.Outputs {
+		kvs, err := readMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("psbt: output %d: %w", i, err)
+		}
+		p.Outputs[i].Unknowns = kvs
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("psbt: trailing data")
+	}
+	return p, nil
+}
+
+// ParsePacketBase64 parses the base64 serialization of a PSBT, as used by bitcoind's RPC.
+func ParsePacketBase64(s string) (*Packet, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("psbt: %w", err)
+	}
+	return ParsePacket(data)
+}
+
+// Serialize returns the binary serialization of p.
+func (p *Packet) Serialize() ([]byte, error) {
+	if p.UnsignedTx == nil || len(p.Inputs) != len(p.UnsignedTx.TxIn) || len(p.Outputs) != len(p.UnsignedTx.TxOut) {
+		return nil, errors.New("psbt: inputs and outputs do not match the unsigned transaction")
+	}
+	var w bytes.Buffer
+	w.Write(psbtMagic)
+
+	var tx bytes.Buffer
+	if err := p.UnsignedTx.SerializeNoWitness(&tx); err != nil {
+		return nil, err
+	}
+	global := append([]Unknown{{Key: []byte{globalUnsignedTx}, Value: tx.Bytes()}}, p.Unknowns...)
+	if err := writeMap(&w, global); err != nil {
+		return nil, err
+	}
+	for i := range p.Inputs {
+		kvs, err := p.Inputs[i].serialize()
+		if err != nil {
+			return nil, fmt.Errorf("psbt: input %d: %w", i, err)
+		}
+		if err = writeMap(&w, kvs); err != nil {
+			return nil, err
+		}
+	}
+	for _, out := range p.Outputs {
+		if err := writeMap(&w, out.Unknowns); err != nil {
+			return nil, err
+		}
+	}
+	return w.Bytes(), nil
+}
+
+// Base64 returns the base64 serialization of p, as used by bitcoind's RPC.
+func (p *Packet) Base64() (string, error) {
+	data, err := p.Serialize()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// IsFinalized returns true if all inputs of p have a final script signature or witness.
+func (p *Packet) IsFinalized() bool {
+	for _, in := range p.Inputs {
+		if in.FinalScriptSig == nil && in.FinalScriptWitness == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Extract returns the signed transaction of a finalized PSBT.
+func (p *Packet) Extract() (*wire.MsgTx, error) {
+	if !p.IsFinalized() {
+		return nil, errors.New("psbt: not all inputs are finalized")
+	}
+	tx := p.UnsignedTx.Copy()
+	for i, in := range p.Inputs {
+		tx.TxIn[i].SignatureScript = in.FinalScriptSig
+		if in.FinalScriptWitness != nil {
+			witness, err := readWitness(in.FinalScriptWitness)
+			if err != nil {
+				return nil, fmt.Errorf("psbt: input %d: %w", i, err)
+			}
+			tx.TxIn[i].Witness = witness
+		}
+	}
+	return tx, nil
+}
+
+// errMissingUtxo is returned by Packet.utxo if an input has neither a witness nor a non-witness utxo.
+var errMissingUtxo = errors.New("psbt: missing utxo")
+
+// utxo returns the output spent by the ith input of p.
+//
+// If the input has both a witness and a non-witness utxo, the witness utxo must be the output referenced
+// in the non-witness utxo, whose hash is checked against the outpoint when parsing.
+// Otherwise, an updater could lie about the amount of a SegWit input, which is only committed to by the signature.
+func (p *Packet) utxo(i int) (*wire.TxOut, error) {
+	in := &p.Inputs[i]
+	var referenced *wire.TxOut
+	if in.NonWitnessUtxo != nil {
+		index := p.UnsignedTx.TxIn[i].PreviousOutPoint.Index
+		if int(index) >= len(in.NonWitnessUtxo.TxOut) {
+			return nil, errors.New("psbt: outpoint index out of range")
+		}
+		referenced = in.NonWitnessUtxo.TxOut[index]
+	}
+	switch {
+	case in.WitnessUtxo == nil && referenced == nil:
+		return nil, errMissingUtxo
+	case in.WitnessUtxo == nil:
+		return referenced, nil
+	case referenced != nil && (in.WitnessUtxo.Value != referenced.Value || !bytes.Equal(in.WitnessUtxo.PkScript, referenced.PkScript)):
+		return nil, errors.New("psbt: witness utxo does not match the non-witness utxo")
+	}
+	return in.WitnessUtxo, nil
+}
+
+func (in *Input) parse(kvs []Unknown) error {
+	for _, kv := range kvs {
+		keyData := kv.Key[1:]
+		switch kv.Key[0] {
+		case inputNonWitnessUtxo:
+			if len(keyData) != 0 {
+				return errors.New("invalid non-witness utxo key")
+			}
+			tx := wire.NewMsgTx(wire.TxVersion)
+			if err := tx.Deserialize(bytes.NewReader(kv.Value)); err != nil {
+				return fmt.Errorf("non-witness utxo: %w", err)
+			}
+			in.NonWitnessUtxo = tx
+		case inputWitnessUtxo:
+			if len(keyData) != 0 {
+				return errors.New("invalid witness utxo key")
+			}
+			out, err := readTxOut(kv.Value)
+			if err != nil {
+				return fmt.Errorf("witness utxo: %w", err)
+			}
+			in.WitnessUtxo = out
+		case inputPartialSig:
+			if !validPubKey(keyData) {
+				return errors.New("invalid partial signature key")
+			}
+			in.PartialSigs = append(in.PartialSigs, PartialSig{PubKey: keyData, Signature: kv.Value})
+		case inputSighashType:
+			if len(keyData) != 0 || len(kv.Value) != 4 {
+				return errors.New("invalid sighash type")
+			}
+			in.SighashType = txscript.SigHashType(binary.LittleEndian.Uint32(kv.Value))
+		case inputRedeemScript:
+			if len(keyData) != 0 {
+				return errors.New("invalid redeem script key")
+			}
+			in.RedeemScript = kv.Value
+		case inputWitnessScript:
+			if len(keyData) != 0 {
+				return errors.New("invalid witness script key")
+			}
+			in.WitnessScript = kv.Value
+		case inputBip32Derivation:
+			if !validPubKey(keyData) || len(kv.Value) < 4 || len(kv.Value)%4 != 0 {
+				return errors.New("invalid bip32 derivation")
+			}
+			derivation := Bip32Derivation{
+				PubKey:      keyData,
+				Fingerprint: binary.BigEndian.Uint32(kv.Value[:4]),
+			}
+			for j := 4; j < len(kv.Value); j += 4 {
+				derivation.Path = append(derivation.Path, binary.LittleEndian.Uint32(kv.Value[j:]))
+			}
+			in.Bip32Derivation = append(in.Bip32Derivation, derivation)
+		case inputFinalScriptSig:
+			if len(keyData) != 0 {
+				return errors.New("invalid final script sig key")
+			}
+			in.FinalScriptSig = kv.Value
+		case inputFinalScriptWitness:
+			if len(keyData) != 0 {
+				return errors.New("invalid final script witness key")
+			}
+			in.FinalScriptWitness = kv.Value
+		default:
+			in.Unknowns = append(in.Unknowns, kv)
+		}
+	}
+	return nil
+}
+
+func (in *Input) serialize() ([]Unknown, error) {
+	var kvs []Unknown
+	add := func(keyType byte, keyData, value []byte) {
+		kvs = append(kvs, Unknown{Key: append([]byte{keyType}, keyData...), Value: value})
+	}
+	if in.NonWitnessUtxo != nil {
+		var buf bytes.Buffer
+		if err := in.NonWitnessUtxo.Serialize(&buf); err != nil {
+			return nil, err
+		}
+		add(inputNonWitnessUtxo, nil, buf.Bytes())
+	}
+	if in.WitnessUtxo != nil {
+		var buf bytes.Buffer
+		if err := wire.WriteTxOut(&buf, 0, 0, in.WitnessUtxo); err != nil {
+			return nil, err
+		}
+		add(inputWitnessUtxo, nil, buf.Bytes())
+	}
+	partialSigs := append([]PartialSig{}, in.PartialSigs...)
+	sort.Slice(partialSigs, func(i, j int) bool { return bytes.Compare(partialSigs[i].PubKey, partialSigs[j].PubKey) < 0 })
+	for _, sig := range partialSigs {
+		add(inputPartialSig, sig.PubKey, sig.Signature)
+	}
+	if in.SighashType != 0 {
+		value := make([]byte, 4)
+		binary.LittleEndian.PutUint32(value, uint32(in.SighashType))
+		add(inputSighashType, nil, value)
+	}
+	if in.RedeemScript != nil {
+		add(inputRedeemScript, nil, in.RedeemScript)
+	}
+	if in.WitnessScript != nil {
+		add(inputWitnessScript, nil, in.WitnessScript)
+	}
+	for _, derivation := range in.Bip32Derivation {
+		value := make([]byte, 4, 4+4*len(derivation.Path))
+		binary.BigEndian.PutUint32(value, derivation.Fingerprint)
+		for _, index := range derivation.Path {
+			value = binary.LittleEndian.AppendUint32(value, index)
+		}
+		add(inputBip32Derivation, derivation.PubKey, value)
+	}
+	if in.FinalScriptSig != nil {
+		add(inputFinalScriptSig, nil, in.FinalScriptSig)
+	}
+	if in.FinalScriptWitness != nil {
+		add(inputFinalScriptWitness, nil, in.FinalScriptWitness)
+	}
+	return append(kvs, in.Unknowns...), nil
+}
+
+// validPubKey returns true if key has the length of a compressed or uncompressed SEC1 public key.
+func validPubKey(key []byte) bool {
+	return len(key) == 33 || len(key) == 65
+}
+
+// readMap reads key-value pairs until the separator 0x00, and checks that no key is repeated.
+func readMap(r *bytes.Reader) ([]Unknown, error) {
+	var kvs []Unknown
+	seen := make(map[string]bool)
+	for {
+		key, err := wire.ReadVarBytes(r, 0, maxPSBTSize, "key")
+		if err != nil {
+			return nil, err
+		}
+		if len(key) == 0 {
+			return kvs, nil
+		}
+		if seen[string(key)] {
+			return nil, fmt.Errorf("duplicate key %x", key)
+		}
+		seen[string(key)] = true
+		value, err := wire.ReadVarBytes(r, 0, maxPSBTSize, "value")
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, Unknown{Key: key, Value: value})
+	}
+}
+
+// writeMap writes the key-value pairs kvs, followed by the separator 0x00.
+func writeMap(w io.Writer, kvs []Unknown) error {
+	for _, kv := range kvs {
+		if err := wire.WriteVarBytes(w, 0, kv.Key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, kv.Value); err != nil {
+			return err
+		}
+	}
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+func readTxOut(data []byte) (*wire.TxOut, error) {
+	if len(data) < 8 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	r := bytes.NewReader(data[8:])
+	pkScript, err := wire.ReadVarBytes(r, 0, maxPSBTSize, "pkScript")
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("trailing data")
+	}
+	return wire.NewTxOut(int64(binary.LittleEndian.Uint64(data)), pkScript), nil
+}
+
+// readWitness parses a serialized witness stack, as found in a final script witness.
+func readWitness(data []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(data)
+	n, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(data)) {
+		return nil, errors.New("invalid witness")
+	}
+	witness := make(wire.TxWitness, n)
+	for i := range witness {
+		if witness[i], err = wire.ReadVarBytes(r, 0, maxPSBTSize, "witness"); err != nil {
+			return nil, err
+		}
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("trailing data in witness")
+	}
+	return witness, nil
+}
+
+// writeWitness serializes a witness stack, as stored in a final script witness.
+func writeWitness(witness wire.TxWitness) []byte {
+	var buf bytes.Buffer
+	_ = wire.WriteVarInt(&buf, 0, uint64(len(witness)))
+	for _, item := range witness {
+		_ = wire.WriteVarBytes(&buf, 0, item)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,263 @@
+// Package bitcoin signs the SegWit v0 inputs of BIP-174 partially signed Bitcoin transactions with a threshold ECDSA
+// key generated by cmp.Keygen.
+//
+// Inputs spending P2WPKH and P2SH-P2WPKH outputs are supported. An input is signed if it pays to the public key of
+// the config, or to one of its unhardened BIP-32 descendants, as described by a BIP-32 derivation of the input whose
+// fingerprint is the one of the config.
+//
+// Signing a PSBT consists of:
+//   - InputsToSign, which returns the BIP-143 sighash of each input owned by the config,
+//   - SignInput for each of these, which runs cmp.Sign, or cmp.SignWithTweak for a derived key,
+//   - AddSignature with the resulting signature,
+//   - Finalize, once all inputs are signed, after which the transaction is obtained with Packet.Extract.
+package bitcoin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/w3-key/mps-lean/pkg/bip32"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/cmp"
+)
+
+// InputToSign describes an input of a PSBT which can be signed by a config.
+type InputToSign struct {
+	// Index is the index of the input in the unsigned transaction.
+	Index int
+	// Hash is the BIP-143 sighash of the input.
+	Hash []byte
+	// SighashType is the sighash type of the input, appended to the DER encoded signature.
+	SighashType txscript.SigHashType
+	// Tweak is added to the key of the config to obtain the key of the input, or nil if it is the key of the config.
+	Tweak curve.Scalar
+	// PublicKey is the public key of the input.
+	PublicKey *curve.Secp256k1Point
+}
+
+// InputsToSign returns the inputs of p which pay to the key of config, or to one of its descendants,
+// and have not been signed by it yet.
+//
+// Inputs which do not belong to config, or whose utxo is missing, are skipped.
+// An error is returned if an input has inconsistent utxos, or a redeem script for a key of config which does not
+// match its utxo.
+//
+// Only SIGHASH_ALL is signed by default, since any other sighash type lets the other parties of the transaction
+// change the parts of it which are not committed to. Other types must be explicitly given in allowedSighashTypes.
+func InputsToSign(config *cmp.Config, p *Packet, allowedSighashTypes ...txscript.SigHashType) ([]InputToSign, error) {
+	publicKey, ok := config.PublicPoint().(*curve.Secp256k1Point)
+	if !ok {
+		return nil, errors.New("bitcoin: config is not over secp256k1")
+	}
+	fingerprint := bip32.Fingerprint(publicKey)
+	sigHashes := txscript.NewTxSigHashes(p.UnsignedTx)
+
+	var inputs []InputToSign
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if in.FinalScriptSig != nil || in.FinalScriptWitness != nil {
+			continue
+		}
+		utxo, err := p.utxo(i)
+		if errors.Is(err, errMissingUtxo) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bitcoin: input %d: %w", i, err)
+		}
+
+		// the key of config, followed by its descendants listed in the input
+		candidates := []InputToSign{{Index: i, PublicKey: publicKey}}
+		for _, derivation := range in.Bip32Derivation {
+			if derivation.Fingerprint != fingerprint || len(derivation.Path) == 0 {
+				continue
+			}
+			tweak, child, err := config.DeriveTweak(formatPath(derivation.Path))
+			if err != nil {
+				continue
+			}
+			if childBytes, _ := child.PublicKey.MarshalBinary(); !bytes.Equal(childBytes, derivation.PubKey) {
+				continue
+			}
+			candidates = append(candidates, InputToSign{Index: i, Tweak: tweak, PublicKey: child.PublicKey})
+		}
+
+		for _, candidate := range candidates {
+			pubKey, _ := candidate.PublicKey.MarshalBinary()
+			witnessProgram, err := spendsP2WPKH(in, utxo.PkScript, pubKey)
+			if err != nil {
+				return nil, fmt.Errorf("bitcoin: input %d: %w", i, err)
+			}
+			if witnessProgram == nil {
+				continue
+			}
+			if hasPartialSig(in, pubKey) {
+				break
+			}
+			candidate.SighashType = in.SighashType
+			if candidate.SighashType == 0 {
+				candidate.SighashType = txscript.SigHashAll
+			}
+			if !sighashAllowed(candidate.SighashType, allowedSighashTypes) {
+				return nil, fmt.Errorf("bitcoin: input %d: sighash type %#x is not allowed", i, candidate.SighashType)
+			}
+			candidate.Hash, err = txscript.CalcWitnessSigHash(witnessProgram, sigHashes, candidate.SighashType, p.UnsignedTx, i, utxo.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bitcoin: input %d: %w", i, err)
+			}
+			inputs = append(inputs, candidate)
+			break
+		}
+	}
+	return inputs, nil
+}
+
+// SignInput signs the sighash of input with cmp.Sign, or cmp.SignWithTweak if the input belongs to a derived key.
+//
+// Returns *ecdsa.Signature if successful, which is added to the PSBT with AddSignature.
+func SignInput(config *cmp.Config, signers []party.ID, input InputToSign, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	if input.Tweak == nil {
+		return cmp.Sign(config, signers, input.Hash, pl, opts...)
+	}
+	return cmp.SignWithTweak(config, signers, input.Tweak, input.Hash, pl, opts...)
+}
+
+// AddSignature adds sig as a partial signature of input to p, after checking it.
+//
+// The signature is DER encoded with a low s, as required by the standardness rules of Bitcoin.
+func AddSignature(p *Packet, input InputToSign, sig *ecdsa.Signature) error {
+	if input.Index < 0 || input.Index >= len(p.Inputs) {
+		return fmt.Errorf("bitcoin: input %d out of range", input.Index)
+	}
+	if !sig.Verify(input.PublicKey, input.Hash) {
+		return fmt.Errorf("bitcoin: input %d: invalid signature", input.Index)
+	}
+	der, err := sig.Normalize().SerializeDER()
+	if err != nil {
+		return fmt.Errorf("bitcoin: input %d: %w", input.Index, err)
+	}
+
+	pubKey, err := input.PublicKey.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	in := &p.Inputs[input.Index]
+	if hasPartialSig(in, pubKey) {
+		return fmt.Errorf("bitcoin: input %d is already signed", input.Index)
+	}
+	in.PartialSigs = append(in.PartialSigs, PartialSig{
+		PubKey:    pubKey,
+		Signature: append(der, byte(input.SighashType)),
+	})
+	return nil
+}
+
+// Finalize sets the final script signature and witness of every P2WPKH or P2SH-P2WPKH input of p
+// which has a partial signature, and removes the information which is no longer needed, as described in BIP-174.
+//
+// An error is returned if some inputs are still not finalized, in which case p can still be passed to other signers.
+func Finalize(p *Packet) error {
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if in.FinalScriptSig != nil || in.FinalScriptWitness != nil || len(in.PartialSigs) == 0 {
+			continue
+		}
+		utxo, err := p.utxo(i)
+		if err != nil {
+			return fmt.Errorf("bitcoin: input %d: %w", i, err)
+		}
+		for _, sig := range in.PartialSigs {
+			witnessProgram, err := spendsP2WPKH(in, utxo.PkScript, sig.PubKey)
+			if err != nil {
+				return fmt.Errorf("bitcoin: input %d: %w", i, err)
+			}
+			if witnessProgram == nil {
+				continue
+			}
+			if txscript.IsPayToScriptHash(utxo.PkScript) {
+				scriptSig, err := txscript.NewScriptBuilder().AddData(in.RedeemScript).Script()
+				if err != nil {
+					return fmt.Errorf("bitcoin: input %d: %w", i, err)
+				}
+				in.FinalScriptSig = scriptSig
+			}
+			in.FinalScriptWitness = writeWitness(wire.TxWitness{sig.Signature, sig.PubKey})
+			in.PartialSigs = nil
+			in.SighashType = 0
+			in.RedeemScript = nil
+			in.WitnessScript = nil
+			in.Bip32Derivation = nil
+			break
+		}
+	}
+	if !p.IsFinalized() {
+		return errors.New("bitcoin: not all inputs are signed")
+	}
+	return nil
+}
+
+// spendsP2WPKH returns the P2WPKH script of pubKey if pkScript pays to it, either directly or nested in P2SH,
+// or nil otherwise.
+func spendsP2WPKH(in *Input, pkScript, pubKey []byte) ([]byte, error) {
+	witnessProgram, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(btcutil.Hash160(pubKey)).Script()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(pkScript, witnessProgram) {
+		return witnessProgram, nil
+	}
+	if !txscript.IsPayToScriptHash(pkScript) || !bytes.Equal(in.RedeemScript, witnessProgram) {
+		return nil, nil
+	}
+	p2sh, err := txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).AddData(btcutil.Hash160(witnessProgram)).AddOp(txscript.OP_EQUAL).Script()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(pkScript, p2sh) {
+		return nil, errors.New("redeem script does not match the utxo")
+	}
+	return witnessProgram, nil
+}
+
+// sighashAllowed returns true if sighashType is SIGHASH_ALL, or is one of allowed.
+func sighashAllowed(sighashType txscript.SigHashType, allowed []txscript.SigHashType) bool {
+	if sighashType == txscript.SigHashAll {
+		return true
+	}
+	for _, t := range allowed {
+		if sighashType == t {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPartialSig(in *Input, pubKey []byte) bool {
+	for _, sig := range in.PartialSigs {
+		if bytes.Equal(sig.PubKey, pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatPath returns the BIP-32 path of indices, relative to the master key, such as "m/84/0/0".
+func formatPath(indices []uint32) string {
+	segments := make([]string, 0, len(indices)+1)
+	segments = append(segments, "m")
+	for _, i := range indices {
+		segments = append(segments, strconv.FormatUint(uint64(i), 10))
+	}
+	return strings.Join(segments, "/")
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/w3-key/mps-lean/pkg/pool"
 	"github.com/w3-key/mps-lean/pkg/protocol"
 	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	"github.com/w3-key/mps-lean/protocols/cmp/auxinfo"
 	"github.com/w3-key/mps-lean/protocols/cmp/config"
+	"github.com/w3-key/mps-lean/protocols/cmp/export"
 	"github.com/w3-key/mps-lean/protocols/cmp/keygen"
+	"github.com/w3-key/mps-lean/protocols/cmp/presign"
+	"github.com/w3-key/mps-lean/protocols/cmp/recovery"
 	"github.com/w3-key/mps-lean/protocols/cmp/sign"
 )
 
@@ -29,8 +34,11 @@ func EmptyConfig(group curve.Curve) *Config {
 // all participants posses a unique share of this key, as well as auxiliary parameters required during signing.
 //
 // For better performance, a `pool.Pool` can be provided in order to parallelize certain steps of the protocol.
+// All randomness is read from crypto/rand, unless a reader is set with round.WithRand. The same holds for the other
+// protocols in this package: a deterministic reader for each party makes an execution reproducible,
+// which is only meant for tests and audits.
 // Returns *cmp.Config if successful.
-func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool) protocol.StartFunc {
+func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
 	info := round.Info{
 		ProtocolID:       "cmp/keygen-threshold",
 		FinalRoundNumber: keygen.Rounds,
@@ -39,13 +47,14 @@ func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, thresho
 		Threshold:        threshold,
 		Group:            group,
 	}
+	info.Apply(opts...)
 	return keygen.Start(info, pl, nil)
 }
 
 // Refresh allows the parties to refresh all existing cryptographic keys from a previously generated Config.
 // The group's ECDSA public key remains the same, but any previous shares are rendered useless.
 // Returns *cmp.Config if successful.
-func Refresh(config *Config, pl *pool.Pool) protocol.StartFunc {
+func Refresh(config *Config, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
 	info := round.Info{
 		ProtocolID:       "cmp/refresh-threshold",
 		FinalRoundNumber: keygen.Rounds,
@@ -54,11 +63,211 @@ func Refresh(config *Config, pl *pool.Pool) protocol.StartFunc {
 		Threshold:        config.Threshold,
 		Group:            config.Group,
 	}
+	info.Apply(opts...)
 	return keygen.Start(info, pl, config)
 }
 
+// AuxInfo generates new Paillier, Pedersen and ElGamal parameters for all `participants`, and proves their validity.
+// These parameters are the expensive part of Keygen and Refresh, and do not depend on the ECDSA key.
+// They can be generated once and then reused by KeygenWithAuxInfo and RefreshShares.
+// Returns *config.AuxInfo if successful.
+func AuxInfo(group curve.Curve, selfID party.ID, participants []party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return auxinfo.Start(group, selfID, participants, pl, opts...)
+}
+
+// KeygenWithAuxInfo generates a new shared ECDSA key like Keygen, but reuses the parameters in `aux`
+// instead of generating new ones. All parties which generated `aux` must take part.
+// Returns *cmp.Config if successful.
+func KeygenWithAuxInfo(aux *config.AuxInfo, threshold int, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-threshold-aux",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           aux.ID,
+		PartyIDs:         aux.PartyIDs(),
+		Threshold:        threshold,
+		Group:            aux.Group,
+	}
+	info.Apply(opts...)
+	return keygen.StartWithAuxInfo(info, pl, nil, aux)
+}
+
+// RefreshShares refreshes the ECDSA shares of a previously generated Config, but keeps its Paillier, Pedersen
+// and ElGamal parameters. It is much faster than Refresh, which should still be used to replace these parameters.
+// Alternatively, new parameters can be generated with AuxInfo and set with Config.WithAuxInfo.
+// Returns *cmp.Config if successful.
+func RefreshShares(config *Config, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "cmp/refresh-threshold-aux",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           config.ID,
+		PartyIDs:         config.PartyIDs(),
+		Threshold:        config.Threshold,
+		Group:            config.Group,
+	}
+	info.Apply(opts...)
+	return keygen.StartWithAuxInfo(info, pl, config, config.AuxInfo())
+}
+
+// Reshare moves the key of config from the parties in `dealers` to the parties in `newParties`,
+// with a new threshold `newThreshold`. The group's ECDSA public key remains the same.
+//
+// `dealers` must contain at least config.Threshold+1 parties holding a share of the key, and every party in
+// dealers ∪ newParties must take part in the protocol. Parties in `newParties` which do not hold a share of the key
+// yet should use ReshareNew instead.
+// All parties in `newParties` generate fresh Paillier and Pedersen parameters, and any previous shares are rendered useless.
+// Returns *cmp.Config if successful, or the group's public key as curve.Point for a dealer which is not in `newParties`.
+func Reshare(config *Config, dealers, newParties []party.ID, newThreshold int, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return keygen.StartReshare(reshareInfo(config.Group, config.ID, dealers, newParties, newThreshold, opts),
+		pl, config, config.PublicPoint(), dealers, newParties)
+}
+
+// ReshareNew is run by a party in `newParties` which does not hold a share of the key being reshared with Reshare.
+// `publicKey` is the group's ECDSA public key, which must be obtained from a trusted source.
+// Returns *cmp.Config if successful.
+func ReshareNew(group curve.Curve, selfID party.ID, publicKey curve.Point, dealers, newParties []party.ID, newThreshold int, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return keygen.StartReshare(reshareInfo(group, selfID, dealers, newParties, newThreshold, opts),
+		pl, nil, publicKey, dealers, newParties)
+}
+
+func reshareInfo(group curve.Curve, selfID party.ID, dealers, newParties []party.ID, newThreshold int, opts []round.Option) round.Info {
+	participants := party.NewIDSlice(newParties)
+	for _, j := range dealers {
+		if !participants.Contains(j) {
+			participants = party.NewIDSlice(append(participants, j))
+		}
+	}
+	info := round.Info{
+		ProtocolID:       "cmp/reshare-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        newThreshold,
+		Group:            group,
+	}
+	info.Apply(opts...)
+	return info
+}
+
+// ImportKey is run by the owner of an existing ECDSA private key `secret`, in order to share it among `participants`
+// with the given `threshold`. The group's ECDSA public key remains the same, and the resulting configs can be used
+// like the ones obtained with Keygen.
+//
+// The parties in `participants` other than the owner run ImportKeyNew. All participants generate fresh Paillier and Pedersen
+// parameters. The owner should delete `secret` once the protocol succeeds.
+// Returns *cmp.Config if successful, or the group's public key as curve.Point if the owner is not in `participants`.
+func ImportKey(group curve.Curve, selfID party.ID, secret curve.Scalar, participants []party.ID, threshold int, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	var publicKey curve.Point
+	if secret != nil {
+		publicKey = secret.ActOnBase()
+	}
+	return keygen.StartImport(importInfo(group, selfID, selfID, participants, threshold, opts),
+		pl, secret, publicKey, selfID, participants)
+}
+
+// ImportKeyNew is run by a party in `participants` other than `owner`, to obtain a share of the key imported with ImportKey.
+// `publicKey` is the public key being imported, which must be obtained from a trusted source.
+// Returns *cmp.Config if successful.
+func ImportKeyNew(group curve.Curve, selfID, owner party.ID, publicKey curve.Point, participants []party.ID, threshold int, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return keygen.StartImport(importInfo(group, selfID, owner, participants, threshold, opts),
+		pl, nil, publicKey, owner, participants)
+}
+
+func importInfo(group curve.Curve, selfID, owner party.ID, participants []party.ID, threshold int, opts []round.Option) round.Info {
+	partyIDs := party.NewIDSlice(participants)
+	if !partyIDs.Contains(owner) {
+		partyIDs = party.NewIDSlice(append(partyIDs, owner))
+	}
+	info := round.Info{
+		ProtocolID:       "cmp/import-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         partyIDs,
+		Threshold:        threshold,
+		Group:            group,
+	}
+	info.Apply(opts...)
+	return info
+}
+
+// Recover re-creates the share of the party `lost`, which has lost its Config, at its original evaluation point.
+// It is run by the parties in `helpers`, together with the party `lost` running RecoverNew.
+//
+// `helpers` must contain at least config.Threshold+1 parties holding a share of the key,
+// none of which learn the recovered share. The group's ECDSA public key remains the same.
+// The recovered party obtains fresh Paillier, Pedersen and ElGamal keys, which parties not taking part
+// in the protocol do not learn. All remaining parties should therefore take part as helpers when possible.
+// Returns *cmp.Config if successful, where the public parameters of `lost` are updated.
+func Recover(config *Config, helpers []party.ID, lost party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return recovery.StartRecover(config, helpers, lost, pl, opts...)
+}
+
+// RecoverNew is run by the party `selfID` whose share is re-created by `helpers` with Recover.
+// `publicKey` is the group's ECDSA public key, which must be obtained from a trusted source.
+// Returns *cmp.Config if successful.
+func RecoverNew(group curve.Curve, selfID party.ID, publicKey curve.Point, helpers []party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return recovery.StartRecoverNew(group, selfID, publicKey, helpers, pl, opts...)
+}
+
+// ExportKey reconstructs the group's ECDSA private key, encrypted to the recipient described in `req`.
+// It is meant for disaster recovery, after which the threshold sharing should no longer be considered secure.
+//
+// `participants` must contain at least config.Threshold+1 parties, each of which explicitly approves `req`
+// by running the protocol. No participant learns the private key.
+// Returns *export.Export if successful, containing an auditable export.Record of the approvals,
+// from which the recipient obtains the private key with Export.Decrypt.
+func ExportKey(config *Config, participants []party.ID, req *export.Request, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return export.Start(config, participants, req, pl, opts...)
+}
+
 // Sign generates an ECDSA signature for `messageHash` among the given `signers`.
 // Returns *ecdsa.Signature if successful.
-func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool, forkeys bool) protocol.StartFunc {
-	return sign.StartSign(config, signers, messageHash, pl, forkeys)
+func Sign(config *Config, signers []party.ID, messageHash []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return sign.StartSign(config, signers, messageHash, pl, opts...)
+}
+
+// SignDerived generates an ECDSA signature for `messageHash` with the BIP-32 child key at `path`,
+// relative to config, such as "m/0/7", without deriving a new Config.
+// The signature verifies under the PublicPoint of config.DerivePath(path).
+// Returns *ecdsa.Signature if successful.
+func SignDerived(config *Config, signers []party.ID, path string, messageHash []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	tweak, _, err := config.DeriveTweak(path)
+	if err != nil {
+		return func([]byte) (round.Session, error) { return nil, err }
+	}
+	return sign.StartSignDerived(config, signers, messageHash, tweak, pl, opts...)
+}
+
+// SignWithTweak generates an ECDSA signature for `messageHash` with the key x + tweak,
+// where x is the key shared by config.
+// All signers must use the same tweak, which is bound to the session.
+// Returns *ecdsa.Signature if successful.
+func SignWithTweak(config *Config, signers []party.ID, tweak curve.Scalar, messageHash []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return sign.StartSignDerived(config, signers, messageHash, tweak, pl, opts...)
+}
+
+// SignBatch generates an ECDSA signature for each of the `messageHashes` among the given `signers`, in a single session.
+// All messages are signed in parallel, and the messages of each round are sent together.
+// This saves round trips only, the computation and proofs are the same as signing each message separately.
+// Returns []*ecdsa.Signature if successful, in the same order as `messageHashes`.
+// If only some messages could be signed, the protocol aborts with a *sign.BatchError
+// containing the valid signatures and the reason each other message failed.
+func SignBatch(config *Config, signers []party.ID, messageHashes [][]byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return sign.StartSignBatch(config, signers, messageHashes, pl, opts...)
+}
+
+// Presign generates a preprocessed signature that does not depend on the message being signed.
+// When the message becomes available, the same participants can efficiently combine their shares
+// to produce a full signature with the PresignOnline protocol.
+// Note: the PreSignatures should be treated as secret key material.
+// Returns *ecdsa.PreSignature if successful.
+func Presign(config *Config, signers []party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return presign.StartPresign(config, signers, nil, pl, opts...)
+}
+
+// PresignOnline efficiently generates an ECDSA signature for `messageHash` with the PreSignature
+// stored under `id` in `store`, as returned by Presign.
+// The PreSignature is taken from the store before the session starts, so it can never sign a second message.
+// Returns *ecdsa.Signature if successful.
+func PresignOnline(config *Config, store sign.PreSignatureStore, id types.RID, messageHash []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return presign.StartPresignOnline(config, store, id, messageHash, pl, opts...)
 }
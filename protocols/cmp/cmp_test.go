@@ -1,19 +1,24 @@
 package cmp
 
 import (
+	stdecdsa "crypto/ecdsa"
 	"crypto/rand"
+	"crypto/sha256"
 	"math"
+	"math/big"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/bip32"
 	"github.com/w3-key/mps-lean/pkg/ecdsa"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/party"
 	"github.com/w3-key/mps-lean/pkg/pool"
 	"github.com/w3-key/mps-lean/pkg/protocol"
 	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/cmp/sign"
 )
 
 func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, pl *pool.Pool, n *test.Network, wg *sync.WaitGroup) {
@@ -35,6 +40,15 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 	require.IsType(t, &Config{}, r)
 	c = r.(*Config)
 
+	h, err = protocol.NewMultiHandler(RefreshShares(c, pl), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	r, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c = r.(*Config)
+
 	h, err = protocol.NewMultiHandler(Sign(c, ids, message, pl), nil)
 	require.NoError(t, err)
 	test.HandlerLoop(c.ID, h, n)
@@ -45,6 +59,18 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 	signature := signResult.(*ecdsa.Signature)
 	assert.True(t, signature.Verify(c.PublicPoint(), message))
 
+	messages := [][]byte{message, []byte("world")}
+	h, err = protocol.NewMultiHandler(SignBatch(c, ids, messages, pl), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, []*ecdsa.Signature{}, signResult)
+	for i, signature := range signResult.([]*ecdsa.Signature) {
+		assert.True(t, signature.Verify(c.PublicPoint(), messages[i]))
+	}
+
 	h, err = protocol.NewMultiHandler(Presign(c, ids, pl), nil)
 	require.NoError(t, err)
 
@@ -56,7 +82,9 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 	preSignature := signResult.(*ecdsa.PreSignature)
 	assert.NoError(t, preSignature.Validate())
 
-	h, err = protocol.NewMultiHandler(PresignOnline(c, preSignature, message, pl), nil)
+	store := sign.NewMemoryStore()
+	require.NoError(t, store.Put(preSignature))
+	h, err = protocol.NewMultiHandler(PresignOnline(c, store, preSignature.ID, message, pl), nil)
 	require.NoError(t, err)
 	test.HandlerLoop(c.ID, h, n)
 
@@ -65,6 +93,9 @@ func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte
 	require.IsType(t, &ecdsa.Signature{}, signResult)
 	signature = signResult.(*ecdsa.Signature)
 	assert.True(t, signature.Verify(c.PublicPoint(), message))
+
+	_, err = protocol.NewMultiHandler(PresignOnline(c, store, preSignature.ID, []byte("world"), pl), nil)
+	assert.ErrorIs(t, err, sign.ErrPreSignatureUsed, "a presignature should only sign one message")
 }
 
 func TestCMP(t *testing.T) {
@@ -86,6 +117,52 @@ func TestCMP(t *testing.T) {
 	wg.Wait()
 }
 
+func doP256(t *testing.T, id party.ID, ids []party.ID, threshold int, messageHash []byte, pl *pool.Pool, n *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+	h, err := protocol.NewMultiHandler(Keygen(curve.P256{}, id, ids, threshold, pl), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c := r.(*Config)
+
+	h, err = protocol.NewMultiHandler(Sign(c, ids, messageHash, pl), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+	signResult, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &ecdsa.Signature{}, signResult)
+	signature := signResult.(*ecdsa.Signature)
+	assert.True(t, signature.Verify(c.PublicPoint(), messageHash))
+
+	rBytes, err := signature.R.XScalar().MarshalBinary()
+	require.NoError(t, err)
+	sBytes, err := signature.S.MarshalBinary()
+	require.NoError(t, err)
+	publicKey := c.PublicPoint().ToECDSA()
+	assert.True(t, stdecdsa.Verify(publicKey, messageHash, new(big.Int).SetBytes(rBytes), new(big.Int).SetBytes(sBytes)))
+}
+
+func TestCMPP256(t *testing.T) {
+	N := 3
+	T := 1
+	messageHash := sha256.Sum256([]byte("hello"))
+
+	partyIDs := test.PartyIDs(N)
+
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for _, id := range partyIDs {
+		pl := pool.NewPool(1)
+		defer pl.TearDown()
+		go doP256(t, id, partyIDs, T, messageHash[:], pl, n, &wg)
+	}
+	wg.Wait()
+}
+
 func TestStart(t *testing.T) {
 	group := curve.Secp256k1{}
 	N := 6
@@ -161,3 +238,42 @@ func TestStart(t *testing.T) {
 		})
 	}
 }
+
+func TestDerivePath(t *testing.T) {
+	group := curve.Secp256k1{}
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, rand.Reader, pl)
+	c := configs[partyIDs[0]]
+
+	root, err := c.ExtendedPublicKey(bip32.MainnetPublic)
+	require.NoError(t, err)
+	xpub, err := bip32.ParseExtendedPublicKey(root.String())
+	require.NoError(t, err)
+	expected, err := xpub.DerivePath("m/44/60/0/0/7")
+	require.NoError(t, err)
+
+	for _, id := range partyIDs {
+		derived, err := configs[id].DerivePath("m/44/60/0/0/7")
+		require.NoError(t, err)
+		assert.True(t, expected.PublicKey.Equal(derived.PublicPoint()), "derived key should match the xpub derivation")
+		assert.True(t, derived.ECDSA.ActOnBase().Equal(derived.Public[id].ECDSA))
+
+		child, err := derived.ExtendedPublicKey(bip32.MainnetPublic)
+		require.NoError(t, err)
+		assert.Equal(t, expected.String(), child.String())
+		assert.EqualValues(t, 5, derived.Depth)
+		assert.EqualValues(t, 7, derived.ChildNumber)
+
+		data, err := derived.MarshalBinary()
+		require.NoError(t, err)
+		decoded := EmptyConfig(group)
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, derived.Depth, decoded.Depth)
+		assert.Equal(t, derived.ParentFingerprint, decoded.ParentFingerprint)
+		assert.Equal(t, derived.ChildNumber, decoded.ChildNumber)
+	}
+
+	_, err = c.DerivePath("m/44'/60/0/0/7")
+	assert.Error(t, err, "hardened derivation should be rejected")
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cronokirby/saferith"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pedersen"
+)
+
+// AuxInfo contains the auxiliary parameters of a party, which are independent of the ECDSA key.
+// It is the output of the aux info protocol, and can be used by several keygen or refresh
+// executions which only generate new ECDSA shares.
+//
+// To unmarshal this struct, EmptyAuxInfo should be called first with a specific group,
+// before using cbor.Unmarshal with that struct.
+type AuxInfo struct {
+	// Group returns the Elliptic Curve Group associated with this aux info.
+	Group curve.Curve
+	// ID is the identifier of the party this AuxInfo belongs to.
+	ID party.ID
+	// ElGamal is this party's yᵢ used for ElGamal.
+	ElGamal curve.Scalar
+	// Paillier is this party's Paillier decryption key.
+	Paillier *paillier.SecretKey
+	// Public maps party.ID to the public auxiliary parameters of a party.
+	// The ECDSA field of each entry is always nil.
+	Public map[party.ID]*Public
+}
+
+// EmptyAuxInfo creates an empty AuxInfo with a fixed group, ready for unmarshalling.
+func EmptyAuxInfo(group curve.Curve) *AuxInfo {
+	return &AuxInfo{
+		Group: group,
+	}
+}
+
+// AuxInfo returns the auxiliary parameters contained in c.
+func (c *Config) AuxInfo() *AuxInfo {
+	public := make(map[party.ID]*Public, len(c.Public))
+	for j, p := range c.Public {
+		public[j] = &Public{
+			ElGamal:  p.ElGamal,
+			Paillier: p.Paillier,
+			Pedersen: p.Pedersen,
+		}
+	}
+	return &AuxInfo{
+		Group:    c.Group,
+		ID:       c.ID,
+		ElGamal:  c.ElGamal,
+		Paillier: c.Paillier,
+		Public:   public,
+	}
+}
+
+// WithAuxInfo returns a copy of c where the auxiliary parameters are replaced by those in aux.
+// aux must have been generated by the same parties as c.
+func (c *Config) WithAuxInfo(aux *AuxInfo) (*Config, error) {
+	if aux.Group.Name() != c.Group.Name() {
+		return nil, errors.New("config: aux info has a different group")
+	}
+	if aux.ID != c.ID {
+		return nil, errors.New("config: aux info belongs to a different party")
+	}
+	if len(aux.Public) != len(c.Public) {
+		return nil, errors.New("config: aux info has a different set of parties")
+	}
+	public := make(map[party.ID]*Public, len(c.Public))
+	for j, p := range c.Public {
+		a, ok := aux.Public[j]
+		if !ok {
+			return nil, errors.New("config: aux info has a different set of parties")
+		}
+		public[j] = &Public{
+			ECDSA:    p.ECDSA,
+			ElGamal:  a.ElGamal,
+			Paillier: a.Paillier,
+			Pedersen: a.Pedersen,
+		}
+	}
+	return &Config{
+		Group:             c.Group,
+		ID:                c.ID,
+		Threshold:         c.Threshold,
+		ECDSA:             c.ECDSA,
+		ElGamal:           aux.ElGamal,
+		Paillier:          aux.Paillier,
+		RID:               c.RID,
+		ChainKey:          c.ChainKey,
+		Depth:             c.Depth,
+		ParentFingerprint: c.ParentFingerprint,
+		ChildNumber:       c.ChildNumber,
+		Public:            public,
+	}, nil
+}
+
+// PartyIDs returns a sorted slice of party IDs.
+func (a *AuxInfo) PartyIDs() party.IDSlice {
+	ids := make([]party.ID, 0, len(a.Public))
+	for j := range a.Public {
+		ids = append(ids, j)
+	}
+	return party.NewIDSlice(ids)
+}
+
+// WriteTo implements io.WriterTo interface.
+func (a *AuxInfo) WriteTo(w io.Writer) (total int64, err error) {
+	if a == nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	var n int64
+
+	// write partyIDs
+	partyIDs := a.PartyIDs()
+	n, err = partyIDs.WriteTo(w)
+	total += n
+	if err != nil {
+		return
+	}
+
+	// write all party data
+	for _, j := range partyIDs {
+		p := a.Public[j]
+		if p == nil {
+			return total, io.ErrUnexpectedEOF
+		}
+		// write Yⱼ
+		data, err := p.ElGamal.MarshalBinary()
+		if err != nil {
+			return total, err
+		}
+		m, err := w.Write(data)
+		total += int64(m)
+		if err != nil {
+			return total, err
+		}
+
+		n, err = p.Paillier.WriteTo(w)
+		total += n
+		if err != nil {
+			return total, err
+		}
+
+		n, err = p.Pedersen.WriteTo(w)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return
+}
+
+// Domain implements hash.WriterToWithDomain.
+func (a *AuxInfo) Domain() string {
+	return "CMP AuxInfo"
+}
+
+type auxInfoMarshal struct {
+	ID      party.ID
+	ElGamal curve.Scalar
+	P, Q    *saferith.Nat
+	Public  []cbor.RawMessage
+}
+
+type auxPublicMarshal struct {
+	ID      party.ID
+	ElGamal curve.Point
+	N       *saferith.Modulus
+	S, T    *saferith.Nat
+}
+
+func (a *AuxInfo) MarshalBinary() ([]byte, error) {
+	ps := make([]cbor.RawMessage, 0, len(a.Public))
+	for _, id := range a.PartyIDs() {
+		p := a.Public[id]
+		data, err := cbor.Marshal(&auxPublicMarshal{
+			ID:      id,
+			ElGamal: p.ElGamal,
+			N:       p.Pedersen.N(),
+			S:       p.Pedersen.S(),
+			T:       p.Pedersen.T(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, data)
+	}
+	return cbor.Marshal(&auxInfoMarshal{
+		ID:      a.ID,
+		ElGamal: a.ElGamal,
+		P:       a.Paillier.P(),
+		Q:       a.Paillier.Q(),
+		Public:  ps,
+	})
+}
+
+func (a *AuxInfo) UnmarshalBinary(data []byte) error {
+	if a.Group == nil {
+		return errors.New("aux info must be initialized using EmptyAuxInfo")
+	}
+	am := &auxInfoMarshal{
+		ElGamal: a.Group.NewScalar(),
+	}
+	if err := cbor.Unmarshal(data, &am); err != nil {
+		return fmt.Errorf("aux info: %w", err)
+	}
+
+	if am.ElGamal.IsZero() {
+		return errors.New("aux info: ElGamal secret key is zero")
+	}
+
+	if err := paillier.ValidatePrime(am.P); err != nil {
+		return fmt.Errorf("aux info: prime P: %w", err)
+	}
+	if err := paillier.ValidatePrime(am.Q); err != nil {
+		return fmt.Errorf("aux info: prime Q: %w", err)
+	}
+	paillierSecret := paillier.NewSecretKeyFromPrimes(am.P, am.Q)
+
+	ps := make(map[party.ID]*Public, len(am.Public))
+	for _, pm := range am.Public {
+		p := &auxPublicMarshal{
+			ElGamal: a.Group.NewPoint(),
+		}
+		if err := cbor.Unmarshal(pm, p); err != nil {
+			return fmt.Errorf("aux info: party %s: %w", p.ID, err)
+		}
+		if _, ok := ps[p.ID]; ok {
+			return fmt.Errorf("aux info: party %s: duplicate entry", p.ID)
+		}
+
+		// handle our own key separately
+		if p.ID == am.ID {
+			ps[p.ID] = &Public{
+				ElGamal:  am.ElGamal.ActOnBase(),
+				Paillier: paillierSecret.PublicKey,
+				Pedersen: pedersen.New(paillierSecret.Modulus(), p.S, p.T),
+			}
+			continue
+		}
+
+		if err := paillier.ValidateN(p.N); err != nil {
+			return fmt.Errorf("aux info: party %s: %w", p.ID, err)
+		}
+		if err := pedersen.ValidateParameters(p.N, p.S, p.T); err != nil {
+			return fmt.Errorf("aux info: party %s: %w", p.ID, err)
+		}
+		if p.ElGamal.IsIdentity() {
+			return fmt.Errorf("aux info: party %s: ElGamal public key is identity", p.ID)
+		}
+
+		paillierPublic := paillier.NewPublicKey(p.N)
+		ps[p.ID] = &Public{
+			ElGamal:  p.ElGamal,
+			Paillier: paillierPublic,
+			Pedersen: pedersen.New(paillierPublic.Modulus(), p.S, p.T),
+		}
+	}
+
+	// check that we are included
+	if _, ok := ps[am.ID]; !ok {
+		return errors.New("aux info: no public data for this party")
+	}
+
+	*a = AuxInfo{
+		Group:    a.Group,
+		ID:       am.ID,
+		ElGamal:  am.ElGamal,
+		Paillier: paillierSecret,
+		Public:   ps,
+	}
+	return nil
+}
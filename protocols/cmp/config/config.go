@@ -40,6 +40,11 @@ type Config struct {
 	RID types.RID
 	// ChainKey is the chaining key value associated with this public key
 	ChainKey types.RID
+	// Depth, ParentFingerprint and ChildNumber locate the public key in a BIP-32 tree.
+	// They are zero for a freshly generated key, and are set by DeriveBIP32 and DerivePath.
+	Depth             uint8
+	ParentFingerprint uint32
+	ChildNumber       uint32
 	// Public maps party.ID to public. It contains all public information associated to a party.
 	Public map[party.ID]*Public
 }
@@ -254,21 +259,93 @@ func (c *Config) Derive(adjust curve.Scalar, newChainKey []byte) (*Config, error
 // DeriveBIP32 derives a sharing of the ith child of the consortium signing key.
 //
 // This function uses unhardened derivation, deriving a key without including the
-// underlying private key. This function will panic if i ⩾ 2³¹, since that indicates
+// underlying private key. An error is returned if i ⩾ 2³¹, since that indicates
 // a hardened key.
 //
 // Sometimes, an error will be returned, indicating that this index generates
 // an invalid key.
 //
+// The depth, parent fingerprint and child number of the result are updated,
+// so that ExtendedPublicKey describes the child.
+//
 // See: https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki
 func (c *Config) DeriveBIP32(i uint32) (*Config, error) {
-	publicPoint, ok := c.PublicPoint().(*curve.Secp256k1Point)
-	if !ok {
-		return nil, errors.New("DeriveBIP32 must be called with secp256k1")
+	parent, err := c.ExtendedPublicKey(bip32.MainnetPublic)
+	if err != nil {
+		return nil, err
+	}
+	child, scalar, err := parent.Derive(i)
+	if err != nil {
+		return nil, err
+	}
+	derived, err := c.Derive(scalar, child.ChainCode)
+	if err != nil {
+		return nil, err
+	}
+	derived.Depth = child.Depth
+	derived.ParentFingerprint = child.ParentFingerprint
+	derived.ChildNumber = child.ChildNumber
+	return derived, nil
+}
+
+// DerivePath derives a sharing of the key at path, relative to c, such as "m/44/60/0/0/7".
+//
+// Hardened segments are rejected, since they would require the underlying private key.
+func (c *Config) DerivePath(path string) (*Config, error) {
+	tweak, child, err := c.DeriveTweak(path)
+	if err != nil {
+		return nil, err
 	}
-	scalar, newChainKey, err := bip32.DeriveScalar(publicPoint, c.ChainKey, i)
+	derived, err := c.Derive(tweak, child.ChainCode)
 	if err != nil {
 		return nil, err
 	}
-	return c.Derive(scalar, newChainKey)
+	derived.Depth = child.Depth
+	derived.ParentFingerprint = child.ParentFingerprint
+	derived.ChildNumber = child.ChildNumber
+	return derived, nil
+}
+
+// DeriveTweak returns the scalar t such that the key at path, relative to c, is x + t,
+// where x is the key shared by c, along with the extended public key at path.
+//
+// This avoids deriving a new Config, for example when signing with cmp.SignDerived.
+func (c *Config) DeriveTweak(path string) (curve.Scalar, *bip32.ExtendedPublicKey, error) {
+	indices, err := bip32.ParsePath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	child, err := c.ExtendedPublicKey(bip32.MainnetPublic)
+	if err != nil {
+		return nil, nil, err
+	}
+	tweak := c.Group.NewScalar()
+	for _, i := range indices {
+		var scalar curve.Scalar
+		if child, scalar, err = child.Derive(i); err != nil {
+			return nil, nil, fmt.Errorf("DeriveTweak: %w", err)
+		}
+		tweak.Add(scalar)
+	}
+	return tweak, child, nil
+}
+
+// ExtendedPublicKey returns the BIP-32 extended public key of c, with the given version,
+// such as bip32.MainnetPublic or bip32.TestnetPublic.
+//
+// Its String method gives the xpub, which can be used to derive the same child
+// public keys as DerivePath, without any key share.
+func (c *Config) ExtendedPublicKey(version uint32) (*bip32.ExtendedPublicKey, error) {
+	publicPoint, ok := c.PublicPoint().(*curve.Secp256k1Point)
+	if !ok {
+		return nil, errors.New("BIP-32 derivation must be used with secp256k1")
+	}
+	return &bip32.ExtendedPublicKey{
+		Version:           version,
+		Depth:             c.Depth,
+		ParentFingerprint: c.ParentFingerprint,
+		ChildNumber:       c.ChildNumber,
+		ChainCode:         c.ChainKey.Copy(),
+		PublicKey:         publicPoint,
+	}, nil
 }
@@ -24,12 +24,14 @@ func EmptyConfig(group curve.Curve) *Config {
 }
 
 type configMarshal struct {
-	ID             party.ID
-	Threshold      int
-	ECDSA, ElGamal curve.Scalar
-	P, Q           *saferith.Nat
-	RID, ChainKey  types.RID
-	Public         []cbor.RawMessage
+	ID                       party.ID
+	Threshold                int
+	ECDSA, ElGamal           curve.Scalar
+	P, Q                     *saferith.Nat
+	RID, ChainKey            types.RID
+	Depth                    uint8
+	ParentFingerprint, Child uint32
+	Public                   []cbor.RawMessage
 }
 
 type publicMarshal struct {
@@ -58,15 +60,18 @@ func (c *Config) MarshalBinary() ([]byte, error) {
 		ps = append(ps, data)
 	}
 	return cbor.Marshal(&configMarshal{
-		ID:        c.ID,
-		Threshold: c.Threshold,
-		ECDSA:     c.ECDSA,
-		ElGamal:   c.ElGamal,
-		P:         c.Paillier.P(),
-		Q:         c.Paillier.Q(),
-		RID:       c.RID,
-		ChainKey:  c.ChainKey,
-		Public:    ps,
+		ID:                c.ID,
+		Threshold:         c.Threshold,
+		ECDSA:             c.ECDSA,
+		ElGamal:           c.ElGamal,
+		P:                 c.Paillier.P(),
+		Q:                 c.Paillier.Q(),
+		RID:               c.RID,
+		ChainKey:          c.ChainKey,
+		Depth:             c.Depth,
+		ParentFingerprint: c.ParentFingerprint,
+		Child:             c.ChildNumber,
+		Public:            ps,
 	})
 }
 
@@ -152,15 +157,119 @@ func (c *Config) UnmarshalBinary(data []byte) error {
 	}
 
 	*c = Config{
-		Group:     c.Group,
-		ID:        cm.ID,
-		Threshold: cm.Threshold,
-		ECDSA:     cm.ECDSA,
-		ElGamal:   cm.ElGamal,
-		Paillier:  paillierSecret,
-		RID:       cm.RID,
-		ChainKey:  cm.ChainKey,
-		Public:    ps,
+		Group:             c.Group,
+		ID:                cm.ID,
+		Threshold:         cm.Threshold,
+		ECDSA:             cm.ECDSA,
+		ElGamal:           cm.ElGamal,
+		Paillier:          paillierSecret,
+		RID:               cm.RID,
+		ChainKey:          cm.ChainKey,
+		Depth:             cm.Depth,
+		ParentFingerprint: cm.ParentFingerprint,
+		ChildNumber:       cm.Child,
+		Public:            ps,
 	}
 	return nil
 }
+
+type publicConfigMarshal struct {
+	Threshold                int
+	RID, ChainKey            types.RID
+	Depth                    uint8
+	ParentFingerprint, Child uint32
+	Public                   []cbor.RawMessage
+}
+
+// MarshalPublic encodes the public data of c, that is everything except the secrets of c.ID.
+func (c *Config) MarshalPublic() ([]byte, error) {
+	ps := make([]cbor.RawMessage, 0, len(c.Public))
+	for _, id := range c.PartyIDs() {
+		p := c.Public[id]
+		data, err := cbor.Marshal(&publicMarshal{
+			ID:      id,
+			ECDSA:   p.ECDSA,
+			ElGamal: p.ElGamal,
+			N:       p.Pedersen.N(),
+			S:       p.Pedersen.S(),
+			T:       p.Pedersen.T(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, data)
+	}
+	return cbor.Marshal(&publicConfigMarshal{
+		Threshold:         c.Threshold,
+		RID:               c.RID,
+		ChainKey:          c.ChainKey,
+		Depth:             c.Depth,
+		ParentFingerprint: c.ParentFingerprint,
+		Child:             c.ChildNumber,
+		Public:            ps,
+	})
+}
+
+// UnmarshalPublic decodes the output of MarshalPublic into a Config over group.
+// The returned Config contains no secrets, and its ID is empty.
+func UnmarshalPublic(group curve.Curve, data []byte) (*Config, error) {
+	var cm publicConfigMarshal
+	if err := cbor.Unmarshal(data, &cm); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := cm.RID.Validate(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := cm.ChainKey.Validate(); err != nil {
+		return nil, fmt.Errorf("config: chain key: %w", err)
+	}
+
+	ps := make(map[party.ID]*Public, len(cm.Public))
+	for _, pm := range cm.Public {
+		p := &publicMarshal{
+			ECDSA:   group.NewPoint(),
+			ElGamal: group.NewPoint(),
+		}
+		if err := cbor.Unmarshal(pm, p); err != nil {
+			return nil, fmt.Errorf("config: party %s: %w", p.ID, err)
+		}
+		if _, ok := ps[p.ID]; ok {
+			return nil, fmt.Errorf("config: party %s: duplicate entry", p.ID)
+		}
+		if p.N == nil || p.S == nil || p.T == nil {
+			return nil, fmt.Errorf("config: party %s: missing Pedersen parameters", p.ID)
+		}
+		if err := paillier.ValidateN(p.N); err != nil {
+			return nil, fmt.Errorf("config: party %s: %w", p.ID, err)
+		}
+		if err := pedersen.ValidateParameters(p.N, p.S, p.T); err != nil {
+			return nil, fmt.Errorf("config: party %s: %w", p.ID, err)
+		}
+		if p.ECDSA.IsIdentity() || p.ElGamal.IsIdentity() {
+			return nil, fmt.Errorf("config: party %s: ECDSA or ElGamal public key is identity", p.ID)
+		}
+
+		paillierPublic := paillier.NewPublicKey(p.N)
+		ps[p.ID] = &Public{
+			ECDSA:    p.ECDSA,
+			ElGamal:  p.ElGamal,
+			Paillier: paillierPublic,
+			Pedersen: pedersen.New(paillierPublic.Modulus(), p.S, p.T),
+		}
+	}
+
+	if !ValidThreshold(cm.Threshold, len(ps)) {
+		return nil, fmt.Errorf("config: threshold %d is invalid", cm.Threshold)
+	}
+
+	return &Config{
+		Group:             group,
+		Threshold:         cm.Threshold,
+		RID:               cm.RID,
+		ChainKey:          cm.ChainKey,
+		Depth:             cm.Depth,
+		ParentFingerprint: cm.ParentFingerprint,
+		ChildNumber:       cm.Child,
+		Public:            ps,
+	}, nil
+}
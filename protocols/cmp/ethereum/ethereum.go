@@ -0,0 +1,131 @@
+// Package ethereum signs Ethereum messages and typed data with a threshold ECDSA key generated by cmp.Keygen.
+package ethereum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/cmp"
+)
+
+// TextHash returns the EIP-191 hash of data, as signed by personal_sign:
+//
+//	keccak256("\x19Ethereum Signed Message:\n" ‖ len(data) ‖ data).
+func TextHash(data []byte) []byte {
+	return accounts.TextHash(data)
+}
+
+// TypedDataHash returns the EIP-712 hash of the typed data document typedDataJSON, as signed by eth_signTypedData_v4:
+//
+//	keccak256("\x19\x01" ‖ hashStruct(domain) ‖ hashStruct(message)).
+//
+// The domain's chainId may be given as a JSON number, as done by most wallets, or as a decimal or hex string.
+func TypedDataHash(typedDataJSON []byte) ([]byte, error) {
+	typedDataJSON, err := quoteChainID(typedDataJSON)
+	if err != nil {
+		return nil, fmt.Errorf("ethereum: invalid typed data: %w", err)
+	}
+	var typedData apitypes.TypedData
+	if err = json.Unmarshal(typedDataJSON, &typedData); err != nil {
+		return nil, fmt.Errorf("ethereum: invalid typed data: %w", err)
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("ethereum: invalid typed data: %w", err)
+	}
+	return hash, nil
+}
+
+// quoteChainID replaces a numeric chainId in the domain of typedDataJSON by a string,
+// since apitypes.TypedData only accepts the latter.
+func quoteChainID(typedDataJSON []byte) ([]byte, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(typedDataJSON, &document); err != nil {
+		return nil, err
+	}
+	var domain map[string]json.RawMessage
+	if len(document["domain"]) == 0 || json.Unmarshal(document["domain"], &domain) != nil {
+		return typedDataJSON, nil
+	}
+	chainID := bytes.TrimSpace(domain["chainId"])
+	if len(chainID) == 0 || chainID[0] < '0' || chainID[0] > '9' {
+		return typedDataJSON, nil
+	}
+	domain["chainId"] = append(append([]byte{'"'}, chainID...), '"')
+	var err error
+	if document["domain"], err = json.Marshal(domain); err != nil {
+		return nil, err
+	}
+	return json.Marshal(document)
+}
+
+// SignText signs the EIP-191 hash of data with cmp.Sign.
+//
+// Returns *ecdsa.Signature if successful, which can be encoded with EncodeSignature and TextHash(data).
+func SignText(config *cmp.Config, signers []party.ID, data []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return cmp.Sign(config, signers, TextHash(data), pl, opts...)
+}
+
+// SignTypedData signs the EIP-712 hash of the typed data document typedDataJSON with cmp.Sign.
+//
+// Returns *ecdsa.Signature if successful, which can be encoded with EncodeSignature and TypedDataHash(typedDataJSON).
+func SignTypedData(config *cmp.Config, signers []party.ID, typedDataJSON []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	hash, err := TypedDataHash(typedDataJSON)
+	if err != nil {
+		return func([]byte) (round.Session, error) { return nil, err }
+	}
+	return cmp.Sign(config, signers, hash, pl, opts...)
+}
+
+// EncodeSignature returns the 65 byte signature r ‖ s ‖ v of hash expected by Ethereum wallets and ecrecover,
+// where s is normalized to the lower half of the group order, and v is 27 or 28.
+//
+// The signature is checked to recover to the address of publicKey, so that an invalid v can never be returned.
+func EncodeSignature(sig *ecdsa.Signature, publicKey curve.Point, hash []byte) ([]byte, error) {
+	rs, err := recoverable(sig, publicKey, hash)
+	if err != nil {
+		return nil, err
+	}
+	rs[64] += 27
+	return rs, nil
+}
+
+// recoverable returns the signature r ‖ s ‖ v of hash with v ∈ {0, 1}, and checks that it recovers to publicKey.
+func recoverable(sig *ecdsa.Signature, publicKey curve.Point, hash []byte) ([]byte, error) {
+	if _, ok := publicKey.(*curve.Secp256k1Point); !ok {
+		return nil, errors.New("ethereum: public key is not on secp256k1")
+	}
+	if _, ok := sig.R.(*curve.Secp256k1Point); !ok {
+		return nil, errors.New("ethereum: signature is not on secp256k1")
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("ethereum: hash must be 32 bytes, got %d", len(hash))
+	}
+	// s and -s are both valid, but Ethereum only accepts s ≤ n/2
+	rs, err := sig.Normalize().SerializeRecoverable()
+	if err != nil {
+		return nil, fmt.Errorf("ethereum: %w", err)
+	}
+	// v can not encode an x coordinate of R which overflows the group order
+	if rs[64] > 1 {
+		return nil, errors.New("ethereum: signature is not representable")
+	}
+	recovered, err := ecdsa.RecoverPublicKey(hash, rs)
+	if err != nil {
+		return nil, fmt.Errorf("ethereum: %w", err)
+	}
+	if !recovered.Equal(publicKey) {
+		return nil, errors.New("ethereum: signature does not recover to the public key")
+	}
+	return rs, nil
+}
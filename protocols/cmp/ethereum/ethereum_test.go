@@ -0,0 +1,136 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/cmp"
+)
+
+// mailTypedData is the example of https://eips.ethereum.org/EIPS/eip-712.
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedDataHash(t *testing.T) {
+	hash, err := TypedDataHash([]byte(mailTypedData))
+	require.NoError(t, err)
+	assert.Equal(t, "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hex.EncodeToString(hash))
+
+	_, err = TypedDataHash([]byte(`{"types": {}, "primaryType": "Mail"}`))
+	assert.Error(t, err)
+	_, err = TypedDataHash([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestTextHash(t *testing.T) {
+	expected := crypto.Keccak256([]byte("\x19Ethereum Signed Message:\n5hello"))
+	assert.Equal(t, expected, TextHash([]byte("hello")))
+}
+
+func runSign(t *testing.T, configs map[party.ID]*cmp.Config, signers []party.ID, start func(c *cmp.Config) protocol.StartFunc) *ecdsa.Signature {
+	rounds := make([]round.Session, 0, len(signers))
+	for _, id := range signers {
+		r, err := start(configs[id])(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	require.IsType(t, &round.Output{}, rounds[0])
+	require.IsType(t, &ecdsa.Signature{}, rounds[0].(*round.Output).Result)
+	return rounds[0].(*round.Output).Result.(*ecdsa.Signature)
+}
+
+func checkRecover(t *testing.T, sig []byte, hash []byte, publicKey curve.Point) {
+	require.Len(t, sig, 65)
+	require.True(t, sig[64] == 27 || sig[64] == 28, "v should be 27 or 28")
+	raw := append([]byte{}, sig...)
+	raw[64] -= 27
+	recovered, err := crypto.SigToPub(hash, raw)
+	require.NoError(t, err)
+	assert.Equal(t, publicKey.ToAddress(), crypto.PubkeyToAddress(*recovered))
+}
+
+func TestSign(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs := test.GenerateConfig(curve.Secp256k1{}, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	signers := partyIDs[:2]
+	publicKey := configs[partyIDs[0]].PublicPoint()
+
+	text := []byte("hello")
+	sig := runSign(t, configs, signers, func(c *cmp.Config) protocol.StartFunc {
+		return SignText(c, signers, text, pl)
+	})
+	encoded, err := EncodeSignature(sig, publicKey, TextHash(text))
+	require.NoError(t, err)
+	checkRecover(t, encoded, TextHash(text), publicKey)
+
+	// the encoding is the same for the equivalent signature (-R, -s)
+	negated := &ecdsa.Signature{R: sig.R.Negate(), S: curve.Secp256k1{}.NewScalar().Set(sig.S).Negate()}
+	require.True(t, negated.Verify(publicKey, TextHash(text)))
+	encodedNegated, err := EncodeSignature(negated, publicKey, TextHash(text))
+	require.NoError(t, err)
+	assert.Equal(t, encoded, encodedNegated)
+
+	sig = runSign(t, configs, signers, func(c *cmp.Config) protocol.StartFunc {
+		return SignTypedData(c, signers, []byte(mailTypedData), pl)
+	})
+	hash, err := TypedDataHash([]byte(mailTypedData))
+	require.NoError(t, err)
+	encoded, err = EncodeSignature(sig, publicKey, hash)
+	require.NoError(t, err)
+	checkRecover(t, encoded, hash, publicKey)
+
+	_, err = EncodeSignature(sig, publicKey, TextHash(text))
+	assert.Error(t, err, "signature should not recover for a different hash")
+
+	_, err = SignTypedData(configs[signers[0]], signers, []byte("{}"), pl)(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/cmp"
+)
+
+// signerFor returns the signer for tx on the chain chainID.
+//
+// A nil chainID is only allowed for legacy transactions, which are then signed without EIP-155 replay protection.
+// Typed transactions contain their chain ID, which must be equal to chainID.
+func signerFor(tx *types.Transaction, chainID *big.Int) (types.Signer, error) {
+	if tx == nil {
+		return nil, errors.New("ethereum: transaction is nil")
+	}
+	if tx.Type() != types.LegacyTxType {
+		if chainID == nil {
+			return nil, fmt.Errorf("ethereum: transaction of type %d requires a chain ID", tx.Type())
+		}
+		if tx.ChainId().Cmp(chainID) != 0 {
+			return nil, fmt.Errorf("ethereum: transaction has chain ID %v, expected %v", tx.ChainId(), chainID)
+		}
+	}
+	return types.LatestSignerForChainID(chainID), nil
+}
+
+// TransactionHash returns the hash of tx which is signed by the sender on the chain chainID.
+//
+// Legacy transactions are hashed following EIP-155 if chainID is not nil,
+// and EIP-2930 and EIP-1559 transactions following EIP-2718.
+func TransactionHash(tx *types.Transaction, chainID *big.Int) ([]byte, error) {
+	signer, err := signerFor(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Hash(tx).Bytes(), nil
+}
+
+// SignTransaction signs the hash of tx on the chain chainID with cmp.Sign.
+//
+// Returns *ecdsa.Signature if successful, from which the signed transaction is obtained with SignedTransaction.
+func SignTransaction(config *cmp.Config, signers []party.ID, tx *types.Transaction, chainID *big.Int, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	hash, err := TransactionHash(tx, chainID)
+	if err != nil {
+		return func([]byte) (round.Session, error) { return nil, err }
+	}
+	return cmp.Sign(config, signers, hash, pl, opts...)
+}
+
+// SignedTransaction returns a copy of tx on the chain chainID with the signature sig by publicKey.
+//
+// The v value is set according to the type of tx, and the sender of the result is checked to be publicKey.ToAddress().
+func SignedTransaction(tx *types.Transaction, chainID *big.Int, sig *ecdsa.Signature, publicKey curve.Point) (*types.Transaction, error) {
+	signer, err := signerFor(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := recoverable(sig, publicKey, signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tx.WithSignature(signer, rs)
+	if err != nil {
+		return nil, fmt.Errorf("ethereum: %w", err)
+	}
+	sender, err := types.Sender(signer, signed)
+	if err != nil {
+		return nil, fmt.Errorf("ethereum: %w", err)
+	}
+	if sender != publicKey.ToAddress() {
+		return nil, errors.New("ethereum: transaction sender is not the public key")
+	}
+	return signed, nil
+}
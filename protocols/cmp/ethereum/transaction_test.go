@@ -0,0 +1,86 @@
+package ethereum
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/cmp"
+)
+
+func TestSignTransaction(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs := test.GenerateConfig(curve.Secp256k1{}, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	signers := partyIDs[1:]
+	publicKey := configs[partyIDs[0]].PublicPoint()
+
+	chainID := big.NewInt(11155111)
+	to := common.HexToAddress("0x94fD43dE0095165eE054554E1A84ccEfa8fdA47F")
+	accessList := types.AccessList{{Address: to, StorageKeys: []common.Hash{{1}}}}
+	tests := []struct {
+		name    string
+		tx      *types.Transaction
+		chainID *big.Int
+		v       []int64
+	}{
+		{"legacy", types.NewTx(&types.LegacyTx{
+			Nonce: 1, GasPrice: big.NewInt(20000000000), Gas: 21000, To: &to, Value: big.NewInt(1),
+		}), chainID, []int64{35 + 2*11155111, 36 + 2*11155111}},
+		{"legacy without EIP-155", types.NewTx(&types.LegacyTx{
+			Nonce: 2, GasPrice: big.NewInt(20000000000), Gas: 21000, To: &to, Value: big.NewInt(1),
+		}), nil, []int64{27, 28}},
+		{"EIP-2930", types.NewTx(&types.AccessListTx{
+			ChainID: chainID, Nonce: 3, GasPrice: big.NewInt(20000000000), Gas: 30000, To: &to, Value: big.NewInt(1),
+			AccessList: accessList,
+		}), chainID, []int64{0, 1}},
+		{"EIP-1559", types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainID, Nonce: 4, GasTipCap: big.NewInt(2000000000), GasFeeCap: big.NewInt(20000000000),
+			Gas: 30000, To: &to, Value: big.NewInt(1), Data: []byte{1, 2, 3}, AccessList: accessList,
+		}), chainID, []int64{0, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := runSign(t, configs, signers, func(c *cmp.Config) protocol.StartFunc {
+				return SignTransaction(c, signers, tt.tx, tt.chainID, pl)
+			})
+			signed, err := SignedTransaction(tt.tx, tt.chainID, sig, publicKey)
+			require.NoError(t, err)
+
+			sender, err := types.Sender(types.LatestSignerForChainID(tt.chainID), signed)
+			require.NoError(t, err)
+			assert.Equal(t, publicKey.ToAddress(), sender)
+			v, _, _ := signed.RawSignatureValues()
+			assert.Contains(t, tt.v, v.Int64())
+			assert.NotEqual(t, tt.tx.Hash(), signed.Hash(), "signed transaction should have a different hash")
+
+			// the signature is bound to the chain
+			_, err = SignedTransaction(tt.tx, big.NewInt(1), sig, publicKey)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTransactionHashChainID(t *testing.T) {
+	to := common.HexToAddress("0x94fD43dE0095165eE054554E1A84ccEfa8fdA47F")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID: big.NewInt(1), Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000, To: &to,
+	})
+	_, err := TransactionHash(tx, big.NewInt(1))
+	assert.NoError(t, err)
+	_, err = TransactionHash(tx, big.NewInt(5))
+	assert.Error(t, err, "chain ID should match the transaction")
+	_, err = TransactionHash(tx, nil)
+	assert.Error(t, err, "typed transactions require a chain ID")
+	_, err = TransactionHash(nil, big.NewInt(1))
+	assert.Error(t, err)
+}
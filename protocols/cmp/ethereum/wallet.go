@@ -0,0 +1,258 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/protocols/cmp"
+)
+
+// WalletScheme is the URL scheme of the accounts of a Wallet.
+const WalletScheme = "cmp"
+
+// walletAccount is an account of a Wallet, with the tweak of its key relative to the key of the config.
+type walletAccount struct {
+	account   accounts.Account
+	tweak     curve.Scalar
+	publicKey curve.Point
+}
+
+// Wallet exposes the threshold key of a cmp.Config, and its BIP-32 children, as a go-ethereum accounts.Wallet.
+//
+// Every signature runs cmp.Sign, or cmp.SignWithTweak for a child key, among the signers over the transport.
+// As with cmp.Signer, the other signers must sign the same data concurrently, for instance with their own Wallet.
+// Signatures of SignData and SignText are 65 bytes r ‖ s ‖ v with v ∈ {0, 1}, as returned by the keystore.
+type Wallet struct {
+	config    *cmp.Config
+	signers   []party.ID
+	transport cmp.Transport
+	pl        *pool.Pool
+
+	mtx      sync.RWMutex
+	accounts []walletAccount
+}
+
+var _ accounts.Wallet = (*Wallet)(nil)
+
+// NewWallet returns a Wallet for the key of config, whose only account is the one of config.PublicPoint(),
+// until children are added with Derive.
+func NewWallet(config *cmp.Config, signers []party.ID, transport cmp.Transport, pl *pool.Pool) (*Wallet, error) {
+	publicKey, ok := config.PublicPoint().(*curve.Secp256k1Point)
+	if !ok {
+		return nil, errors.New("ethereum: config is not over secp256k1")
+	}
+	w := &Wallet{
+		config:    config,
+		signers:   signers,
+		transport: transport,
+		pl:        pl,
+	}
+	w.accounts = []walletAccount{{account: w.newAccount(publicKey, nil), publicKey: publicKey}}
+	return w, nil
+}
+
+func (w *Wallet) newAccount(publicKey curve.Point, path accounts.DerivationPath) accounts.Account {
+	url := w.URL()
+	if path != nil {
+		url.Path += "/" + path.String()
+	}
+	return accounts.Account{Address: publicKey.ToAddress(), URL: url}
+}
+
+// URL returns cmp://<address>, where address is the one of the key of the config.
+func (w *Wallet) URL() accounts.URL {
+	return accounts.URL{Scheme: WalletScheme, Path: w.config.PublicPoint().ToAddress().Hex()}
+}
+
+// Status always reports the wallet as online, since the shares of config never need to be unlocked.
+func (w *Wallet) Status() (string, error) {
+	return "Online", nil
+}
+
+// Open does nothing, as the wallet holds no connection.
+func (w *Wallet) Open(string) error {
+	return nil
+}
+
+// Close does nothing, as the wallet holds no connection.
+func (w *Wallet) Close() error {
+	return nil
+}
+
+// Accounts returns the account of the key of config, followed by the children pinned by Derive.
+func (w *Wallet) Accounts() []accounts.Account {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	out := make([]accounts.Account, 0, len(w.accounts))
+	for _, a := range w.accounts {
+		out = append(out, a.account)
+	}
+	return out
+}
+
+// Contains returns true if the address of account is one of the accounts of w.
+func (w *Wallet) Contains(account accounts.Account) bool {
+	_, err := w.find(account)
+	return err == nil
+}
+
+func (w *Wallet) find(account accounts.Account) (walletAccount, error) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	for _, a := range w.accounts {
+		if a.account.Address == account.Address {
+			return a, nil
+		}
+	}
+	return walletAccount{}, accounts.ErrUnknownAccount
+}
+
+// Derive returns the account of the unhardened BIP-32 child of the key of config at path,
+// which is added to the accounts of w if pin is set.
+func (w *Wallet) Derive(path accounts.DerivationPath, pin bool) (accounts.Account, error) {
+	tweak, child, err := w.config.DeriveTweak(path.String())
+	if err != nil {
+		return accounts.Account{}, fmt.Errorf("ethereum: %w", err)
+	}
+	a := walletAccount{account: w.newAccount(child.PublicKey, path), tweak: tweak, publicKey: child.PublicKey}
+	if !pin {
+		return a.account, nil
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	for _, existing := range w.accounts {
+		if existing.account.Address == a.account.Address {
+			return existing.account, nil
+		}
+	}
+	w.accounts = append(w.accounts, a)
+	return a.account, nil
+}
+
+// SelfDerive does nothing, since discovering used accounts would require all signers to derive the same children.
+func (w *Wallet) SelfDerive([]accounts.DerivationPath, geth.ChainStateReader) {}
+
+// sign runs the signing session of hash for account.
+func (w *Wallet) sign(account accounts.Account, hash []byte) (*ecdsa.Signature, curve.Point, error) {
+	a, err := w.find(account)
+	if err != nil {
+		return nil, nil, err
+	}
+	start := cmp.Sign(w.config, w.signers, hash, w.pl)
+	if a.tweak != nil {
+		start = cmp.SignWithTweak(w.config, w.signers, a.tweak, hash, w.pl)
+	}
+	sig, err := cmp.RunSign(start, w.transport)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sig, a.publicKey, nil
+}
+
+func (w *Wallet) signHash(account accounts.Account, hash []byte) ([]byte, error) {
+	sig, publicKey, err := w.sign(account, hash)
+	if err != nil {
+		return nil, err
+	}
+	return recoverable(sig, publicKey, hash)
+}
+
+// SignData signs keccak256(data) with account. The mime type is ignored, as done by the keystore.
+func (w *Wallet) SignData(account accounts.Account, _ string, data []byte) ([]byte, error) {
+	return w.signHash(account, crypto.Keccak256(data))
+}
+
+// SignDataWithPassphrase is SignData, since the wallet does not rely on passphrases.
+func (w *Wallet) SignDataWithPassphrase(account accounts.Account, _, mimeType string, data []byte) ([]byte, error) {
+	return w.SignData(account, mimeType, data)
+}
+
+// SignText signs the EIP-191 hash of text with account.
+func (w *Wallet) SignText(account accounts.Account, text []byte) ([]byte, error) {
+	return w.signHash(account, TextHash(text))
+}
+
+// SignTextWithPassphrase is SignText, since the wallet does not rely on passphrases.
+func (w *Wallet) SignTextWithPassphrase(account accounts.Account, _ string, text []byte) ([]byte, error) {
+	return w.SignText(account, text)
+}
+
+// SignTx signs tx on the chain chainID with account, as described in TransactionHash.
+func (w *Wallet) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	hash, err := TransactionHash(tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	sig, publicKey, err := w.sign(account, hash)
+	if err != nil {
+		return nil, err
+	}
+	return SignedTransaction(tx, chainID, sig, publicKey)
+}
+
+// SignTxWithPassphrase is SignTx, since the wallet does not rely on passphrases.
+func (w *Wallet) SignTxWithPassphrase(account accounts.Account, _ string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return w.SignTx(account, tx, chainID)
+}
+
+// SignerFn returns a bind.SignerFn signing the transactions of account on the chain chainID.
+func (w *Wallet) SignerFn(account accounts.Account, chainID *big.Int) bind.SignerFn {
+	return func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if from != account.Address {
+			return nil, bind.ErrNotAuthorized
+		}
+		return w.SignTx(account, tx, chainID)
+	}
+}
+
+// NewTransactor returns the bind.TransactOpts of account on the chain chainID, with which abigen bindings
+// transact from the account, like bind.NewKeyedTransactorWithChainID does for a private key.
+func (w *Wallet) NewTransactor(account accounts.Account, chainID *big.Int) (*bind.TransactOpts, error) {
+	if !w.Contains(account) {
+		return nil, accounts.ErrUnknownAccount
+	}
+	if chainID == nil {
+		return nil, bind.ErrNoChainID
+	}
+	return &bind.TransactOpts{From: account.Address, Signer: w.SignerFn(account, chainID)}, nil
+}
+
+// Backend is an accounts.Backend holding a fixed set of wallets, so that they can be used by an accounts.Manager.
+type Backend struct {
+	wallets []accounts.Wallet
+	feed    event.Feed
+}
+
+var _ accounts.Backend = (*Backend)(nil)
+
+// NewBackend returns a Backend holding wallets.
+func NewBackend(wallets ...*Wallet) *Backend {
+	b := &Backend{wallets: make([]accounts.Wallet, 0, len(wallets))}
+	for _, w := range wallets {
+		b.wallets = append(b.wallets, w)
+	}
+	return b
+}
+
+// Wallets returns the wallets of b.
+func (b *Backend) Wallets() []accounts.Wallet {
+	return append([]accounts.Wallet{}, b.wallets...)
+}
+
+// Subscribe subscribes sink to wallet events, of which there are none since the wallets of b never change.
+func (b *Backend) Subscribe(sink chan<- accounts.WalletEvent) event.Subscription {
+	return b.feed.Subscribe(sink)
+}
@@ -0,0 +1,148 @@
+package ethereum
+
+import (
+	"math/big"
+	mrand "math/rand"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/test"
+)
+
+// newWallets returns the wallets of the signers of a key, which communicate over a test.Network.
+func newWallets(t *testing.T, pl *pool.Pool) []*Wallet {
+	configs, partyIDs := test.GenerateConfig(curve.Secp256k1{}, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	signers := partyIDs[1:]
+	network := test.NewNetwork(signers)
+	wallets := make([]*Wallet, 0, len(signers))
+	for _, id := range signers {
+		id := id
+		w, err := NewWallet(configs[id], signers, func(h protocol.Handler) error {
+			test.HandlerLoop(id, h, network)
+			return nil
+		}, pl)
+		require.NoError(t, err)
+		wallets = append(wallets, w)
+	}
+	return wallets
+}
+
+// signAll runs sign concurrently for all wallets, and returns the result of the first one.
+func signAll(t *testing.T, wallets []*Wallet, sign func(w *Wallet) (interface{}, error)) interface{} {
+	results := make([]interface{}, len(wallets))
+	errs := make([]error, len(wallets))
+	var wg sync.WaitGroup
+	for i, w := range wallets {
+		wg.Add(1)
+		go func(i int, w *Wallet) {
+			defer wg.Done()
+			results[i], errs[i] = sign(w)
+		}(i, w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	return results[0]
+}
+
+func TestWallet(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	wallets := newWallets(t, pl)
+	master := wallets[0].Accounts()[0]
+	assert.Equal(t, wallets[0].config.PublicPoint().ToAddress(), master.Address)
+	assert.Equal(t, WalletScheme, master.URL.Scheme)
+
+	path := accounts.DerivationPath{0, 7}
+	var child accounts.Account
+	for _, w := range wallets {
+		unpinned, err := w.Derive(path, false)
+		require.NoError(t, err)
+		assert.False(t, w.Contains(unpinned))
+		child, err = w.Derive(path, true)
+		require.NoError(t, err)
+		assert.Equal(t, unpinned, child)
+		assert.True(t, w.Contains(child))
+		assert.Len(t, w.Accounts(), 2)
+	}
+	derived, err := wallets[0].config.DerivePath("m/0/7")
+	require.NoError(t, err)
+	assert.Equal(t, derived.PublicPoint().ToAddress(), child.Address)
+	_, err = wallets[0].Derive(accounts.DerivationPath{0x80000000}, true)
+	assert.Error(t, err, "hardened derivation is not supported")
+
+	to := common.HexToAddress("0x94fD43dE0095165eE054554E1A84ccEfa8fdA47F")
+	chainID := big.NewInt(11155111)
+	for _, account := range []accounts.Account{master, child} {
+		text := []byte("hello")
+		sig := signAll(t, wallets, func(w *Wallet) (interface{}, error) { return w.SignText(account, text) }).([]byte)
+		recovered, err := crypto.SigToPub(accounts.TextHash(text), sig)
+		require.NoError(t, err)
+		assert.Equal(t, account.Address, crypto.PubkeyToAddress(*recovered))
+
+		data := []byte{1, 2, 3}
+		sig = signAll(t, wallets, func(w *Wallet) (interface{}, error) {
+			return w.SignData(account, accounts.MimetypeTextPlain, data)
+		}).([]byte)
+		recovered, err = crypto.SigToPub(crypto.Keccak256(data), sig)
+		require.NoError(t, err)
+		assert.Equal(t, account.Address, crypto.PubkeyToAddress(*recovered))
+
+		tx := types.NewTx(&types.DynamicFeeTx{
+			ChainID: chainID, Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000, To: &to,
+		})
+		signed := signAll(t, wallets, func(w *Wallet) (interface{}, error) {
+			return w.SignTx(account, tx, chainID)
+		}).(*types.Transaction)
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		require.NoError(t, err)
+		assert.Equal(t, account.Address, sender)
+	}
+
+	unknown := accounts.Account{Address: to}
+	_, err = wallets[0].SignText(unknown, []byte("hello"))
+	assert.ErrorIs(t, err, accounts.ErrUnknownAccount)
+}
+
+func TestWalletTransactor(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	wallets := newWallets(t, pl)
+	account := wallets[0].Accounts()[0]
+	chainID := big.NewInt(1)
+
+	// the wallet can be found through an accounts.Manager, as done by geth tooling
+	manager := accounts.NewManager(&accounts.Config{}, NewBackend(wallets[0]))
+	defer manager.Close()
+	found, err := manager.Find(account)
+	require.NoError(t, err)
+	assert.Equal(t, wallets[0], found)
+
+	to := common.HexToAddress("0x94fD43dE0095165eE054554E1A84ccEfa8fdA47F")
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+	signed := signAll(t, wallets, func(w *Wallet) (interface{}, error) {
+		opts, err := w.NewTransactor(account, chainID)
+		if err != nil {
+			return nil, err
+		}
+		return opts.Signer(opts.From, tx)
+	}).(*types.Transaction)
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, account.Address, sender)
+
+	_, err = wallets[0].SignerFn(account, chainID)(to, tx)
+	assert.Error(t, err, "transactions of other accounts should not be signed")
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+const (
+	// Identifier for the export protocol.
+	protocolID = "cmp/export"
+	// protocolRounds is the number of rounds of the export protocol.
+	protocolRounds round.Number = 2
+)
+
+// Request describes an export of the private key, which every participant must approve.
+type Request struct {
+	// Recipient is the public key to which the private key is encrypted.
+	Recipient curve.Point
+	// Reason is a description of why the key is exported, which is included in the Record.
+	Reason string
+}
+
+// Start returns a protocol.StartFunc which reconstructs the private key of c, encrypted to req.Recipient.
+//
+// `participants` must contain at least c.Threshold+1 parties, which all run the protocol with the same Request.
+// Running the protocol is the explicit approval of a participant: each one signs the Request with its share,
+// and the protocol only succeeds if all `participants` did.
+// The private key is never known to any participant, only to the holder of the recipient's secret key,
+// who obtains it with Export.Decrypt.
+// Returns *export.Export if successful.
+func Start(c *config.Config, participants []party.ID, req *Request, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if req == nil || req.Recipient == nil || req.Recipient.IsIdentity() {
+			return nil, errors.New("export: invalid recipient")
+		}
+		if req.Recipient.Curve().Name() != c.Group.Name() {
+			return nil, errors.New("export: recipient has a different group")
+		}
+		signers := party.NewIDSlice(participants)
+		if !c.CanSign(signers) {
+			return nil, errors.New("export: participants are not a valid subset of the parties")
+		}
+
+		info := round.Info{
+			ProtocolID:       protocolID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           c.ID,
+			PartyIDs:         signers,
+			Threshold:        c.Threshold,
+			Group:            c.Group,
+		}
+		info.Apply(opts...)
+		recipient, err := req.Recipient.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("export: %w", err)
+		}
+		helper, err := round.NewSession(info, sessionID, pl, c,
+			&hash.BytesWithDomain{TheDomain: "Export Recipient", Bytes: recipient},
+			&hash.BytesWithDomain{TheDomain: "Export Reason", Bytes: []byte(req.Reason)})
+		if err != nil {
+			return nil, fmt.Errorf("export: %w", err)
+		}
+
+		PublicShares := make(map[party.ID]curve.Point, len(signers))
+		for _, j := range signers {
+			PublicShares[j] = c.Public[j].ECDSA
+		}
+		return &round1{
+			Helper:  helper,
+			Config:  c,
+			Request: req,
+			Record: &Record{
+				Group:        c.Group,
+				SSID:         helper.SSID(),
+				PublicKey:    c.PublicPoint(),
+				Recipient:    req.Recipient,
+				Reason:       req.Reason,
+				Participants: signers,
+				PublicShares: PublicShares,
+			},
+		}, nil
+	}
+}
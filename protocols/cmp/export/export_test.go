@@ -0,0 +1,87 @@
+package export
+
+import (
+	"crypto/rand"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+)
+
+func TestExport(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := 1
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	participants := partyIDs[1:]
+	publicKey := configs[partyIDs[0]].PublicPoint()
+
+	recipientSecret, recipient := sample.ScalarPointPair(rand.Reader, group)
+	req := &Request{Recipient: recipient, Reason: "exit"}
+
+	rounds := make([]round.Session, 0, len(participants))
+	for _, id := range participants {
+		r, err := Start(configs[id], participants, req, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		require.IsType(t, &Export{}, r.(*round.Output).Result)
+		result := r.(*round.Output).Result.(*Export)
+
+		data, err := cbor.Marshal(result)
+		require.NoError(t, err)
+		e := EmptyExport(group)
+		require.NoError(t, cbor.Unmarshal(data, e))
+
+		require.NoError(t, e.Record.Verify())
+		assert.Equal(t, participants, e.Record.Participants)
+		assert.Equal(t, "exit", e.Record.Reason)
+
+		secret, err := e.Decrypt(recipientSecret)
+		require.NoError(t, err)
+		assert.True(t, secret.ActOnBase().Equal(publicKey), "exported key does not match the public key")
+
+		_, err = e.Decrypt(sample.Scalar(rand.Reader, group))
+		assert.Error(t, err, "decrypting with a different key should fail")
+
+		e.Record.Reason = "something else"
+		assert.Error(t, e.Record.Verify(), "approvals should be bound to the reason")
+	}
+}
+
+func TestExportInvalid(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	c := configs[partyIDs[0]]
+	_, recipient := sample.ScalarPointPair(rand.Reader, group)
+
+	_, err := Start(c, partyIDs[:1], &Request{Recipient: recipient}, pl)(nil)
+	assert.Error(t, err, "not enough participants")
+
+	_, err = Start(c, partyIDs, &Request{Recipient: group.NewPoint()}, pl)(nil)
+	assert.Error(t, err, "recipient is identity")
+}
@@ -0,0 +1,251 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+// Record is the audit record of an export. It can be verified by anyone, without access to the Config.
+type Record struct {
+	Group curve.Curve
+	// SSID identifies the protocol execution.
+	SSID []byte
+	// PublicKey is the public key of the exported private key.
+	PublicKey curve.Point
+	// Recipient is the public key to which the private key was encrypted.
+	Recipient curve.Point
+	// Reason is the description of the export approved by the participants.
+	Reason string
+	// Participants are the parties which approved the export.
+	Participants party.IDSlice
+	// PublicShares[j] = Xⱼ is the public key share of participant j.
+	PublicShares map[party.ID]curve.Point
+	// Approvals[j] is a proof of knowledge of xⱼ, bound to all the above fields.
+	Approvals map[party.ID]*zksch.Proof
+}
+
+// Export is the output of the export protocol, which should be sent to the recipient.
+type Export struct {
+	Record *Record
+	// Shares[j] is the encryption of λⱼ⋅xⱼ to the recipient.
+	Shares map[party.ID]*EncryptedShare
+}
+
+// EncryptedShare is the encryption of a scalar y to a recipient R, where
+// K = k⋅G, and C = y + H(k⋅R, K, …).
+type EncryptedShare struct {
+	K curve.Point
+	C curve.Scalar
+}
+
+// approvalHash returns the hash used by party j to approve the export described in r.
+func (r *Record) approvalHash(j party.ID) *hash.Hash {
+	h := hash.New(&hash.BytesWithDomain{TheDomain: "Export SSID", Bytes: r.SSID})
+	_ = h.WriteAny(r.PublicKey, r.Recipient,
+		&hash.BytesWithDomain{TheDomain: "Export Reason", Bytes: []byte(r.Reason)},
+		r.Participants, j)
+	return h
+}
+
+// Verify checks that the public shares of the participants correspond to PublicKey,
+// and that all participants approved the export.
+func (r *Record) Verify() error {
+	if r.PublicKey == nil || r.Recipient == nil || r.PublicKey.IsIdentity() || r.Recipient.IsIdentity() {
+		return errors.New("export: invalid public key or recipient")
+	}
+	if len(r.Participants) == 0 || !r.Participants.Valid() {
+		return errors.New("export: invalid participants")
+	}
+	lagrange := polynomial.Lagrange(r.Group, r.Participants)
+	PublicKey := r.Group.NewPoint()
+	for _, j := range r.Participants {
+		X, ok := r.PublicShares[j]
+		if !ok {
+			return fmt.Errorf("export: missing public share of %s", j)
+		}
+		if !r.Approvals[j].Verify(r.approvalHash(j), X, nil) {
+			return fmt.Errorf("export: invalid approval of %s", j)
+		}
+		PublicKey = PublicKey.Add(lagrange[j].Act(X))
+	}
+	if !PublicKey.Equal(r.PublicKey) {
+		return errors.New("export: public shares do not match the public key")
+	}
+	return nil
+}
+
+// encryptShare encrypts y for the recipient R, with an ephemeral key sampled from rand.
+func encryptShare(rand io.Reader, ssid []byte, j party.ID, R curve.Point, y curve.Scalar) *EncryptedShare {
+	group := y.Curve()
+	k, K := sample.ScalarPointPair(rand, group)
+	mask := shareMask(ssid, j, K, k.Act(R))
+	return &EncryptedShare{
+		K: K,
+		C: group.NewScalar().Set(y).Add(mask),
+	}
+}
+
+// shareMask derives the mask H(S, K, …) for the encrypted share of party j, where S is the shared secret.
+func shareMask(ssid []byte, j party.ID, K, S curve.Point) curve.Scalar {
+	h := hash.New(&hash.BytesWithDomain{TheDomain: "Export Share", Bytes: ssid})
+	_ = h.WriteAny(j, K, S)
+	return sample.Scalar(h.Digest(), K.Curve())
+}
+
+// Decrypt verifies the record, and recovers the private key using the recipient's secret key.
+// The result is checked against Record.PublicKey.
+func (e *Export) Decrypt(recipientSecret curve.Scalar) (curve.Scalar, error) {
+	r := e.Record
+	if err := r.Verify(); err != nil {
+		return nil, err
+	}
+	if !recipientSecret.ActOnBase().Equal(r.Recipient) {
+		return nil, errors.New("export: secret key does not match the recipient")
+	}
+
+	group := r.Group
+	lagrange := polynomial.Lagrange(group, r.Participants)
+	secret := group.NewScalar()
+	for _, j := range r.Participants {
+		share, ok := e.Shares[j]
+		if !ok || share == nil || share.K == nil || share.C == nil {
+			return nil, fmt.Errorf("export: missing share of %s", j)
+		}
+		// yⱼ = C - H(r⋅K, K, …)
+		mask := shareMask(r.SSID, j, share.K, recipientSecret.Act(share.K))
+		y := group.NewScalar().Set(share.C).Sub(mask)
+		// yⱼ⋅G = λⱼ⋅Xⱼ
+		if !y.ActOnBase().Equal(lagrange[j].Act(r.PublicShares[j])) {
+			return nil, fmt.Errorf("export: invalid share of %s", j)
+		}
+		secret.Add(y)
+	}
+	if !secret.ActOnBase().Equal(r.PublicKey) {
+		return nil, errors.New("export: reconstructed key does not match the public key")
+	}
+	return secret, nil
+}
+
+// EmptyExport creates an empty Export with a fixed group, ready for unmarshalling.
+func EmptyExport(group curve.Curve) *Export {
+	return &Export{Record: &Record{Group: group}}
+}
+
+type exportMarshal struct {
+	SSID                 []byte
+	PublicKey, Recipient []byte
+	Reason               string
+	Participants         []cbor.RawMessage
+}
+
+type participantMarshal struct {
+	ID          party.ID
+	PublicShare []byte
+	Approval    *zksch.Proof
+	K           []byte
+	C           []byte
+}
+
+func (e *Export) MarshalBinary() ([]byte, error) {
+	r := e.Record
+	PublicKey, err := r.PublicKey.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	Recipient, err := r.Recipient.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	ps := make([]cbor.RawMessage, 0, len(r.Participants))
+	for _, j := range r.Participants {
+		p := &participantMarshal{ID: j, Approval: r.Approvals[j]}
+		if p.PublicShare, err = r.PublicShares[j].MarshalBinary(); err != nil {
+			return nil, err
+		}
+		if p.K, err = e.Shares[j].K.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		if p.C, err = e.Shares[j].C.MarshalBinary(); err != nil {
+			return nil, err
+		}
+		data, err := cbor.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, data)
+	}
+	return cbor.Marshal(&exportMarshal{
+		SSID:         r.SSID,
+		PublicKey:    PublicKey,
+		Recipient:    Recipient,
+		Reason:       r.Reason,
+		Participants: ps,
+	})
+}
+
+func (e *Export) UnmarshalBinary(data []byte) error {
+	if e.Record == nil || e.Record.Group == nil {
+		return errors.New("export must be initialized using EmptyExport")
+	}
+	group := e.Record.Group
+	var em exportMarshal
+	if err := cbor.Unmarshal(data, &em); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	r := &Record{
+		Group:        group,
+		SSID:         em.SSID,
+		PublicKey:    group.NewPoint(),
+		Recipient:    group.NewPoint(),
+		Reason:       em.Reason,
+		PublicShares: make(map[party.ID]curve.Point, len(em.Participants)),
+		Approvals:    make(map[party.ID]*zksch.Proof, len(em.Participants)),
+	}
+	if err := r.PublicKey.UnmarshalBinary(em.PublicKey); err != nil {
+		return fmt.Errorf("export: public key: %w", err)
+	}
+	if err := r.Recipient.UnmarshalBinary(em.Recipient); err != nil {
+		return fmt.Errorf("export: recipient: %w", err)
+	}
+	shares := make(map[party.ID]*EncryptedShare, len(em.Participants))
+	ids := make([]party.ID, 0, len(em.Participants))
+	for _, pm := range em.Participants {
+		// preallocate the proof, so that its points can be decoded
+		p := participantMarshal{Approval: zksch.EmptyProof(group)}
+		if err := cbor.Unmarshal(pm, &p); err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+		if _, ok := shares[p.ID]; ok {
+			return fmt.Errorf("export: party %s: duplicate entry", p.ID)
+		}
+		share := &EncryptedShare{K: group.NewPoint(), C: group.NewScalar()}
+		PublicShare := group.NewPoint()
+		if err := PublicShare.UnmarshalBinary(p.PublicShare); err != nil {
+			return fmt.Errorf("export: party %s: %w", p.ID, err)
+		}
+		if err := share.K.UnmarshalBinary(p.K); err != nil {
+			return fmt.Errorf("export: party %s: %w", p.ID, err)
+		}
+		if err := share.C.UnmarshalBinary(p.C); err != nil {
+			return fmt.Errorf("export: party %s: %w", p.ID, err)
+		}
+		ids = append(ids, p.ID)
+		r.PublicShares[p.ID] = PublicShare
+		r.Approvals[p.ID] = p.Approval
+		shares[p.ID] = share
+	}
+	r.Participants = party.NewIDSlice(ids)
+
+	*e = Export{Record: r, Shares: shares}
+	return nil
+}
@@ -0,0 +1,58 @@
+package export
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+
+	Config  *config.Config
+	Request *Request
+
+	// Record is the audit record, without the approvals.
+	Record *Record
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - approve the export with a proof of knowledge of xᵢ, bound to the record
+// - compute yᵢ = λᵢ⋅xᵢ and encrypt it to the recipient
+// - broadcast the approval and the encrypted share.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	X := r.Record.PublicShares[r.SelfID()]
+	Approval := zksch.NewProof(r.Rand(), r.Record.approvalHash(r.SelfID()), X, r.Config.ECDSA, nil)
+
+	// yᵢ = λᵢ⋅xᵢ
+	lagrange := polynomial.Lagrange(r.Group(), r.PartyIDs())
+	y := r.Group().NewScalar().Set(lagrange[r.SelfID()]).Mul(r.Config.ECDSA)
+	Share := encryptShare(r.Rand(), r.SSID(), r.SelfID(), r.Request.Recipient, y)
+
+	if err := r.BroadcastMessage(out, &broadcast2{Approval: Approval, K: Share.K, C: Share.C}); err != nil {
+		return r, err
+	}
+
+	r.Record.Approvals = map[party.ID]*zksch.Proof{r.SelfID(): Approval}
+	return &round2{
+		round1: r,
+		Shares: map[party.ID]*EncryptedShare{r.SelfID(): Share},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
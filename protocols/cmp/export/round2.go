@@ -0,0 +1,83 @@
+package export
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+
+	// Shares[j] = (Kⱼ, Cⱼ) is the encryption of yⱼ to the recipient.
+	Shares map[party.ID]*EncryptedShare
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// Approval is a proof of knowledge of xᵢ bound to the record.
+	Approval *zksch.Proof
+	// K, C = kᵢ⋅G, yᵢ + H(kᵢ⋅R, …)
+	K curve.Point
+	C curve.Scalar
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify the approval of party j
+// - store the approval and the encrypted share.
+func (r *round2) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.K.IsIdentity() {
+		return round.ErrNilFields
+	}
+	if !body.Approval.Verify(r.Record.approvalHash(from), r.Record.PublicShares[from], nil) {
+		return errors.New("failed to validate approval")
+	}
+	r.Record.Approvals[from] = body.Approval
+	r.Shares[from] = &EncryptedShare{K: body.K, C: body.C}
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - output the record and the encrypted shares.
+func (r *round2) Finalize(chan<- *round.Message) (round.Session, error) {
+	return r.ResultRound(&Export{
+		Record: r.Record,
+		Shares: r.Shares,
+	}), nil
+}
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2{
+		Approval: zksch.EmptyProof(r.Group()),
+		K:        r.Group().NewPoint(),
+		C:        r.Group().NewScalar(),
+	}
+}
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
@@ -1,9 +1,10 @@
 package keygen
 
 import (
-	"crypto/rand"
+	"errors"
 	"fmt"
 
+	"github.com/w3-key/mps-lean/pkg/hash"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/math/polynomial"
 	"github.com/w3-key/mps-lean/pkg/math/sample"
@@ -11,6 +12,7 @@ import (
 	"github.com/w3-key/mps-lean/pkg/pool"
 	"github.com/w3-key/mps-lean/pkg/protocol"
 	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
 	"github.com/w3-key/mps-lean/protocols/cmp/config"
 )
 
@@ -27,30 +29,234 @@ func Start(info round.Info, pl *pool.Pool, c *config.Config) protocol.StartFunc
 		if err != nil {
 			return nil, fmt.Errorf("keygen: %w", err)
 		}
+		return newRound1(helper, c), nil
+	}
+}
+
+// StartWithAuxInfo is the same as Start, except that the Paillier, Pedersen and ElGamal parameters in aux are reused
+// instead of generating and proving new ones. aux must have been generated by all parties in info.PartyIDs.
+//
+// When refreshing, aux is usually c.AuxInfo().
+func StartWithAuxInfo(info round.Info, pl *pool.Pool, c *config.Config, aux *config.AuxInfo) protocol.StartFunc {
+	return func(sessionID []byte) (_ round.Session, err error) {
+		if aux == nil {
+			return nil, errors.New("keygen: aux info is nil")
+		}
+		if aux.Group.Name() != info.Group.Name() {
+			return nil, errors.New("keygen: aux info has a different group")
+		}
+		if aux.ID != info.SelfID {
+			return nil, errors.New("keygen: aux info belongs to a different party")
+		}
+		partyIDs := party.NewIDSlice(info.PartyIDs)
+		if len(aux.Public) != len(partyIDs) || !aux.PartyIDs().Contains(partyIDs...) {
+			return nil, errors.New("keygen: aux info was generated by a different set of parties")
+		}
+
+		var helper *round.Helper
+		if c == nil {
+			helper, err = round.NewSession(info, sessionID, pl, aux)
+		} else {
+			helper, err = round.NewSession(info, sessionID, pl, aux, c)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("keygen: %w", err)
+		}
+		r := newRound1(helper, c)
+		r.AuxInfo = aux
+		return r, nil
+	}
+}
+
+// newRound1 returns the first round of a keygen, or of a refresh if c is not nil.
+func newRound1(helper *round.Helper, c *config.Config) *round1 {
+	group := helper.Group()
+
+	if c != nil {
+		PublicSharesECDSA := make(map[party.ID]curve.Point, len(c.Public))
+		for id, public := range c.Public {
+			PublicSharesECDSA[id] = public.ECDSA
+		}
+		return &round1{
+			Helper:                    helper,
+			PreviousSecretECDSA:       c.ECDSA,
+			PreviousPublicSharesECDSA: PublicSharesECDSA,
+			PreviousChainKey:          c.ChainKey,
+			PreviousLocation:          locationOf(c),
+			VSSSecret:                 polynomial.NewPolynomial(helper.Rand(), group, helper.Threshold(), group.NewScalar()), // fᵢ(X) deg(fᵢ) = t, fᵢ(0) = 0
+		}
+	}
+
+	// sample fᵢ(X) deg(fᵢ) = t, fᵢ(0) = secretᵢ
+	VSSConstant := sample.Scalar(helper.Rand(), group)
+	VSSSecret := polynomial.NewPolynomial(helper.Rand(), group, helper.Threshold(), VSSConstant)
+	return &round1{
+		Helper:    helper,
+		VSSSecret: VSSSecret,
+	}
+}
+
+// StartReshare moves the key with public key `publicKey` from the parties in `dealers` to the parties in `receivers`.
+// info.PartyIDs must contain exactly the union of both sets, and info.Threshold is the threshold of the new sharing.
+//
+// Parties holding a share of the key pass their config c, newcomers pass c == nil.
+// Dealers share their Lagrange scaled secret λᵢ⋅xᵢ with a polynomial of degree info.Threshold,
+// and every other party shares 0.
+func StartReshare(info round.Info, pl *pool.Pool, c *config.Config, publicKey curve.Point, dealers, receivers []party.ID) protocol.StartFunc {
+	return func(sessionID []byte) (_ round.Session, err error) {
+		dealerIDs := party.NewIDSlice(dealers)
+		receiverIDs := party.NewIDSlice(receivers)
+		if !dealerIDs.Valid() || !receiverIDs.Valid() {
+			return nil, errors.New("reshare: dealers or receivers invalid")
+		}
+		if len(dealerIDs) == 0 {
+			return nil, errors.New("reshare: no dealers")
+		}
+		if !config.ValidThreshold(info.Threshold, len(receiverIDs)) {
+			return nil, fmt.Errorf("reshare: threshold %d is invalid for %d receivers", info.Threshold, len(receiverIDs))
+		}
+		for _, j := range info.PartyIDs {
+			if !dealerIDs.Contains(j) && !receiverIDs.Contains(j) {
+				return nil, fmt.Errorf("reshare: party %s is neither dealer nor receiver", j)
+			}
+		}
+		if !party.NewIDSlice(info.PartyIDs).Contains(dealerIDs...) || !party.NewIDSlice(info.PartyIDs).Contains(receiverIDs...) {
+			return nil, errors.New("reshare: dealers and receivers must take part in the protocol")
+		}
+		if publicKey == nil || publicKey.IsIdentity() {
+			return nil, errors.New("reshare: invalid public key")
+		}
 
-		group := helper.Group()
+		group := info.Group
+		publicKeyBytes, err := publicKey.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("reshare: %w", err)
+		}
+		helper, err := round.NewSession(info, sessionID, pl, dealerIDs, &hash.BytesWithDomain{
+			TheDomain: "Public Key",
+			Bytes:     publicKeyBytes,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reshare: %w", err)
+		}
 
+		// λᵢ⋅X'ᵢ for all dealers
+		var DealerPublicShares map[party.ID]curve.Point
+		// fᵢ(0) = λᵢ⋅x'ᵢ for a dealer, 0 otherwise
+		VSSConstant := group.NewScalar()
+		var PreviousChainKey types.RID
+		var PreviousLocation bip32Location
 		if c != nil {
-			PublicSharesECDSA := make(map[party.ID]curve.Point, len(c.Public))
-			for id, public := range c.Public {
-				PublicSharesECDSA[id] = public.ECDSA
-			}
-			return &round1{
-				Helper:                    helper,
-				PreviousSecretECDSA:       c.ECDSA,
-				PreviousPublicSharesECDSA: PublicSharesECDSA,
-				PreviousChainKey:          c.ChainKey,
-				VSSSecret:                 polynomial.NewPolynomial(group, helper.Threshold(), group.NewScalar()), // fᵢ(X) deg(fᵢ) = t, fᵢ(0) = 0
-			}, nil
-		}
-
-		// sample fᵢ(X) deg(fᵢ) = t, fᵢ(0) = secretᵢ
-		VSSConstant := sample.Scalar(rand.Reader, group)
-		VSSSecret := polynomial.NewPolynomial(group, helper.Threshold(), VSSConstant)
+			if c.Group.Name() != group.Name() {
+				return nil, errors.New("reshare: config has a different group")
+			}
+			if !c.PublicPoint().Equal(publicKey) {
+				return nil, errors.New("reshare: config has a different public key")
+			}
+			if !config.ValidThreshold(c.Threshold, len(dealerIDs)) {
+				return nil, fmt.Errorf("reshare: need at least %d dealers, got %d", c.Threshold+1, len(dealerIDs))
+			}
+			lagrange := polynomial.Lagrange(group, dealerIDs)
+			DealerPublicShares = make(map[party.ID]curve.Point, len(dealerIDs))
+			for _, j := range dealerIDs {
+				public, ok := c.Public[j]
+				if !ok {
+					return nil, fmt.Errorf("reshare: dealer %s does not hold a share", j)
+				}
+				DealerPublicShares[j] = lagrange[j].Act(public.ECDSA)
+			}
+			if dealerIDs.Contains(c.ID) {
+				VSSConstant = group.NewScalar().Set(lagrange[c.ID]).Mul(c.ECDSA)
+			}
+			PreviousChainKey = c.ChainKey
+			PreviousLocation = locationOf(c)
+		} else if dealerIDs.Contains(info.SelfID) {
+			return nil, errors.New("reshare: dealer must provide its config")
+		}
+
 		return &round1{
-			Helper:    helper,
-			VSSSecret: VSSSecret,
+			Helper:             helper,
+			PreviousChainKey:   PreviousChainKey,
+			PreviousLocation:   PreviousLocation,
+			VSSSecret:          polynomial.NewPolynomial(helper.Rand(), group, helper.Threshold(), VSSConstant),
+			Dealers:            dealerIDs,
+			Receivers:          receiverIDs,
+			DealerPublicShares: DealerPublicShares,
+			PublicKey:          publicKey,
 		}, nil
+	}
+}
 
+// StartImport shares the existing private key of the party `owner` among `receivers`, such that the resulting
+// configs have public key `publicKey`. info.PartyIDs must contain exactly `owner` and `receivers`,
+// and info.Threshold is the threshold of the new sharing.
+//
+// This is a reshare with the owner as single dealer, which shares its secret with a polynomial of degree info.Threshold.
+// The owner passes its secret, all other parties pass secret == nil.
+// Since there is no previous chain key, the owner samples a new one.
+func StartImport(info round.Info, pl *pool.Pool, secret curve.Scalar, publicKey curve.Point, owner party.ID, receivers []party.ID) protocol.StartFunc {
+	return func(sessionID []byte) (_ round.Session, err error) {
+		receiverIDs := party.NewIDSlice(receivers)
+		if !receiverIDs.Valid() {
+			return nil, errors.New("import: receivers invalid")
+		}
+		if !config.ValidThreshold(info.Threshold, len(receiverIDs)) {
+			return nil, fmt.Errorf("import: threshold %d is invalid for %d receivers", info.Threshold, len(receiverIDs))
+		}
+		partyIDs := party.NewIDSlice(info.PartyIDs)
+		for _, j := range partyIDs {
+			if j != owner && !receiverIDs.Contains(j) {
+				return nil, fmt.Errorf("import: party %s is neither owner nor receiver", j)
+			}
+		}
+		if !partyIDs.Contains(owner) || !partyIDs.Contains(receiverIDs...) {
+			return nil, errors.New("import: owner and receivers must take part in the protocol")
+		}
+		if publicKey == nil || publicKey.IsIdentity() {
+			return nil, errors.New("import: invalid public key")
+		}
+
+		group := info.Group
+		// fᵢ(0) = x for the owner, 0 otherwise
+		VSSConstant := group.NewScalar()
+		var PreviousChainKey types.RID
+		if info.SelfID == owner {
+			if secret == nil || secret.IsZero() {
+				return nil, errors.New("import: owner must provide a non-zero secret")
+			}
+			if !secret.ActOnBase().Equal(publicKey) {
+				return nil, errors.New("import: secret does not match public key")
+			}
+			VSSConstant.Set(secret)
+		} else if secret != nil {
+			return nil, errors.New("import: only the owner provides a secret")
+		}
+
+		publicKeyBytes, err := publicKey.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("import: %w", err)
+		}
+		helper, err := round.NewSession(info, sessionID, pl, party.IDSlice{owner}, &hash.BytesWithDomain{
+			TheDomain: "Public Key",
+			Bytes:     publicKeyBytes,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("import: %w", err)
+		}
+		if info.SelfID == owner {
+			if PreviousChainKey, err = types.NewRID(helper.Rand()); err != nil {
+				return nil, fmt.Errorf("import: %w", err)
+			}
+		}
+
+		return &round1{
+			Helper:             helper,
+			PreviousChainKey:   PreviousChainKey,
+			VSSSecret:          polynomial.NewPolynomial(helper.Rand(), group, helper.Threshold(), VSSConstant),
+			Dealers:            party.IDSlice{owner},
+			Receivers:          receiverIDs,
+			DealerPublicShares: map[party.ID]curve.Point{owner: publicKey},
+			PublicKey:          publicKey,
+		}, nil
 	}
 }
@@ -1,13 +1,18 @@
 package keygen
 
 import (
+	"crypto/rand"
 	mrand "math/rand"
 	"testing"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/bip32"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
 	"github.com/w3-key/mps-lean/pkg/pool"
 	"github.com/w3-key/mps-lean/pkg/round"
 	"github.com/w3-key/mps-lean/pkg/test"
@@ -119,3 +124,362 @@ func TestRefresh(t *testing.T) {
 	}
 	checkOutput(t, rounds)
 }
+
+func TestRefreshDerivePath(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N := 2
+	configs, _ := test.GenerateConfig(group, N, N-1, mrand.New(mrand.NewSource(1)), pl)
+
+	rounds := make([]round.Session, 0, N)
+	var xpub string
+	for _, c := range configs {
+		derived, err := c.DerivePath("m/44/60/0/0/7")
+		require.NoError(t, err)
+		extended, err := derived.ExtendedPublicKey(bip32.MainnetPublic)
+		require.NoError(t, err)
+		xpub = extended.String()
+
+		info := round.Info{
+			ProtocolID:       "cmp/refresh-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           c.ID,
+			PartyIDs:         c.PartyIDs(),
+			Threshold:        N - 1,
+			Group:            group,
+		}
+		r, err := Start(info, pl, derived)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	checkOutput(t, rounds)
+
+	for _, r := range rounds {
+		c := r.(*round.Output).Result.(*config.Config)
+		refreshed, err := c.ExtendedPublicKey(bip32.MainnetPublic)
+		require.NoError(t, err)
+		assert.Equal(t, xpub, refreshed.String(), "refresh should keep the xpub of a derived key")
+		assert.EqualValues(t, 5, c.Depth)
+	}
+}
+
+func TestReshare(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	// reshare a derived key, whose BIP-32 location must reach the newcomers
+	for id, c := range configs {
+		derived, err := c.DerivePath("m/0/3")
+		require.NoError(t, err)
+		configs[id] = derived
+	}
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	xpub, err := configs[partyIDs[0]].ExtendedPublicKey(bip32.MainnetPublic)
+	require.NoError(t, err)
+
+	// a retires, b deals and stays, c stays without dealing, d and e join.
+	allIDs := test.PartyIDs(5)
+	dealers := party.IDSlice{allIDs[0], allIDs[1]}
+	receivers := party.IDSlice{allIDs[1], allIDs[2], allIDs[3], allIDs[4]}
+	newThreshold := 2
+
+	rounds := make([]round.Session, 0, len(allIDs))
+	for _, id := range allIDs {
+		info := round.Info{
+			ProtocolID:       "cmp/reshare-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           id,
+			PartyIDs:         allIDs,
+			Threshold:        newThreshold,
+			Group:            group,
+		}
+		r, err := StartReshare(info, pl, configs[id], publicKey, dealers, receivers)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+		if r2, ok := rounds[0].(*round2); ok {
+			assert.Nil(t, r2.PaillierSecret, "retiring dealer should not generate a Paillier key")
+		}
+	}
+
+	newConfigs := make(map[party.ID]*config.Config, len(receivers))
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		result := r.(*round.Output).Result
+		if !receivers.Contains(r.SelfID()) {
+			require.Implements(t, (*curve.Point)(nil), result)
+			assert.True(t, publicKey.Equal(result.(curve.Point)), "public key is different")
+			continue
+		}
+		require.IsType(t, &config.Config{}, result)
+		c := result.(*config.Config)
+		assert.Equal(t, newThreshold, c.Threshold)
+		assert.Equal(t, receivers, c.PartyIDs())
+		assert.True(t, publicKey.Equal(c.PublicPoint()), "public key is different")
+		assert.EqualValues(t, configs[partyIDs[0]].ChainKey, c.ChainKey, "chain key is different")
+		assert.True(t, c.ECDSA.ActOnBase().Equal(c.Public[c.ID].ECDSA), "public share is inconsistent")
+		newXpub, err := c.ExtendedPublicKey(bip32.MainnetPublic)
+		require.NoError(t, err)
+		assert.Equal(t, xpub.String(), newXpub.String(), "xpub is different")
+		newConfigs[c.ID] = c
+	}
+
+	// any t+1 new shares reconstruct the same secret
+	signers := receivers[1:]
+	lagrange := polynomial.Lagrange(group, signers)
+	secret := group.NewScalar()
+	for _, j := range signers {
+		secret.Add(group.NewScalar().Set(lagrange[j]).Mul(newConfigs[j].ECDSA))
+	}
+	assert.True(t, publicKey.Equal(secret.ActOnBase()), "new shares do not reconstruct the secret")
+}
+
+func TestKeygenWithAuxInfo(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N := 3
+	T := 1
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		info := round.Info{
+			ProtocolID:       "cmp/keygen-aux-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           partyID,
+			PartyIDs:         partyIDs,
+			Threshold:        T,
+			Group:            group,
+		}
+		r, err := StartWithAuxInfo(info, pl, nil, configs[partyID].AuxInfo())(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	checkOutput(t, rounds)
+
+	for _, r := range rounds {
+		c := r.(*round.Output).Result.(*config.Config)
+		assert.False(t, c.PublicPoint().Equal(configs[c.ID].PublicPoint()), "public key was reused")
+		assert.True(t, c.Paillier.PublicKey.Equal(configs[c.ID].Paillier.PublicKey), "paillier key was not reused")
+		assert.True(t, c.ElGamal.Equal(configs[c.ID].ElGamal), "elgamal key was not reused")
+	}
+}
+
+func TestKeygenWithAuxInfoDeterministic(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N := 3
+	T := 1
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	run := func(seed int64) map[party.ID][]byte {
+		rounds := make([]round.Session, 0, N)
+		for i, partyID := range partyIDs {
+			info := round.Info{
+				ProtocolID:       "cmp/keygen-aux-test",
+				FinalRoundNumber: Rounds,
+				SelfID:           partyID,
+				PartyIDs:         partyIDs,
+				Threshold:        T,
+				Group:            group,
+				Rand:             mrand.New(mrand.NewSource(seed + int64(i))),
+			}
+			r, err := StartWithAuxInfo(info, pl, nil, configs[partyID].AuxInfo())([]byte("session"))
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+		for {
+			err, done := test.Rounds(rounds, nil)
+			require.NoError(t, err, "failed to process round")
+			if done {
+				break
+			}
+		}
+		checkOutput(t, rounds)
+
+		out := make(map[party.ID][]byte, N)
+		for _, r := range rounds {
+			c := r.(*round.Output).Result.(*config.Config)
+			data, err := cbor.Marshal(c)
+			require.NoError(t, err)
+			out[c.ID] = data
+		}
+		return out
+	}
+
+	first := run(1)
+	assert.Equal(t, first, run(1), "same randomness should give the same configs")
+	assert.NotEqual(t, first, run(2), "different randomness should give different configs")
+}
+
+func TestRefreshWithAuxInfo(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N := 4
+	T := N - 1
+	configs, _ := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	rounds := make([]round.Session, 0, N)
+	for _, c := range configs {
+		info := round.Info{
+			ProtocolID:       "cmp/refresh-aux-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           c.ID,
+			PartyIDs:         c.PartyIDs(),
+			Threshold:        T,
+			Group:            group,
+		}
+		r, err := StartWithAuxInfo(info, pl, c, c.AuxInfo())(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	checkOutput(t, rounds)
+
+	for _, r := range rounds {
+		c := r.(*round.Output).Result.(*config.Config)
+		old := configs[c.ID]
+		assert.True(t, c.PublicPoint().Equal(old.PublicPoint()), "public key is different")
+		assert.False(t, c.ECDSA.Equal(old.ECDSA), "share was not refreshed")
+		assert.True(t, c.Paillier.PublicKey.Equal(old.Paillier.PublicKey), "paillier key was not reused")
+	}
+}
+
+func TestStartWithAuxInfoDifferentParties(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	info := round.Info{
+		ProtocolID:       "cmp/keygen-aux-test",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs[:2],
+		Threshold:        1,
+		Group:            group,
+	}
+	_, err := StartWithAuxInfo(info, pl, nil, configs[partyIDs[0]].AuxInfo())(nil)
+	assert.Error(t, err)
+}
+
+func TestImport(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	secret := sample.Scalar(rand.Reader, group)
+	publicKey := secret.ActOnBase()
+
+	// the owner d deals the key to a, b and c without keeping a share.
+	allIDs := test.PartyIDs(4)
+	owner := allIDs[3]
+	receivers := allIDs[:3]
+	threshold := 1
+
+	rounds := make([]round.Session, 0, len(allIDs))
+	for _, id := range allIDs {
+		info := round.Info{
+			ProtocolID:       "cmp/import-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           id,
+			PartyIDs:         allIDs,
+			Threshold:        threshold,
+			Group:            group,
+		}
+		var s curve.Scalar
+		if id == owner {
+			s = secret
+		}
+		r, err := StartImport(info, pl, s, publicKey, owner, receivers)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	configs := make(map[party.ID]*config.Config, len(receivers))
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		result := r.(*round.Output).Result
+		if r.SelfID() == owner {
+			require.Implements(t, (*curve.Point)(nil), result)
+			assert.True(t, publicKey.Equal(result.(curve.Point)), "public key is different")
+			continue
+		}
+		require.IsType(t, &config.Config{}, result)
+		c := result.(*config.Config)
+		assert.Equal(t, threshold, c.Threshold)
+		assert.True(t, publicKey.Equal(c.PublicPoint()), "public key is different")
+		assert.True(t, c.ECDSA.ActOnBase().Equal(c.Public[c.ID].ECDSA), "public share is inconsistent")
+		configs[c.ID] = c
+	}
+
+	signers := receivers[1:]
+	lagrange := polynomial.Lagrange(group, signers)
+	reconstructed := group.NewScalar()
+	for _, j := range signers {
+		reconstructed.Add(group.NewScalar().Set(lagrange[j]).Mul(configs[j].ECDSA))
+	}
+	assert.True(t, reconstructed.Equal(secret), "shares do not reconstruct the imported secret")
+}
+
+func TestStartImportInvalid(t *testing.T) {
+	secret := sample.Scalar(rand.Reader, group)
+	partyIDs := test.PartyIDs(3)
+	info := round.Info{
+		ProtocolID:       "cmp/import-test",
+		FinalRoundNumber: Rounds,
+		SelfID:           partyIDs[0],
+		PartyIDs:         partyIDs,
+		Threshold:        1,
+		Group:            group,
+	}
+
+	// the secret does not match the public key
+	_, err := StartImport(info, nil, secret, sample.Scalar(rand.Reader, group).ActOnBase(), partyIDs[0], partyIDs)(nil)
+	assert.Error(t, err)
+
+	// only the owner knows the secret
+	_, err = StartImport(info, nil, secret, secret.ActOnBase(), partyIDs[1], partyIDs)(nil)
+	assert.Error(t, err)
+}
@@ -1,7 +1,7 @@
 package keygen
 
 import (
-	"crypto/rand"
+	"encoding/binary"
 	"errors"
 
 	"github.com/cronokirby/saferith"
@@ -11,9 +11,11 @@ import (
 	"github.com/w3-key/mps-lean/pkg/math/sample"
 	"github.com/w3-key/mps-lean/pkg/paillier"
 	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pedersen"
 	"github.com/w3-key/mps-lean/pkg/round"
 	"github.com/w3-key/mps-lean/pkg/types"
 	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
 )
 
 var _ round.Round = (*round1)(nil)
@@ -37,11 +39,41 @@ type round1 struct {
 	// In that case, we will simply use the previous chain key at the very end.
 	PreviousChainKey types.RID
 
+	// PreviousLocation is the position of the key in a BIP-32 tree, if we're refreshing or resharing,
+	// which is kept in the new config along with the chain key.
+	PreviousLocation bip32Location
+
 	// VSSSecret = fᵢ(X)
 	// Polynomial from which the new secret shares are computed.
 	// Keygen:  fᵢ(0) = xⁱ
 	// Refresh: fᵢ(0) = 0
+	// Reshare: fᵢ(0) = λᵢ⋅x'ᵢ if dealer, 0 otherwise
+	// Import:  fᵢ(0) = x if owner, 0 otherwise
 	VSSSecret *polynomial.Polynomial
+
+	// Dealers contains the parties holding a share of the key being reshared.
+	// Keygen, Refresh: nil
+	Dealers party.IDSlice
+
+	// Receivers contains the parties obtaining a share of the reshared key.
+	// Keygen, Refresh: nil, all parties obtain a share
+	Receivers party.IDSlice
+
+	// DealerPublicShares[j] = λⱼ⋅X'ⱼ is the expected constant of Fⱼ(X) for each dealer j.
+	// Only set if we hold a share of the key being reshared.
+	DealerPublicShares map[party.ID]curve.Point
+
+	// PublicKey = X is the public key being reshared.
+	PublicKey curve.Point
+
+	// AuxInfo contains the Paillier, Pedersen and ElGamal parameters of all parties, if they are reused.
+	// In that case, no new parameters are generated or proven.
+	AuxInfo *config.AuxInfo
+}
+
+// receives returns true if party j obtains a share of the key at the end of the protocol.
+func (r *round1) receives(j party.ID) bool {
+	return r.Dealers == nil || r.Receivers.Contains(j)
 }
 
 // VerifyMessage implements round.Round.
@@ -52,8 +84,8 @@ func (r *round1) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round
 //
-// - sample Paillier (pᵢ, qᵢ)
-// - sample Pedersen Nᵢ, sᵢ, tᵢ
+// - sample Paillier (pᵢ, qᵢ), unless reusing aux info or not receiving a share
+// - sample Pedersen Nᵢ, sᵢ, tᵢ, unless reusing aux info or not receiving a share
 // - sample aᵢ  <- 𝔽
 // - set Aᵢ = aᵢ⋅G
 // - compute Fᵢ(X) = fᵢ(X)⋅G
@@ -61,12 +93,29 @@ func (r *round1) StoreMessage(round.Message) error { return nil }
 // - sample cᵢ <- {0,1}ᵏ
 // - commit to message.
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
-	// generate Paillier and Pedersen
-	PaillierSecret := paillier.NewSecretKey(nil)
-	SelfPaillierPublic := PaillierSecret.PublicKey
-	SelfPedersenPublic, PedersenSecret := PaillierSecret.GeneratePedersen()
-
-	ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(rand.Reader, r.Group())
+	var (
+		PaillierSecret     *paillier.SecretKey
+		SelfPedersenPublic *pedersen.Parameters
+		PedersenSecret     *saferith.Nat
+		ElGamalSecret      curve.Scalar
+		ElGamalPublic      curve.Point
+	)
+	switch {
+	case !r.receives(r.SelfID()):
+		// a retiring dealer never uses Paillier or Pedersen parameters, so we skip generating and proving them.
+		ElGamalSecret, ElGamalPublic = sample.ScalarPointPair(r.Rand(), r.Group())
+	case r.AuxInfo != nil:
+		PaillierSecret = r.AuxInfo.Paillier
+		SelfPedersenPublic = r.AuxInfo.Public[r.SelfID()].Pedersen
+		ElGamalSecret = r.AuxInfo.ElGamal
+		ElGamalPublic = r.AuxInfo.Public[r.SelfID()].ElGamal
+	default:
+		// generate Paillier and Pedersen
+		PaillierSecret = paillier.NewSecretKey(r.Rand(), nil)
+		SelfPedersenPublic, PedersenSecret = PaillierSecret.GeneratePedersen(r.Rand())
+
+		ElGamalSecret, ElGamalPublic = sample.ScalarPointPair(r.Rand(), r.Group())
+	}
 
 	// save our own share already so we are consistent with what we receive from others
 	SelfShare := r.VSSSecret.Evaluate(r.SelfID().Scalar(r.Group()))
@@ -75,23 +124,29 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	SelfVSSPolynomial := polynomial.NewPolynomialExponent(r.VSSSecret)
 
 	// generate Schnorr randomness
-	SchnorrRand := zksch.NewRandomness(rand.Reader, r.Group(), nil)
+	SchnorrRand := zksch.NewRandomness(r.Rand(), r.Group(), nil)
 
 	// Sample RIDᵢ
-	SelfRID, err := types.NewRID(rand.Reader)
+	SelfRID, err := types.NewRID(r.Rand())
 	if err != nil {
 		return r, errors.New("failed to sample Rho")
 	}
-	chainKey, err := types.NewRID(rand.Reader)
+	chainKey, err := types.NewRID(r.Rand())
 	if err != nil {
 		return r, errors.New("failed to sample c")
 	}
+	// when resharing, dealers keep the existing chain key
+	if r.Dealers.Contains(r.SelfID()) {
+		chainKey = r.PreviousChainKey.Copy()
+	}
 
 	// 各节点进行签名
 	// commit to data in message 2
-	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(
-		SelfRID, chainKey, SelfVSSPolynomial, SchnorrRand.Commitment(), ElGamalPublic,
-		SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T())
+	committed := []interface{}{SelfRID, chainKey, r.PreviousLocation, SelfVSSPolynomial, SchnorrRand.Commitment(), ElGamalPublic}
+	if SelfPedersenPublic != nil {
+		committed = append(committed, SelfPedersenPublic.N(), SelfPedersenPublic.S(), SelfPedersenPublic.T())
+	}
+	SelfCommitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(r.Rand(), committed...)
 	if err != nil {
 		return r, errors.New("failed to commit")
 	}
@@ -103,18 +158,28 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 		return r, err
 	}
 
+	PaillierPublic := map[party.ID]*paillier.PublicKey{}
+	NModulus := map[party.ID]*saferith.Modulus{}
+	S, T := map[party.ID]*saferith.Nat{}, map[party.ID]*saferith.Nat{}
+	if SelfPedersenPublic != nil {
+		PaillierPublic[r.SelfID()] = PaillierSecret.PublicKey
+		NModulus[r.SelfID()] = SelfPedersenPublic.N()
+		S[r.SelfID()], T[r.SelfID()] = SelfPedersenPublic.S(), SelfPedersenPublic.T()
+	}
+
 	nextRound := &round2{
 		round1:         r,
 		VSSPolynomials: map[party.ID]*polynomial.Exponent{r.SelfID(): SelfVSSPolynomial},
 		Commitments:    map[party.ID]hash.Commitment{r.SelfID(): SelfCommitment},
 		RIDs:           map[party.ID]types.RID{r.SelfID(): SelfRID},
 		ChainKeys:      map[party.ID]types.RID{r.SelfID(): chainKey},
+		Locations:      map[party.ID]bip32Location{r.SelfID(): r.PreviousLocation},
 		ShareReceived:  map[party.ID]curve.Scalar{r.SelfID(): SelfShare},
 		ElGamalPublic:  map[party.ID]curve.Point{r.SelfID(): ElGamalPublic},
-		PaillierPublic: map[party.ID]*paillier.PublicKey{r.SelfID(): SelfPaillierPublic},
-		NModulus:       map[party.ID]*saferith.Modulus{r.SelfID(): SelfPedersenPublic.N()},
-		S:              map[party.ID]*saferith.Nat{r.SelfID(): SelfPedersenPublic.S()},
-		T:              map[party.ID]*saferith.Nat{r.SelfID(): SelfPedersenPublic.T()},
+		PaillierPublic: PaillierPublic,
+		NModulus:       NModulus,
+		S:              S,
+		T:              T,
 		ElGamalSecret:  ElGamalSecret,
 		PaillierSecret: PaillierSecret,
 		PedersenSecret: PedersenSecret,
@@ -124,6 +189,38 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	return nextRound, nil
 }
 
+// bip32Location locates a public key in a BIP-32 tree, as described by the fields of config.Config with the same names.
+type bip32Location struct {
+	Depth             uint8
+	ParentFingerprint uint32
+	ChildNumber       uint32
+}
+
+// locationOf returns the BIP-32 location of the key of c.
+func locationOf(c *config.Config) bip32Location {
+	return bip32Location{Depth: c.Depth, ParentFingerprint: c.ParentFingerprint, ChildNumber: c.ChildNumber}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, so that the location can be committed to.
+func (l bip32Location) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 9)
+	data[0] = l.Depth
+	binary.BigEndian.PutUint32(data[1:5], l.ParentFingerprint)
+	binary.BigEndian.PutUint32(data[5:9], l.ChildNumber)
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (l *bip32Location) UnmarshalBinary(data []byte) error {
+	if len(data) != 9 {
+		return errors.New("bip32Location: invalid length")
+	}
+	l.Depth = data[0]
+	l.ParentFingerprint = binary.BigEndian.Uint32(data[1:5])
+	l.ChildNumber = binary.BigEndian.Uint32(data[5:9])
+	return nil
+}
+
 // PreviousRound implements round.Round.
 func (round1) PreviousRound() round.Round { return nil }
 
@@ -27,6 +27,8 @@ type round2 struct {
 	RIDs map[party.ID]types.RID
 	// ChainKeys[j] = cⱼ
 	ChainKeys map[party.ID]types.RID
+	// Locations[j] is the BIP-32 location of the key sent by j, only used when resharing.
+	Locations map[party.ID]bip32Location
 
 	// ShareReceived[j] = xʲᵢ
 	// share received from party j
@@ -92,6 +94,7 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 	err := r.BroadcastMessage(out, &broadcast3{
 		RID:                r.RIDs[r.SelfID()],
 		C:                  r.ChainKeys[r.SelfID()],
+		Location:           r.Locations[r.SelfID()],
 		VSSPolynomial:      r.VSSPolynomials[r.SelfID()],
 		SchnorrCommitments: r.SchnorrRand.Commitment(),
 		ElGamalPublic:      r.ElGamalPublic[r.SelfID()],
@@ -1,6 +1,7 @@
 package keygen
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -32,6 +33,8 @@ type broadcast3 struct {
 	// RID = RIDᵢ
 	RID types.RID
 	C   types.RID
+	// Location is the BIP-32 location of the key being refreshed or reshared, and is empty for a new key.
+	Location bip32Location
 	// VSSPolynomial = Fᵢ(X) VSSPolynomial
 	VSSPolynomial *polynomial.Exponent
 	// SchnorrCommitments = Aᵢ Schnorr commitment for the final confirmation
@@ -53,8 +56,11 @@ type broadcast3 struct {
 // - verify degree of VSS polynomial Fⱼ "in-the-exponent"
 //   - if keygen, verify Fⱼ(0) != ∞
 //   - if refresh, verify Fⱼ(0) == ∞
+//   - if reshare, verify Fⱼ(0) == λⱼ⋅X'ⱼ, cⱼ = c' and the BIP-32 location for dealers, and Fⱼ(0) == ∞ for the others
 // - validate Paillier
 // - validate Pedersen
+//   - if reusing aux info, verify that Nⱼ, sⱼ, tⱼ, Yⱼ are the ones in the aux info instead
+//   - if j does not receive a share, verify that it sent no Nⱼ, sⱼ, tⱼ
 // - validate commitments.
 // - store ridⱼ, Cⱼ, Nⱼ, Sⱼ, Tⱼ, Fⱼ(X), Aⱼ.
 func (r *round3) StoreBroadcastMessage(msg round.Message) error {
@@ -65,9 +71,16 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	}
 
 	// check nil
-	if body.N == nil || body.S == nil || body.T == nil || body.VSSPolynomial == nil {
+	if body.VSSPolynomial == nil {
 		return round.ErrNilFields
 	}
+	receives := r.receives(from)
+	if receives && (body.N == nil || body.S == nil || body.T == nil) {
+		return round.ErrNilFields
+	}
+	if !receives && (body.N != nil || body.S != nil || body.T != nil) {
+		return errors.New("party without a share sent Paillier or Pedersen parameters")
+	}
 	// check RID length
 	if err := body.RID.Validate(); err != nil {
 		return fmt.Errorf("rid: %w", err)
@@ -82,36 +95,77 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 
 	// Save all X, VSSCommitments
 	VSSPolynomial := body.VSSPolynomial
-	// check that the constant coefficient is 0
-	// if refresh then the polynomial is constant
-	if !(r.VSSSecret.Constant().IsZero() == VSSPolynomial.IsConstant) {
-		return errors.New("vss polynomial has incorrect constant")
+	switch {
+	case r.Dealers == nil:
+		// check that the constant coefficient is 0
+		// if refresh then the polynomial is constant
+		if !(r.VSSSecret.Constant().IsZero() == VSSPolynomial.IsConstant) {
+			return errors.New("vss polynomial has incorrect constant")
+		}
+	case r.Dealers.Contains(from):
+		// a dealer shares λⱼ⋅x'ⱼ, which we can only check if we know X'ⱼ
+		if VSSPolynomial.IsConstant {
+			return errors.New("vss polynomial has incorrect constant")
+		}
+		if r.DealerPublicShares != nil && !VSSPolynomial.Constant().Equal(r.DealerPublicShares[from]) {
+			return errors.New("vss polynomial has incorrect constant")
+		}
+		if r.PreviousChainKey != nil && !bytes.Equal(body.C, r.PreviousChainKey) {
+			return errors.New("dealer sent incorrect chain key")
+		}
+		if r.PreviousChainKey != nil && body.Location != r.PreviousLocation {
+			return errors.New("dealer sent incorrect BIP-32 location")
+		}
+	default:
+		if !VSSPolynomial.IsConstant {
+			return errors.New("vss polynomial has incorrect constant")
+		}
 	}
 	// check deg(Fⱼ) = t
 	if VSSPolynomial.Degree() != r.Threshold() {
 		return errors.New("vss polynomial has incorrect degree")
 	}
 
-	// Set Paillier
-	if err := paillier.ValidateN(body.N); err != nil {
-		return err
-	}
+	switch {
+	case !receives:
+	case r.AuxInfo != nil:
+		// the parameters were already validated by the aux info protocol
+		public := r.AuxInfo.Public[from]
+		if body.N.Nat().Eq(public.Pedersen.N().Nat()) != 1 ||
+			body.S.Eq(public.Pedersen.S()) != 1 || body.T.Eq(public.Pedersen.T()) != 1 {
+			return errors.New("Paillier or Pedersen parameters differ from aux info")
+		}
+		if !body.ElGamalPublic.Equal(public.ElGamal) {
+			return errors.New("ElGamal public key differs from aux info")
+		}
+	default:
+		// Set Paillier
+		if err := paillier.ValidateN(body.N); err != nil {
+			return err
+		}
 
-	// Verify Pedersen
-	if err := pedersen.ValidateParameters(body.N, body.S, body.T); err != nil {
-		return err
+		// Verify Pedersen
+		if err := pedersen.ValidateParameters(body.N, body.S, body.T); err != nil {
+			return err
+		}
 	}
 	// Verify decommit
-	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment,
-		body.RID, body.C, VSSPolynomial, body.SchnorrCommitments, body.ElGamalPublic, body.N, body.S, body.T) {
+	committed := []interface{}{body.RID, body.C, body.Location, VSSPolynomial, body.SchnorrCommitments, body.ElGamalPublic}
+	if receives {
+		committed = append(committed, body.N, body.S, body.T)
+	}
+	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment, committed...) {
 		return errors.New("failed to decommit")
 	}
 	r.RIDs[from] = body.RID
 	r.ChainKeys[from] = body.C
-	r.NModulus[from] = body.N
-	r.S[from] = body.S
-	r.T[from] = body.T
-	r.PaillierPublic[from] = paillier.NewPublicKey(body.N)
+	r.Locations[from] = body.Location
+	if receives {
+		r.NModulus[from] = body.N
+		r.S[from] = body.S
+		r.T[from] = body.T
+		r.PaillierPublic[from] = paillier.NewPublicKey(body.N)
+	}
 	r.VSSPolynomials[from] = body.VSSPolynomial
 	r.SchnorrCommitments[from] = body.SchnorrCommitments
 	r.ElGamalPublic[from] = body.ElGamalPublic
@@ -128,16 +182,35 @@ func (round3) StoreMessage(round.Message) error { return nil }
 // Finalize implements round.Round
 //
 // - set rid = ⊕ⱼ ridⱼ and update hash state
-// - prove Nᵢ is Blum
-// - prove Pedersen parameters
+// - if reshare, verify ∑ⱼ Fⱼ(0) = X and that all dealers agree on the chain key
+// - prove Nᵢ is Blum, unless reusing aux info or not receiving a share
+// - prove Pedersen parameters, unless reusing aux info or not receiving a share
 // - prove Schnorr for all coefficients of fᵢ(X)
 //   - if refresh skip constant coefficient
 //
-// - send proofs and encryption of share for Pⱼ.
+// - send proofs and encryption of share for Pⱼ, or an empty message if Pⱼ does not receive a share.
 func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// c = ⊕ⱼ cⱼ
 	chainKey := r.PreviousChainKey
-	if chainKey == nil {
+	location := r.PreviousLocation
+	if r.Dealers != nil {
+		// the dealers' chain key and BIP-32 location are kept
+		chainKey = r.ChainKeys[r.Dealers[0]]
+		location = r.Locations[r.Dealers[0]]
+		PublicKey := r.Group().NewPoint()
+		for _, j := range r.Dealers {
+			if !bytes.Equal(r.ChainKeys[j], chainKey) {
+				return r.AbortRound(errors.New("dealers sent different chain keys")), nil
+			}
+			if r.Locations[j] != location {
+				return r.AbortRound(errors.New("dealers sent different BIP-32 locations")), nil
+			}
+			PublicKey = PublicKey.Add(r.VSSPolynomials[j].Constant())
+		}
+		if !PublicKey.Equal(r.PublicKey) {
+			return r.AbortRound(errors.New("dealers shared a different public key")), nil
+		}
+	} else if chainKey == nil {
 		chainKey = types.EmptyRID()
 		for _, j := range r.PartyIDs() {
 			chainKey.XOR(r.ChainKeys[j])
@@ -149,38 +222,44 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		rid.XOR(r.RIDs[j])
 	}
 
-	// temporary hash which does not modify the state
-	h := r.Hash()
-	_ = h.WriteAny(rid, r.SelfID())
-
-	// Prove N is a blum prime with zkmod
-	mod := zkmod.NewProof(h.Clone(), zkmod.Private{
-		P:   r.PaillierSecret.P(),
-		Q:   r.PaillierSecret.Q(),
-		Phi: r.PaillierSecret.Phi(),
-	}, zkmod.Public{N: r.NModulus[r.SelfID()]}, r.Pool)
-
-	// prove s, t are correct as aux parameters with zkprm
-	prm := zkprm.NewProof(zkprm.Private{
-		Lambda: r.PedersenSecret,
-		Phi:    r.PaillierSecret.Phi(),
-		P:      r.PaillierSecret.P(),
-		Q:      r.PaillierSecret.Q(),
-	}, h.Clone(), zkprm.Public{N: r.NModulus[r.SelfID()], S: r.S[r.SelfID()], T: r.T[r.SelfID()]}, r.Pool)
-
-	if err := r.BroadcastMessage(out, &broadcast4{
-		Mod: mod,
-		Prm: prm,
-	}); err != nil {
+	msg := &broadcast4{}
+	if r.AuxInfo == nil && r.receives(r.SelfID()) {
+		// temporary hash which does not modify the state
+		h := r.Hash()
+		_ = h.WriteAny(rid, r.SelfID())
+
+		// Prove N is a blum prime with zkmod
+		msg.Mod = zkmod.NewProof(r.Rand(), h.Clone(), zkmod.Private{
+			P:   r.PaillierSecret.P(),
+			Q:   r.PaillierSecret.Q(),
+			Phi: r.PaillierSecret.Phi(),
+		}, zkmod.Public{N: r.NModulus[r.SelfID()]}, r.Pool)
+
+		// prove s, t are correct as aux parameters with zkprm
+		msg.Prm = zkprm.NewProof(r.Rand(), zkprm.Private{
+			Lambda: r.PedersenSecret,
+			Phi:    r.PaillierSecret.Phi(),
+			P:      r.PaillierSecret.P(),
+			Q:      r.PaillierSecret.Q(),
+		}, h.Clone(), zkprm.Public{N: r.NModulus[r.SelfID()], S: r.S[r.SelfID()], T: r.T[r.SelfID()]}, r.Pool)
+	}
+
+	if err := r.BroadcastMessage(out, msg); err != nil {
 		return r, err
 	}
 
 	// create messages with encrypted shares
 	for _, j := range r.OtherPartyIDs() {
+		if !r.receives(j) {
+			if err := r.SendMessage(out, &message4{}, j); err != nil {
+				return r, err
+			}
+			continue
+		}
 		// compute fᵢ(j)
 		share := r.VSSSecret.Evaluate(j.Scalar(r.Group()))
 		// Encrypt share
-		C, _ := r.PaillierPublic[j].Enc(curve.MakeInt(share))
+		C, _ := r.PaillierPublic[j].Enc(r.Rand(), curve.MakeInt(share))
 
 		err := r.SendMessage(out, &message4{
 			Share: C,
@@ -196,6 +275,7 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		round3:   r,
 		RID:      rid,
 		ChainKey: chainKey,
+		Location: location,
 	}, nil
 }
 
@@ -25,6 +25,8 @@ type round4 struct {
 	RID types.RID
 	// ChainKey is a sequence of random bytes agreed upon together
 	ChainKey types.RID
+	// Location is the BIP-32 location of the key, which refresh and reshare keep
+	Location bip32Location
 }
 
 type message4 struct {
@@ -40,7 +42,7 @@ type broadcast4 struct {
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - verify Mod, Prm proof for N
+// - verify Mod, Prm proof for N, unless reusing aux info or j does not receive a share
 func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 	from := msg.From
 	body, ok := msg.Content.(*broadcast4)
@@ -48,6 +50,10 @@ func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
+	if r.AuxInfo != nil || !r.receives(from) {
+		return nil
+	}
+
 	// verify zkmod
 	if !body.Mod.Verify(zkmod.Public{N: r.NModulus[from]}, r.HashForID(from), r.Pool) {
 		return errors.New("failed to validate mod proof")
@@ -62,13 +68,17 @@ func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 
 // VerifyMessage implements round.Round.
 //
-// - verify validity of share ciphertext.
+// - verify validity of share ciphertext, unless we do not receive a share.
 func (r *round4) VerifyMessage(msg round.Message) error {
 	body, ok := msg.Content.(*message4)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
 
+	if !r.receives(msg.To) {
+		return nil
+	}
+
 	if !r.PaillierPublic[msg.To].ValidateCiphertexts(body.Share) {
 		return errors.New("invalid ciphertext")
 	}
@@ -85,6 +95,10 @@ func (r *round4) VerifyMessage(msg round.Message) error {
 func (r *round4) StoreMessage(msg round.Message) error {
 	from, body := msg.From, msg.Content.(*message4)
 
+	if !r.receives(r.SelfID()) {
+		return nil
+	}
+
 	// decrypt share
 	DecryptedShare, err := r.PaillierSecret.Dec(body.Share)
 	if err != nil {
@@ -110,7 +124,7 @@ func (r *round4) StoreMessage(msg round.Message) error {
 // Finalize implements round.Round
 //
 // - sum of all received shares
-// - compute group public key and individual public keys
+// - compute group public key and individual public keys of the parties receiving a share
 // - recompute config SSID
 // - validate Config
 // - write new ssid hash to old hash state
@@ -121,8 +135,10 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	if r.PreviousSecretECDSA != nil {
 		UpdatedSecretECDSA.Set(r.PreviousSecretECDSA)
 	}
-	for _, j := range r.PartyIDs() {
-		UpdatedSecretECDSA.Add(r.ShareReceived[j])
+	if r.receives(r.SelfID()) {
+		for _, j := range r.PartyIDs() {
+			UpdatedSecretECDSA.Add(r.ShareReceived[j])
+		}
 	}
 
 	// [F₁(X), …, Fₙ(X)]
@@ -132,15 +148,32 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	// ShamirPublicPolynomial = F(X) = ∑Fⱼ(X)
-	ShamirPublicPolynomial, err := polynomial.Sum(ShamirPublicPolynomials)
-	if err != nil {
-		return r, err
+	// When resharing, only the dealers' polynomials have a non-zero constant,
+	// so we evaluate each Fⱼ(X) separately instead.
+	var ShamirPublicPolynomial *polynomial.Exponent
+	if r.Dealers == nil {
+		var err error
+		ShamirPublicPolynomial, err = polynomial.Sum(ShamirPublicPolynomials)
+		if err != nil {
+			return r, err
+		}
 	}
 
 	// compute the new public key share Xⱼ = F(j) (+X'ⱼ if doing a refresh)
 	PublicData := make(map[party.ID]*config.Public, len(r.PartyIDs()))
 	for _, j := range r.PartyIDs() {
-		PublicECDSAShare := ShamirPublicPolynomial.Evaluate(j.Scalar(r.Group()))
+		if !r.receives(j) {
+			continue
+		}
+		var PublicECDSAShare curve.Point
+		if ShamirPublicPolynomial != nil {
+			PublicECDSAShare = ShamirPublicPolynomial.Evaluate(j.Scalar(r.Group()))
+		} else {
+			PublicECDSAShare = r.Group().NewPoint()
+			for _, VSSPolynomial := range ShamirPublicPolynomials {
+				PublicECDSAShare = PublicECDSAShare.Add(VSSPolynomial.Evaluate(j.Scalar(r.Group())))
+			}
+		}
 		if r.PreviousPublicSharesECDSA != nil {
 			PublicECDSAShare = PublicECDSAShare.Add(r.PreviousPublicSharesECDSA[j])
 		}
@@ -156,23 +189,32 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 		Group:     r.Group(),
 		ID:        r.SelfID(),
 		Threshold: r.Threshold(),
-		ECDSA:     UpdatedSecretECDSA,
-		ElGamal:   r.ElGamalSecret,
-		Paillier:  r.PaillierSecret,
 		RID:       r.RID.Copy(),
 		ChainKey:  r.ChainKey.Copy(),
 		Public:    PublicData,
+
+		Depth:             r.Location.Depth,
+		ParentFingerprint: r.Location.ParentFingerprint,
+		ChildNumber:       r.Location.ChildNumber,
 	}
 
-	// write new ssid to hash, to bind the Schnorr proof to this new config
-	// Write SSID, selfID to temporary hash
-	h := r.Hash()
-	_ = h.WriteAny(UpdatedConfig, r.SelfID())
+	// if we are not part of the new sharing, there is no share to prove knowledge of
+	msg := &broadcast5{}
+	if r.receives(r.SelfID()) {
+		UpdatedConfig.ECDSA = UpdatedSecretECDSA
+		UpdatedConfig.ElGamal = r.ElGamalSecret
+		UpdatedConfig.Paillier = r.PaillierSecret
 
-	proof := r.SchnorrRand.Prove(h, PublicData[r.SelfID()].ECDSA, UpdatedSecretECDSA, nil)
+		// write new ssid to hash, to bind the Schnorr proof to this new config
+		// Write SSID, selfID to temporary hash
+		h := r.Hash()
+		_ = h.WriteAny(UpdatedConfig, r.SelfID())
+
+		msg.SchnorrResponse = r.SchnorrRand.Prove(h, PublicData[r.SelfID()].ECDSA, UpdatedSecretECDSA, nil)
+	}
 
 	// send to all
-	err = r.BroadcastMessage(out, &broadcast5{SchnorrResponse: proof})
+	err := r.BroadcastMessage(out, msg)
 	if err != nil {
 		return r, err
 	}
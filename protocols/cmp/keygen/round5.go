@@ -23,7 +23,7 @@ type broadcast5 struct {
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - verify all Schnorr proof for the new ecdsa share.
+// - verify all Schnorr proof for the new ecdsa share, for the parties receiving a share.
 func (r *round5) StoreBroadcastMessage(msg round.Message) error {
 	from := msg.From
 	body, ok := msg.Content.(*broadcast5)
@@ -31,6 +31,10 @@ func (r *round5) StoreBroadcastMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
+	if !r.receives(from) {
+		return nil
+	}
+
 	if !body.SchnorrResponse.IsValid() {
 		return round.ErrNilFields
 	}
@@ -50,7 +54,12 @@ func (round5) VerifyMessage(round.Message) error { return nil }
 func (r *round5) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round.
+//
+// When resharing, a dealer which does not receive a share of the key only obtains its public key.
 func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
+	if !r.receives(r.SelfID()) {
+		return r.ResultRound(r.PublicKey), nil
+	}
 	return r.ResultRound(r.UpdatedConfig), nil
 }
 
@@ -0,0 +1,151 @@
+package presign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/elgamal"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pedersen"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+	"github.com/w3-key/mps-lean/protocols/cmp/sign"
+)
+
+const (
+	// Identifier for the presign protocol without a message.
+	protocolOfflineID = "cmp/presign-offline"
+	// Identifier for the single round signing protocol using a PreSignature.
+	protocolOnlineID = "cmp/presign-online"
+	// Identifier for the presign protocol followed by the online round.
+	protocolFullID = "cmp/presign-full"
+
+	// protocolOfflineRounds is the number of rounds required to produce a PreSignature.
+	protocolOfflineRounds round.Number = 5
+	// protocolFullRounds is the number of rounds required to produce a signature,
+	// including the online round.
+	protocolFullRounds round.Number = 6
+)
+
+// StartPresign returns a protocol.StartFunc for the presign protocol.
+//
+// If message is empty, the protocol returns an *ecdsa.PreSignature which can later be used with StartPresignOnline.
+// Otherwise, the online round is executed directly after, and an *ecdsa.Signature is returned.
+func StartPresign(c *config.Config, signers []party.ID, message []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	if len(message) == 0 {
+		message = nil
+	}
+	return func(sessionID []byte) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       protocolOfflineID,
+			FinalRoundNumber: protocolOfflineRounds,
+			SelfID:           c.ID,
+			PartyIDs:         signers,
+			Threshold:        c.Threshold,
+			Group:            c.Group,
+		}
+		info.Apply(opts...)
+		if message != nil {
+			info.ProtocolID = protocolFullID
+			info.FinalRoundNumber = protocolFullRounds
+		}
+
+		helper, err := round.NewSession(info, sessionID, pl, c, types.SigningMessage(message))
+		if err != nil {
+			return nil, fmt.Errorf("presign: %w", err)
+		}
+
+		if !c.CanSign(helper.PartyIDs()) {
+			return nil, errors.New("presign: signers is not a valid signing subset")
+		}
+
+		// Scale public data
+		T := helper.N()
+		group := c.Group
+		ECDSA := make(map[party.ID]curve.Point, T)
+		ElGamal := make(map[party.ID]elgamal.PublicKey, T)
+		Paillier := make(map[party.ID]*paillier.PublicKey, T)
+		Pedersen := make(map[party.ID]*pedersen.Parameters, T)
+		PublicKey := group.NewPoint()
+		lagrange := polynomial.Lagrange(group, signers)
+		// Scale own secret
+		SecretECDSA := group.NewScalar().Set(lagrange[c.ID]).Mul(c.ECDSA)
+		for _, j := range helper.PartyIDs() {
+			public := c.Public[j]
+			// scale public key share
+			ECDSA[j] = lagrange[j].Act(public.ECDSA)
+			ElGamal[j] = public.ElGamal
+			Paillier[j] = public.Paillier
+			Pedersen[j] = public.Pedersen
+			PublicKey = PublicKey.Add(ECDSA[j])
+		}
+
+		return &presign1{
+			Helper:         helper,
+			SecretECDSA:    SecretECDSA,
+			SecretElGamal:  c.ElGamal,
+			SecretPaillier: c.Paillier,
+			PublicKey:      PublicKey,
+			ECDSA:          ECDSA,
+			ElGamal:        ElGamal,
+			Paillier:       Paillier,
+			Pedersen:       Pedersen,
+			Message:        message,
+		}, nil
+	}
+}
+
+// StartPresignOnline returns a protocol.StartFunc for the online signing protocol,
+// which uses the PreSignature with the given id, obtained from StartPresign, to sign message in a single round.
+//
+// The PreSignature is taken from store when the session starts, so that it is used for at most one message,
+// even if the session later fails. The same signers that generated the PreSignature must participate.
+func StartPresignOnline(c *config.Config, store sign.PreSignatureStore, id types.RID, message []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if len(message) == 0 {
+			return nil, errors.New("presign: message is nil")
+		}
+
+		preSignature, err := store.Take(id)
+		if err != nil {
+			return nil, fmt.Errorf("presign: %w", err)
+		}
+
+		if err = preSignature.Validate(); err != nil {
+			return nil, fmt.Errorf("presign: %w", err)
+		}
+
+		signers := preSignature.SignerIDs()
+		if !c.CanSign(signers) {
+			return nil, errors.New("presign: signers is not a valid signing subset")
+		}
+
+		info := round.Info{
+			ProtocolID:       protocolOnlineID,
+			FinalRoundNumber: protocolFullRounds,
+			SelfID:           c.ID,
+			PartyIDs:         signers,
+			Threshold:        c.Threshold,
+			Group:            c.Group,
+		}
+		info.Apply(opts...)
+
+		helper, err := round.NewSession(info, sessionID, pl, c, preSignature.ID, types.SigningMessage(message))
+		if err != nil {
+			return nil, fmt.Errorf("presign: %w", err)
+		}
+
+		return &sign1{
+			Helper:       helper,
+			PublicKey:    c.PublicPoint(),
+			Message:      message,
+			PreSignature: preSignature,
+		}, nil
+	}
+}
@@ -0,0 +1,135 @@
+package presign
+
+import (
+	"github.com/w3-key/mps-lean/pkg/elgamal"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pedersen"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	zkencelg "github.com/w3-key/mps-lean/pkg/zk/encelg"
+)
+
+var _ round.Round = (*presign1)(nil)
+
+type presign1 struct {
+	*round.Helper
+
+	// SecretECDSA = xᵢ
+	SecretECDSA curve.Scalar
+	// SecretElGamal = yᵢ
+	SecretElGamal curve.Scalar
+	// SecretPaillier = (pᵢ, qᵢ)
+	SecretPaillier *paillier.SecretKey
+
+	// PublicKey = X
+	PublicKey curve.Point
+	// ECDSA[j] = Xⱼ
+	ECDSA map[party.ID]curve.Point
+	// ElGamal[j] = Yⱼ
+	ElGamal map[party.ID]elgamal.PublicKey
+	// Paillier[j] = Nⱼ
+	Paillier map[party.ID]*paillier.PublicKey
+	// Pedersen[j] = (Nⱼ,Sⱼ,Tⱼ)
+	Pedersen map[party.ID]*pedersen.Parameters
+
+	// Message is the message to be signed. If it is nil, a presignature is created.
+	Message []byte
+}
+
+// VerifyMessage implements round.Round.
+func (presign1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (presign1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - sample kᵢ, γᵢ <- 𝔽,
+// - Kᵢ = Encᵢ(kᵢ;ρᵢ)
+// - Gᵢ = Encᵢ(γᵢ;νᵢ)
+// - Zᵢ = (bᵢ⋅G, kᵢ⋅G+bᵢ⋅Yᵢ)
+// - sample ridᵢ <- {0,1}ᵏ and commit to it.
+// - prove zkencelg(Kᵢ, Zᵢ) to all other parties.
+func (r *presign1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// kᵢ <- 𝔽,
+	KShare := sample.Scalar(r.Rand(), r.Group())
+	KShareInt := curve.MakeInt(KShare)
+	// Kᵢ = Encᵢ(kᵢ;ρᵢ)
+	K, KNonce := r.Paillier[r.SelfID()].Enc(r.Rand(), KShareInt)
+
+	// γᵢ <- 𝔽,
+	GammaShare := sample.Scalar(r.Rand(), r.Group())
+	// Gᵢ = Encᵢ(γᵢ;νᵢ)
+	G, GNonce := r.Paillier[r.SelfID()].Enc(r.Rand(), curve.MakeInt(GammaShare))
+
+	// Zᵢ = (bᵢ⋅G, kᵢ⋅G+bᵢ⋅Yᵢ)
+	ElGamalK, ElGamalKNonce := elgamal.Encrypt(r.Rand(), r.ElGamal[r.SelfID()], KShare)
+
+	PresignatureID, err := types.NewRID(r.Rand())
+	if err != nil {
+		return r, err
+	}
+	CommitmentID, DecommitmentID, err := r.HashForID(r.SelfID()).Commit(r.Rand(), PresignatureID)
+	if err != nil {
+		return r, err
+	}
+
+	if err = r.BroadcastMessage(out, &broadcast2{
+		K:            K,
+		G:            G,
+		Z:            ElGamalK,
+		CommitmentID: CommitmentID,
+	}); err != nil {
+		return r, err
+	}
+
+	otherIDs := r.OtherPartyIDs()
+	readers := sample.Fork(r.Rand(), len(otherIDs))
+	errs := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
+		j := otherIDs[i]
+		proof := zkencelg.NewProof(readers[i], r.Group(), r.HashForID(r.SelfID()), zkencelg.Public{
+			C:      K,
+			A:      r.ElGamal[r.SelfID()],
+			B:      ElGamalK.L,
+			X:      ElGamalK.M,
+			Prover: r.Paillier[r.SelfID()],
+			Aux:    r.Pedersen[j],
+		}, zkencelg.Private{
+			X:   KShareInt,
+			Rho: KNonce,
+			A:   r.SecretElGamal,
+			B:   ElGamalKNonce,
+		})
+		return r.SendMessage(out, &message2{Proof: proof}, j)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return r, err.(error)
+		}
+	}
+
+	return &presign2{
+		presign1:       r,
+		K:              map[party.ID]*paillier.Ciphertext{r.SelfID(): K},
+		G:              map[party.ID]*paillier.Ciphertext{r.SelfID(): G},
+		ElGamalK:       map[party.ID]*elgamal.Ciphertext{r.SelfID(): ElGamalK},
+		CommitmentID:   map[party.ID]hash.Commitment{r.SelfID(): CommitmentID},
+		GammaShare:     GammaShare,
+		KShare:         KShare,
+		KNonce:         KNonce,
+		GNonce:         GNonce,
+		ElGamalKNonce:  ElGamalKNonce,
+		PresignatureID: PresignatureID,
+		DecommitmentID: DecommitmentID,
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (presign1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (presign1) Number() round.Number { return 1 }
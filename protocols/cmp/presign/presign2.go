@@ -0,0 +1,232 @@
+package presign
+
+import (
+	"errors"
+
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/elgamal"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/mta"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	zkencelg "github.com/w3-key/mps-lean/pkg/zk/encelg"
+	zklogstar "github.com/w3-key/mps-lean/pkg/zk/logstar"
+)
+
+var _ round.Round = (*presign2)(nil)
+
+type presign2 struct {
+	*presign1
+
+	// K[j] = Kⱼ = encⱼ(kⱼ)
+	K map[party.ID]*paillier.Ciphertext
+	// G[j] = Gⱼ = encⱼ(γⱼ)
+	G map[party.ID]*paillier.Ciphertext
+	// ElGamalK[j] = Zⱼ
+	ElGamalK map[party.ID]*elgamal.Ciphertext
+	// CommitmentID[j] = Com(ridⱼ)
+	CommitmentID map[party.ID]hash.Commitment
+
+	// GammaShare = γᵢ
+	GammaShare curve.Scalar
+	// KShare = kᵢ
+	KShare curve.Scalar
+
+	// KNonce = ρᵢ
+	KNonce *saferith.Nat
+	// GNonce = νᵢ
+	GNonce *saferith.Nat
+	// ElGamalKNonce = bᵢ
+	ElGamalKNonce elgamal.Nonce
+
+	// PresignatureID = ridᵢ
+	PresignatureID types.RID
+	// DecommitmentID is the decommitment string for ridᵢ
+	DecommitmentID hash.Decommitment
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// K = Kᵢ
+	K *paillier.Ciphertext
+	// G = Gᵢ
+	G *paillier.Ciphertext
+	// Z = Zᵢ
+	Z *elgamal.Ciphertext
+	// CommitmentID = Com(ridᵢ)
+	CommitmentID hash.Commitment
+}
+
+type message2 struct {
+	Proof *zkencelg.Proof
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - store Kⱼ, Gⱼ, Zⱼ, Com(ridⱼ).
+func (r *presign2) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if !r.Paillier[from].ValidateCiphertexts(body.K, body.G) {
+		return errors.New("invalid K, G")
+	}
+
+	if !body.Z.Valid() {
+		return errors.New("invalid Z")
+	}
+
+	if err := body.CommitmentID.Validate(); err != nil {
+		return err
+	}
+
+	r.K[from] = body.K
+	r.G[from] = body.G
+	r.ElGamalK[from] = body.Z
+	r.CommitmentID[from] = body.CommitmentID
+
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify zkencelg(Kⱼ, Zⱼ).
+func (r *presign2) VerifyMessage(msg round.Message) error {
+	from, to := msg.From, msg.To
+	body, ok := msg.Content.(*message2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.Proof == nil {
+		return round.ErrNilFields
+	}
+
+	Z := r.ElGamalK[from]
+	if !body.Proof.Verify(r.HashForID(from), zkencelg.Public{
+		C:      r.K[from],
+		A:      r.ElGamal[from],
+		B:      Z.L,
+		X:      Z.M,
+		Prover: r.Paillier[from],
+		Aux:    r.Pedersen[to],
+	}) {
+		return errors.New("failed to validate encelg proof for K")
+	}
+
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (presign2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - Γᵢ = [γᵢ]⋅G
+// - Dᵢⱼ, Fᵢⱼ, βᵢⱼ = MtA(γᵢ, Kⱼ) with zkaffp
+// - D̂ᵢⱼ, F̂ᵢⱼ, β̂ᵢⱼ = MtA(xᵢ, Kⱼ) with zkaffg
+// - prove zklog*(Gᵢ, Γᵢ).
+// - reveal ridᵢ.
+func (r *presign2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// Γᵢ = [γᵢ]⋅G
+	BigGammaShare := r.GammaShare.ActOnBase()
+	GammaShareInt := curve.MakeInt(r.GammaShare)
+
+	if err := r.BroadcastMessage(out, &broadcast3{
+		BigGammaShare:  BigGammaShare,
+		DecommitmentID: r.DecommitmentID,
+		PresignatureID: r.PresignatureID,
+	}); err != nil {
+		return r, err
+	}
+
+	otherIDs := r.OtherPartyIDs()
+	type mtaOut struct {
+		err       error
+		DeltaBeta *saferith.Int
+		ChiBeta   *saferith.Int
+	}
+	readers := sample.Fork(r.Rand(), len(otherIDs))
+	mtaOuts := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
+		j := otherIDs[i]
+
+		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffP(readers[i], r.Group(), r.HashForID(r.SelfID()),
+			GammaShareInt, r.G[r.SelfID()], r.GNonce, r.K[j],
+			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
+		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(readers[i], r.Group(), r.HashForID(r.SelfID()),
+			curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()], r.K[j],
+			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
+
+		proofLog := zklogstar.NewProof(readers[i], r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
+			C:      r.G[r.SelfID()],
+			X:      BigGammaShare,
+			Prover: r.Paillier[r.SelfID()],
+			Aux:    r.Pedersen[j],
+		}, zklogstar.Private{
+			X:   GammaShareInt,
+			Rho: r.GNonce,
+		})
+
+		err := r.SendMessage(out, &message3{
+			DeltaD:     DeltaD,
+			DeltaF:     DeltaF,
+			DeltaProof: DeltaProof,
+			ChiD:       ChiD,
+			ChiF:       ChiF,
+			ChiProof:   ChiProof,
+			ProofLog:   proofLog,
+		}, j)
+		return mtaOut{
+			err:       err,
+			DeltaBeta: DeltaBeta,
+			ChiBeta:   ChiBeta,
+		}
+	})
+	DeltaShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs))
+	ChiShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs))
+	for idx, mtaOutRaw := range mtaOuts {
+		j := otherIDs[idx]
+		m := mtaOutRaw.(mtaOut)
+		if m.err != nil {
+			return r, m.err
+		}
+		DeltaShareBetas[j] = m.DeltaBeta
+		ChiShareBetas[j] = m.ChiBeta
+	}
+
+	return &presign3{
+		presign2:        r,
+		BigGammaShare:   map[party.ID]curve.Point{r.SelfID(): BigGammaShare},
+		PresignatureIDs: map[party.ID]types.RID{r.SelfID(): r.PresignatureID},
+		DeltaShareBeta:  DeltaShareBetas,
+		ChiShareBeta:    ChiShareBetas,
+		DeltaShareAlpha: map[party.ID]*saferith.Int{},
+		ChiShareAlpha:   map[party.ID]*saferith.Int{},
+	}, nil
+}
+
+// RoundNumber implements round.Content.
+func (message2) RoundNumber() round.Number { return 2 }
+
+// MessageContent implements round.Round.
+func (r *presign2) MessageContent() round.Content {
+	return &message2{Proof: zkencelg.Empty(r.Group())}
+}
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *presign2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2{Z: elgamal.Empty(r.Group())}
+}
+
+// Number implements round.Round.
+func (presign2) Number() round.Number { return 2 }
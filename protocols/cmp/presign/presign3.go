@@ -0,0 +1,263 @@
+package presign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/elgamal"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	zkaffg "github.com/w3-key/mps-lean/pkg/zk/affg"
+	zkaffp "github.com/w3-key/mps-lean/pkg/zk/affp"
+	zkelog "github.com/w3-key/mps-lean/pkg/zk/elog"
+	zklogstar "github.com/w3-key/mps-lean/pkg/zk/logstar"
+)
+
+var _ round.Round = (*presign3)(nil)
+
+type presign3 struct {
+	*presign2
+
+	// BigGammaShare[j] = Γⱼ = [γⱼ]•G
+	BigGammaShare map[party.ID]curve.Point
+	// PresignatureIDs[j] = ridⱼ
+	PresignatureIDs map[party.ID]types.RID
+
+	// DeltaShareAlpha[j] = αᵢⱼ
+	DeltaShareAlpha map[party.ID]*saferith.Int
+	// DeltaShareBeta[j] = βᵢⱼ
+	DeltaShareBeta map[party.ID]*saferith.Int
+	// ChiShareAlpha[j] = α̂ᵢⱼ
+	ChiShareAlpha map[party.ID]*saferith.Int
+	// ChiShareBeta[j] = β̂ᵢⱼ
+	ChiShareBeta map[party.ID]*saferith.Int
+}
+
+type broadcast3 struct {
+	round.NormalBroadcastContent
+	// BigGammaShare = Γᵢ
+	BigGammaShare curve.Point
+	// DecommitmentID is the decommitment for ridᵢ
+	DecommitmentID hash.Decommitment
+	// PresignatureID = ridᵢ
+	PresignatureID types.RID
+}
+
+type message3 struct {
+	DeltaD     *paillier.Ciphertext // DeltaD = Dᵢⱼ
+	DeltaF     *paillier.Ciphertext // DeltaF = Fᵢⱼ
+	DeltaProof *zkaffp.Proof
+	ChiD       *paillier.Ciphertext // ChiD = D̂ᵢⱼ
+	ChiF       *paillier.Ciphertext // ChiF = F̂ᵢⱼ
+	ChiProof   *zkaffg.Proof
+	ProofLog   *zklogstar.Proof
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - decommit ridⱼ
+// - store Γⱼ.
+func (r *presign3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.BigGammaShare.IsIdentity() {
+		return round.ErrNilFields
+	}
+
+	if err := body.PresignatureID.Validate(); err != nil {
+		return fmt.Errorf("presignature id: %w", err)
+	}
+
+	if !r.HashForID(from).Decommit(r.CommitmentID[from], body.DecommitmentID, body.PresignatureID) {
+		return errors.New("failed to decommit presignature id")
+	}
+
+	r.BigGammaShare[from] = body.BigGammaShare
+	r.PresignatureIDs[from] = body.PresignatureID
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify zkaffp, zkaffg, zklog*.
+func (r *presign3) VerifyMessage(msg round.Message) error {
+	from, to := msg.From, msg.To
+	body, ok := msg.Content.(*message3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.DeltaProof == nil || body.ChiProof == nil || body.ProofLog == nil {
+		return round.ErrNilFields
+	}
+
+	if !body.DeltaProof.Verify(r.Group(), r.HashForID(from), zkaffp.Public{
+		Kv:       r.K[to],
+		Dv:       body.DeltaD,
+		Fp:       body.DeltaF,
+		Xp:       r.G[from],
+		Prover:   r.Paillier[from],
+		Verifier: r.Paillier[to],
+		Aux:      r.Pedersen[to],
+	}) {
+		return errors.New("failed to validate affp proof for Delta MtA")
+	}
+
+	if !body.ChiProof.Verify(r.HashForID(from), zkaffg.Public{
+		Kv:       r.K[to],
+		Dv:       body.ChiD,
+		Fp:       body.ChiF,
+		Xp:       r.ECDSA[from],
+		Prover:   r.Paillier[from],
+		Verifier: r.Paillier[to],
+		Aux:      r.Pedersen[to],
+	}) {
+		return errors.New("failed to validate affg proof for Chi MtA")
+	}
+
+	if !body.ProofLog.Verify(r.HashForID(from), zklogstar.Public{
+		C:      r.G[from],
+		X:      r.BigGammaShare[from],
+		Prover: r.Paillier[from],
+		Aux:    r.Pedersen[to],
+	}) {
+		return errors.New("failed to validate log proof")
+	}
+
+	return nil
+}
+
+// StoreMessage implements round.Round.
+//
+// - decrypt MtA shares,
+// - save αᵢⱼ, α̂ᵢⱼ.
+func (r *presign3) StoreMessage(msg round.Message) error {
+	from, body := msg.From, msg.Content.(*message3)
+
+	// αᵢⱼ
+	DeltaShareAlpha, err := r.SecretPaillier.Dec(body.DeltaD)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt alpha share for delta: %w", err)
+	}
+	// α̂ᵢⱼ
+	ChiShareAlpha, err := r.SecretPaillier.Dec(body.ChiD)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt alpha share for chi: %w", err)
+	}
+
+	r.DeltaShareAlpha[from] = DeltaShareAlpha
+	r.ChiShareAlpha[from] = ChiShareAlpha
+
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - Γ = ∑ⱼ Γⱼ
+// - Δᵢ = [kᵢ]Γ
+// - δᵢ = γᵢ kᵢ + ∑ⱼ δᵢⱼ
+// - χᵢ = xᵢ kᵢ + ∑ⱼ χᵢⱼ
+// - rid = ⊕ⱼ ridⱼ
+// - encrypt χᵢ with ElGamal, and prove zkelog(Zᵢ, Δᵢ).
+func (r *presign3) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// Γ = ∑ⱼ Γⱼ
+	Gamma := r.Group().NewPoint()
+	for _, BigGammaShare := range r.BigGammaShare {
+		Gamma = Gamma.Add(BigGammaShare)
+	}
+
+	// Δᵢ = [kᵢ]Γ
+	KShareInt := curve.MakeInt(r.KShare)
+	BigDeltaShare := r.KShare.Act(Gamma)
+
+	// δᵢ = γᵢ kᵢ
+	DeltaShare := new(saferith.Int).Mul(curve.MakeInt(r.GammaShare), KShareInt, -1)
+
+	// χᵢ = xᵢ kᵢ
+	ChiShare := new(saferith.Int).Mul(curve.MakeInt(r.SecretECDSA), KShareInt, -1)
+
+	for _, j := range r.OtherPartyIDs() {
+		// δᵢ += αᵢⱼ + βᵢⱼ
+		DeltaShare.Add(DeltaShare, r.DeltaShareAlpha[j], -1)
+		DeltaShare.Add(DeltaShare, r.DeltaShareBeta[j], -1)
+
+		// χᵢ += α̂ᵢⱼ + β̂ᵢⱼ
+		ChiShare.Add(ChiShare, r.ChiShareAlpha[j], -1)
+		ChiShare.Add(ChiShare, r.ChiShareBeta[j], -1)
+	}
+
+	DeltaShareScalar := r.Group().NewScalar().SetNat(DeltaShare.Mod(r.Group().Order()))
+	ChiShareScalar := r.Group().NewScalar().SetNat(ChiShare.Mod(r.Group().Order()))
+
+	// rid = ⊕ⱼ ridⱼ
+	PresignatureID := types.EmptyRID()
+	for _, j := range r.PartyIDs() {
+		PresignatureID.XOR(r.PresignatureIDs[j])
+	}
+
+	// Ẑᵢ = (b̂ᵢ⋅G, χᵢ⋅G+b̂ᵢ⋅Yᵢ)
+	ElGamalChi, ElGamalChiNonce := elgamal.Encrypt(r.Rand(), r.ElGamal[r.SelfID()], ChiShareScalar)
+
+	proof := zkelog.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zkelog.Public{
+		E:             r.ElGamalK[r.SelfID()],
+		ElGamalPublic: r.ElGamal[r.SelfID()],
+		Base:          Gamma,
+		Y:             BigDeltaShare,
+	}, zkelog.Private{
+		Y:      r.KShare,
+		Lambda: r.ElGamalKNonce,
+	})
+
+	if err := r.BroadcastMessage(out, &broadcast4{
+		DeltaShare:    DeltaShareScalar,
+		BigDeltaShare: BigDeltaShare,
+		ElGamalChi:    ElGamalChi,
+		Proof:         proof,
+	}); err != nil {
+		return r, err
+	}
+
+	return &presign4{
+		presign3:        r,
+		PresignatureID:  PresignatureID,
+		Gamma:           Gamma,
+		DeltaShares:     map[party.ID]curve.Scalar{r.SelfID(): DeltaShareScalar},
+		BigDeltaShares:  map[party.ID]curve.Point{r.SelfID(): BigDeltaShare},
+		ElGamalChi:      map[party.ID]*elgamal.Ciphertext{r.SelfID(): ElGamalChi},
+		ChiShare:        ChiShareScalar,
+		ElGamalChiNonce: ElGamalChiNonce,
+	}, nil
+}
+
+// RoundNumber implements round.Content.
+func (message3) RoundNumber() round.Number { return 3 }
+
+// MessageContent implements round.Round.
+func (r *presign3) MessageContent() round.Content {
+	return &message3{
+		ChiProof: zkaffg.Empty(r.Group()),
+		ProofLog: zklogstar.Empty(r.Group()),
+	}
+}
+
+// RoundNumber implements round.Content.
+func (broadcast3) RoundNumber() round.Number { return 3 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *presign3) BroadcastContent() round.BroadcastContent {
+	return &broadcast3{
+		BigGammaShare: r.Group().NewPoint(),
+	}
+}
+
+// Number implements round.Round.
+func (presign3) Number() round.Number { return 3 }
@@ -0,0 +1,167 @@
+package presign
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/elgamal"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	zkelog "github.com/w3-key/mps-lean/pkg/zk/elog"
+)
+
+var _ round.Round = (*presign4)(nil)
+
+type presign4 struct {
+	*presign3
+
+	// PresignatureID = rid = ⊕ⱼ ridⱼ
+	PresignatureID types.RID
+
+	// Gamma = Γ = ∑ⱼ Γⱼ
+	Gamma curve.Point
+
+	// DeltaShares[j] = δⱼ
+	DeltaShares map[party.ID]curve.Scalar
+	// BigDeltaShares[j] = Δⱼ = [kⱼ]•Γ
+	BigDeltaShares map[party.ID]curve.Point
+	// ElGamalChi[j] = Ẑⱼ = (b̂ⱼ⋅G, χⱼ⋅G+b̂ⱼ⋅Yⱼ)
+	ElGamalChi map[party.ID]*elgamal.Ciphertext
+
+	// ChiShare = χᵢ
+	ChiShare curve.Scalar
+	// ElGamalChiNonce = b̂ᵢ
+	ElGamalChiNonce elgamal.Nonce
+}
+
+type broadcast4 struct {
+	round.NormalBroadcastContent
+	// DeltaShare = δⱼ
+	DeltaShare curve.Scalar
+	// BigDeltaShare = Δⱼ = [kⱼ]•Γ
+	BigDeltaShare curve.Point
+	// ElGamalChi = Ẑⱼ
+	ElGamalChi *elgamal.Ciphertext
+	// Proof = zkelog(Zⱼ, Δⱼ)
+	Proof *zkelog.Proof
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify zkelog(Zⱼ, Δⱼ)
+// - store δⱼ, Δⱼ, Ẑⱼ.
+func (r *presign4) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast4)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.DeltaShare.IsZero() || body.BigDeltaShare.IsIdentity() || body.Proof == nil {
+		return round.ErrNilFields
+	}
+
+	if !body.ElGamalChi.Valid() {
+		return errors.New("invalid ElGamalChi")
+	}
+
+	if !body.Proof.Verify(r.HashForID(from), zkelog.Public{
+		E:             r.ElGamalK[from],
+		ElGamalPublic: r.ElGamal[from],
+		Base:          r.Gamma,
+		Y:             body.BigDeltaShare,
+	}) {
+		return errors.New("failed to validate elog proof for Δ")
+	}
+
+	r.DeltaShares[from] = body.DeltaShare
+	r.BigDeltaShares[from] = body.BigDeltaShare
+	r.ElGamalChi[from] = body.ElGamalChi
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (presign4) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (presign4) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - set δ = ∑ⱼ δⱼ
+// - set Δ = ∑ⱼ Δⱼ
+// - verify Δ = [δ]G
+// - compute R = [δ⁻¹]Γ, R̄ⱼ = [δ⁻¹]Δⱼ
+// - compute Sᵢ = [χᵢ]R and prove zkelog(Ẑᵢ, Sᵢ).
+func (r *presign4) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// δ = ∑ⱼ δⱼ
+	// Δ = ∑ⱼ Δⱼ
+	Delta := r.Group().NewScalar()
+	BigDelta := r.Group().NewPoint()
+	for _, j := range r.PartyIDs() {
+		Delta.Add(r.DeltaShares[j])
+		BigDelta = BigDelta.Add(r.BigDeltaShares[j])
+	}
+
+	// Δ == [δ]G
+	if !Delta.ActOnBase().Equal(BigDelta) {
+		return r.AbortRound(errors.New("computed Δ is inconsistent with [δ]G")), nil
+	}
+
+	// δ⁻¹
+	DeltaInv := r.Group().NewScalar().Set(Delta).Invert()
+	// R = [δ⁻¹]Γ
+	R := DeltaInv.Act(r.Gamma)
+	// R̄ⱼ = [δ⁻¹]Δⱼ
+	RBar := make(map[party.ID]curve.Point, r.N())
+	for _, j := range r.PartyIDs() {
+		RBar[j] = DeltaInv.Act(r.BigDeltaShares[j])
+	}
+
+	// Sᵢ = [χᵢ]R
+	S := r.ChiShare.Act(R)
+
+	proof := zkelog.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zkelog.Public{
+		E:             r.ElGamalChi[r.SelfID()],
+		ElGamalPublic: r.ElGamal[r.SelfID()],
+		Base:          R,
+		Y:             S,
+	}, zkelog.Private{
+		Y:      r.ChiShare,
+		Lambda: r.ElGamalChiNonce,
+	})
+
+	if err := r.BroadcastMessage(out, &broadcast5{
+		S:     S,
+		Proof: proof,
+	}); err != nil {
+		return r, err
+	}
+
+	return &presign5{
+		presign4: r,
+		R:        R,
+		RBar:     RBar,
+		S:        map[party.ID]curve.Point{r.SelfID(): S},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (presign4) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast4) RoundNumber() round.Number { return 4 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *presign4) BroadcastContent() round.BroadcastContent {
+	return &broadcast4{
+		DeltaShare:    r.Group().NewScalar(),
+		BigDeltaShare: r.Group().NewPoint(),
+		ElGamalChi:    elgamal.Empty(r.Group()),
+		Proof:         zkelog.Empty(r.Group()),
+	}
+}
+
+// Number implements round.Round.
+func (presign4) Number() round.Number { return 4 }
@@ -0,0 +1,118 @@
+package presign
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zkelog "github.com/w3-key/mps-lean/pkg/zk/elog"
+)
+
+var _ round.Round = (*presign5)(nil)
+
+type presign5 struct {
+	*presign4
+
+	// R = [δ⁻¹]Γ
+	R curve.Point
+	// RBar[j] = R̄ⱼ = [δ⁻¹]Δⱼ
+	RBar map[party.ID]curve.Point
+	// S[j] = Sⱼ = [χⱼ]R
+	S map[party.ID]curve.Point
+}
+
+type broadcast5 struct {
+	round.NormalBroadcastContent
+	// S = Sⱼ = [χⱼ]R
+	S curve.Point
+	// Proof = zkelog(Ẑⱼ, Sⱼ)
+	Proof *zkelog.Proof
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify zkelog(Ẑⱼ, Sⱼ)
+// - store Sⱼ.
+func (r *presign5) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast5)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.S.IsIdentity() || body.Proof == nil {
+		return round.ErrNilFields
+	}
+
+	if !body.Proof.Verify(r.HashForID(from), zkelog.Public{
+		E:             r.ElGamalChi[from],
+		ElGamalPublic: r.ElGamal[from],
+		Base:          r.R,
+		Y:             body.S,
+	}) {
+		return errors.New("failed to validate elog proof for S")
+	}
+
+	r.S[from] = body.S
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (presign5) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (presign5) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - verify ∑ⱼ Sⱼ = X
+// - output the PreSignature, or continue with the online round if a message was given.
+func (r *presign5) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// ∑ⱼ Sⱼ = ∑ⱼ [χⱼ]R = [kx]([k⁻¹]G) = X
+	PublicKeyComputed := r.Group().NewPoint()
+	for _, Sj := range r.S {
+		PublicKeyComputed = PublicKeyComputed.Add(Sj)
+	}
+	if !r.PublicKey.Equal(PublicKeyComputed) {
+		return r.AbortRound(errors.New("computed ∑ⱼ Sⱼ is inconsistent with public key")), nil
+	}
+
+	preSignature := &ecdsa.PreSignature{
+		ID:       r.PresignatureID,
+		R:        r.R,
+		RBar:     party.NewPointMap(r.RBar),
+		S:        party.NewPointMap(r.S),
+		KShare:   r.KShare,
+		ChiShare: r.ChiShare,
+	}
+	if r.Message == nil {
+		return r.ResultRound(preSignature), nil
+	}
+
+	rSign1 := &sign1{
+		Helper:       r.Helper,
+		PublicKey:    r.PublicKey,
+		Message:      r.Message,
+		PreSignature: preSignature,
+	}
+	return rSign1.Finalize(out)
+}
+
+// MessageContent implements round.Round.
+func (presign5) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast5) RoundNumber() round.Number { return 5 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *presign5) BroadcastContent() round.BroadcastContent {
+	return &broadcast5{
+		S:     r.Group().NewPoint(),
+		Proof: zkelog.Empty(r.Group()),
+	}
+}
+
+// Number implements round.Round.
+func (presign5) Number() round.Number { return 5 }
@@ -0,0 +1,129 @@
+package presign
+
+import (
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+	"github.com/w3-key/mps-lean/protocols/cmp/sign"
+	"golang.org/x/crypto/sha3"
+)
+
+func runRounds(t *testing.T, rounds []round.Session) {
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+}
+
+func setup(t *testing.T, pl *pool.Pool) (map[party.ID]*config.Config, party.IDSlice, []byte) {
+	group := curve.Secp256k1{}
+
+	N := 4
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	messageHash := make([]byte, 64)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+	return configs, partyIDs[:T+1], messageHash
+}
+
+func TestPresign(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs, messageHash := setup(t, pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, partyID := range partyIDs {
+		// an empty message is the same as nil, and only creates a presignature
+		r, err := StartPresign(configs[partyID], partyIDs, []byte{}, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	runRounds(t, rounds)
+
+	preSignatures := make(map[party.ID]*ecdsa.PreSignature, len(partyIDs))
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		resultRound := r.(*round.Output)
+		require.IsType(t, &ecdsa.PreSignature{}, resultRound.Result, "expected presignature result")
+		preSignature := resultRound.Result.(*ecdsa.PreSignature)
+		require.NoError(t, preSignature.Validate())
+		preSignatures[r.SelfID()] = preSignature
+	}
+
+	first := preSignatures[partyIDs[0]]
+	for _, preSignature := range preSignatures {
+		assert.Equal(t, first.ID, preSignature.ID, "presignature IDs should match")
+		assert.True(t, first.R.Equal(preSignature.R), "presignature R should match")
+	}
+
+	stores := make(map[party.ID]sign.PreSignatureStore, len(partyIDs))
+	for _, partyID := range partyIDs {
+		stores[partyID] = sign.NewMemoryStore()
+		require.NoError(t, stores[partyID].Put(preSignatures[partyID]))
+	}
+
+	rounds = rounds[:0]
+	for _, partyID := range partyIDs {
+		r, err := StartPresignOnline(configs[partyID], stores[partyID], first.ID, messageHash, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	runRounds(t, rounds)
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		resultRound := r.(*round.Output)
+		require.IsType(t, &ecdsa.Signature{}, resultRound.Result, "expected signature result")
+		signature := resultRound.Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
+	}
+
+	// signing another message with the same presignature would reveal the key
+	otherHash := make([]byte, len(messageHash))
+	copy(otherHash, messageHash)
+	otherHash[0] ^= 1
+	for _, partyID := range partyIDs {
+		_, err := StartPresignOnline(configs[partyID], stores[partyID], first.ID, otherHash, pl)(nil)
+		assert.ErrorIs(t, err, sign.ErrPreSignatureUsed, "a presignature should only be used once")
+	}
+}
+
+func TestPresignWithMessage(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	configs, partyIDs, messageHash := setup(t, pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, partyID := range partyIDs {
+		r, err := StartPresign(configs[partyID], partyIDs, messageHash, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	runRounds(t, rounds)
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		resultRound := r.(*round.Output)
+		require.IsType(t, &ecdsa.Signature{}, resultRound.Result, "expected signature result")
+		signature := resultRound.Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
+	}
+}
@@ -0,0 +1,52 @@
+package presign
+
+import (
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*sign1)(nil)
+
+type sign1 struct {
+	*round.Helper
+
+	// PublicKey = X
+	PublicKey curve.Point
+
+	// Message is the message to be signed.
+	Message []byte
+
+	// PreSignature generated by the presign protocol.
+	PreSignature *ecdsa.PreSignature
+}
+
+// VerifyMessage implements round.Round.
+func (sign1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (sign1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - compute σᵢ = kᵢm + rχᵢ and broadcast it.
+func (r *sign1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// σᵢ = kᵢm + rχᵢ
+	SigmaShare := r.PreSignature.SignatureShare(r.Message)
+
+	if err := r.BroadcastMessage(out, &broadcast6{SigmaShare: SigmaShare}); err != nil {
+		return r, err
+	}
+
+	return &sign2{
+		sign1:       r,
+		SigmaShares: map[party.ID]ecdsa.SignatureShare{r.SelfID(): SigmaShare},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (sign1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (sign1) Number() round.Number { return 1 }
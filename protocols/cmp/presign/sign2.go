@@ -0,0 +1,78 @@
+package presign
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*sign2)(nil)
+
+type sign2 struct {
+	*sign1
+	// SigmaShares[j] = σⱼ = kⱼm + rχⱼ
+	SigmaShares map[party.ID]ecdsa.SignatureShare
+}
+
+type broadcast6 struct {
+	round.NormalBroadcastContent
+	// SigmaShare = σⱼ
+	SigmaShare ecdsa.SignatureShare
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - store σⱼ.
+func (r *sign2) StoreBroadcastMessage(msg round.Message) error {
+	body, ok := msg.Content.(*broadcast6)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.SigmaShare.IsZero() {
+		return round.ErrNilFields
+	}
+
+	r.SigmaShares[msg.From] = body.SigmaShare
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (sign2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (sign2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - compute σ = ∑ⱼ σⱼ
+// - verify the signature, and identify the parties with invalid shares if it fails.
+func (r *sign2) Finalize(chan<- *round.Message) (round.Session, error) {
+	// compute σ = ∑ⱼ σⱼ
+	signature := r.PreSignature.Signature(r.SigmaShares)
+
+	if !signature.Verify(r.PublicKey, r.Message) {
+		culprits := r.PreSignature.VerifySignatureShares(r.SigmaShares, r.Message)
+		return r.AbortRound(errors.New("signature failed to verify"), culprits...), nil
+	}
+
+	return r.ResultRound(signature), nil
+}
+
+// MessageContent implements round.Round.
+func (sign2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast6) RoundNumber() round.Number { return 6 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *sign2) BroadcastContent() round.BroadcastContent {
+	return &broadcast6{
+		SigmaShare: r.Group().NewScalar(),
+	}
+}
+
+// Number implements round.Round.
+func (sign2) Number() round.Number { return 6 }
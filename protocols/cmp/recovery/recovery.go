@@ -0,0 +1,104 @@
+package recovery
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+const (
+	// Identifier for the recovery protocol.
+	protocolID = "cmp/recover-threshold"
+	// protocolRounds is the number of rounds of the recovery protocol.
+	protocolRounds round.Number = 3
+)
+
+// StartRecover returns a protocol.StartFunc run by a party in `helpers` to recover the share of `lost`.
+//
+// `helpers` must contain at least c.Threshold+1 parties holding a share of the key, and not `lost`.
+// The protocol is run between the helpers and the party `lost`, which uses StartRecoverNew.
+func StartRecover(c *config.Config, helpers []party.ID, lost party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		helperIDs := party.NewIDSlice(helpers)
+		if !helperIDs.Valid() {
+			return nil, errors.New("recovery: helpers invalid")
+		}
+		if !helperIDs.Contains(c.ID) {
+			return nil, errors.New("recovery: helpers must contain self")
+		}
+		if helperIDs.Contains(lost) {
+			return nil, errors.New("recovery: helpers cannot contain the lost party")
+		}
+		if !config.ValidThreshold(c.Threshold, len(helperIDs)) {
+			return nil, fmt.Errorf("recovery: need at least %d helpers, got %d", c.Threshold+1, len(helperIDs))
+		}
+		for _, j := range append(helperIDs.Copy(), lost) {
+			if _, ok := c.Public[j]; !ok {
+				return nil, fmt.Errorf("recovery: party %s does not hold a share", j)
+			}
+		}
+		return start(c.Group, c.ID, c.PublicPoint(), helperIDs, lost, c, pl, opts...)(sessionID)
+	}
+}
+
+// StartRecoverNew returns a protocol.StartFunc run by the party whose share is recovered with StartRecover.
+//
+// `publicKey` is the group's ECDSA public key, which must be obtained from a trusted source.
+func StartRecoverNew(group curve.Curve, selfID party.ID, publicKey curve.Point, helpers []party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		helperIDs := party.NewIDSlice(helpers)
+		if !helperIDs.Valid() || len(helperIDs) == 0 {
+			return nil, errors.New("recovery: helpers invalid")
+		}
+		if helperIDs.Contains(selfID) {
+			return nil, errors.New("recovery: helpers cannot contain the lost party")
+		}
+		if publicKey == nil || publicKey.IsIdentity() {
+			return nil, errors.New("recovery: invalid public key")
+		}
+		return start(group, selfID, publicKey, helperIDs, selfID, nil, pl, opts...)(sessionID)
+	}
+}
+
+func start(group curve.Curve, selfID party.ID, publicKey curve.Point, helpers party.IDSlice, lost party.ID, c *config.Config, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       protocolID,
+			FinalRoundNumber: protocolRounds,
+			SelfID:           selfID,
+			PartyIDs:         append(helpers.Copy(), lost),
+			// the threshold of the key is only known to the helpers,
+			// so we use the largest one for which the helpers can recover a share.
+			Threshold: len(helpers) - 1,
+			Group:     group,
+		}
+		info.Apply(opts...)
+
+		publicKeyBytes, err := publicKey.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("recovery: %w", err)
+		}
+		helper, err := round.NewSession(info, sessionID, pl, helpers, &hash.BytesWithDomain{
+			TheDomain: "Public Key",
+			Bytes:     publicKeyBytes,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("recovery: %w", err)
+		}
+
+		return &round1{
+			Helper:    helper,
+			Helpers:   helpers,
+			Target:    lost,
+			PublicKey: publicKey,
+			Config:    c,
+		}, nil
+	}
+}
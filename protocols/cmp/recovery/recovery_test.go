@@ -0,0 +1,138 @@
+package recovery
+
+import (
+	"crypto/rand"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+	"github.com/w3-key/mps-lean/protocols/cmp/sign"
+)
+
+var group = curve.Secp256k1{}
+
+func startRounds(t *testing.T, configs map[party.ID]*config.Config, helpers party.IDSlice, lost party.ID, pl *pool.Pool) []round.Session {
+	publicKey := configs[helpers[0]].PublicPoint()
+	rounds := make([]round.Session, 0, len(helpers)+1)
+	for _, id := range helpers {
+		r, err := StartRecover(configs[id], helpers, lost, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	r, err := StartRecoverNew(group, lost, publicKey, helpers, pl)(nil)
+	require.NoError(t, err, "round creation should not result in an error")
+	return append(rounds, r)
+}
+
+func TestRecover(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N, T := 4, 2
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	publicKey := configs[partyIDs[0]].PublicPoint()
+	helpers, lost := partyIDs[:T+1], partyIDs[T+1]
+	oldConfig := configs[lost]
+
+	rounds := startRounds(t, configs, helpers, lost, pl)
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	newConfigs := make(map[party.ID]*config.Config, N)
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		result := r.(*round.Output).Result
+		require.IsType(t, &config.Config{}, result)
+		newConfigs[r.SelfID()] = result.(*config.Config)
+	}
+
+	recovered := newConfigs[lost]
+	assert.Equal(t, lost, recovered.ID)
+	assert.Equal(t, oldConfig.Threshold, recovered.Threshold)
+	assert.True(t, oldConfig.ECDSA.Equal(recovered.ECDSA), "recovered share is different")
+	assert.False(t, oldConfig.Paillier.PublicKey.Equal(recovered.Paillier.PublicKey), "Paillier key was not replaced")
+	assert.Equal(t, oldConfig.RID, recovered.RID)
+	assert.EqualValues(t, oldConfig.ChainKey, recovered.ChainKey)
+	for _, c := range newConfigs {
+		assert.True(t, publicKey.Equal(c.PublicPoint()), "public key is different")
+		assert.True(t, c.Public[lost].Paillier.Equal(recovered.Paillier.PublicKey), "paillier not the same")
+		assert.True(t, c.Public[lost].ElGamal.Equal(recovered.ElGamal.ActOnBase()), "elgamal not the same")
+	}
+
+	// sign with the recovered share
+	signers := party.IDSlice{partyIDs[0], partyIDs[2], lost}
+	messageHash := make([]byte, 32)
+	_, _ = rand.Read(messageHash)
+	rounds = make([]round.Session, 0, len(signers))
+	for _, id := range signers {
+		r, err := sign.StartSign(newConfigs[id], signers, messageHash, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		require.IsType(t, &ecdsa.Signature{}, r.(*round.Output).Result)
+		signature := r.(*round.Output).Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicKey, messageHash), "expected valid signature")
+	}
+}
+
+// shareRule makes culprit send an encryption of a random share to the Target in round 3.
+type shareRule struct {
+	culprit party.ID
+}
+
+func (shareRule) ModifyBefore(round.Session) {}
+
+func (shareRule) ModifyAfter(round.Session) {}
+
+func (r *shareRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	body, ok := content.(*broadcast3)
+	if !ok || rNext.SelfID() != r.culprit {
+		return
+	}
+	r3 := rNext.(*round3)
+	body.Share, _ = r3.TargetPaillier.Enc(rand.Reader, curve.MakeInt(sample.Scalar(rand.Reader, group)))
+}
+
+func TestRecoverInvalidShare(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	N, T := 3, 1
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	helpers, lost := partyIDs[:T+1], partyIDs[T+1]
+
+	rounds := startRounds(t, configs, helpers, lost, pl)
+	rule := &shareRule{culprit: helpers[0]}
+	var err error
+	for {
+		var done bool
+		err, done = test.Rounds(rounds, rule)
+		if err != nil || done {
+			break
+		}
+	}
+	assert.EqualError(t, err, "share does not match mask commitments")
+}
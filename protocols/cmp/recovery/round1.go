@@ -0,0 +1,136 @@
+package recovery
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zkmod "github.com/w3-key/mps-lean/pkg/zk/mod"
+	zkprm "github.com/w3-key/mps-lean/pkg/zk/prm"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+
+	// Helpers are the parties holding a share, which recreate the share of Target.
+	Helpers party.IDSlice
+
+	// Target is the party whose share xₜ is recovered.
+	Target party.ID
+
+	// PublicKey = X is the public key of the group.
+	PublicKey curve.Point
+
+	// Config is the config of a helper, and nil for the Target.
+	Config *config.Config
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// Target:
+//   - sample Paillier (pₜ, qₜ), Pedersen Nₜ, sₜ, tₜ and ElGamal yₜ
+//   - prove Nₜ is Blum and sₜ, tₜ are correct Pedersen parameters
+//
+// Helper i:
+//   - compute cᵢ = λᵢ(t)⋅xᵢ, where λᵢ(t) is the Lagrange coefficient at the Target's index
+//   - sample sᵢⱼ <- 𝔽 for all helpers j ≠ i, and set sᵢᵢ = cᵢ - ∑ⱼ sᵢⱼ
+//   - broadcast Sᵢⱼ = sᵢⱼ⋅G and Encⱼ(sᵢⱼ), as well as the public data of the config.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	nextRound := &round2{
+		round1:      r,
+		PublicData:  map[party.ID][]byte{},
+		Commitments: map[party.ID]map[party.ID]curve.Point{},
+		Masks:       map[party.ID]curve.Scalar{},
+	}
+
+	if r.Config == nil {
+		PaillierSecret := paillier.NewSecretKey(r.Rand(), nil)
+		PedersenPublic, PedersenSecret := PaillierSecret.GeneratePedersen(r.Rand())
+		ElGamalSecret, ElGamalPublic := sample.ScalarPointPair(r.Rand(), r.Group())
+
+		mod := zkmod.NewProof(r.Rand(), r.HashForID(r.SelfID()), zkmod.Private{
+			P:   PaillierSecret.P(),
+			Q:   PaillierSecret.Q(),
+			Phi: PaillierSecret.Phi(),
+		}, zkmod.Public{N: PedersenPublic.N()}, r.Pool)
+		prm := zkprm.NewProof(r.Rand(), zkprm.Private{
+			Lambda: PedersenSecret,
+			Phi:    PaillierSecret.Phi(),
+			P:      PaillierSecret.P(),
+			Q:      PaillierSecret.Q(),
+		}, r.HashForID(r.SelfID()), zkprm.Public{N: PedersenPublic.N(), S: PedersenPublic.S(), T: PedersenPublic.T()}, r.Pool)
+
+		if err := r.BroadcastMessage(out, &broadcast2{Parameters: &parameters{
+			N:       PedersenPublic.N(),
+			S:       PedersenPublic.S(),
+			T:       PedersenPublic.T(),
+			ElGamal: ElGamalPublic,
+			Mod:     mod,
+			Prm:     prm,
+		}}); err != nil {
+			return r, err
+		}
+
+		nextRound.TargetPaillier = PaillierSecret.PublicKey
+		nextRound.TargetPedersen = PedersenPublic
+		nextRound.TargetElGamal = ElGamalPublic
+		nextRound.PaillierSecret = PaillierSecret
+		nextRound.ElGamalSecret = ElGamalSecret
+		return nextRound, nil
+	}
+
+	PublicData, err := r.Config.MarshalPublic()
+	if err != nil {
+		return r, err
+	}
+
+	// cᵢ = λᵢ(t)⋅xᵢ
+	lagrange := polynomial.LagrangeAt(r.Group(), r.Helpers, r.Target.Scalar(r.Group()))
+	SelfMask := r.Group().NewScalar().Set(lagrange[r.SelfID()]).Mul(r.Config.ECDSA)
+
+	Masks := make(map[party.ID]curve.Scalar, len(r.Helpers))
+	Commitments := make(map[party.ID]curve.Point, len(r.Helpers))
+	Ciphertexts := make(map[party.ID]*paillier.Ciphertext, len(r.Helpers)-1)
+	for _, j := range r.Helpers {
+		if j == r.SelfID() {
+			continue
+		}
+		Masks[j] = sample.Scalar(r.Rand(), r.Group())
+		SelfMask.Sub(Masks[j])
+		Ciphertexts[j], _ = r.Config.Public[j].Paillier.Enc(r.Rand(), curve.MakeInt(Masks[j]))
+	}
+	Masks[r.SelfID()] = SelfMask
+	for j, s := range Masks {
+		Commitments[j] = s.ActOnBase()
+	}
+
+	if err = r.BroadcastMessage(out, &broadcast2{
+		PublicData:  PublicData,
+		Commitments: party.NewPointMap(Commitments),
+		Masks:       Ciphertexts,
+	}); err != nil {
+		return r, err
+	}
+
+	nextRound.PublicData[r.SelfID()] = PublicData
+	nextRound.Commitments[r.SelfID()] = Commitments
+	nextRound.Masks[r.SelfID()] = SelfMask
+	return nextRound, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
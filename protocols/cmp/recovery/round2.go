@@ -0,0 +1,264 @@
+package recovery
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pedersen"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zkmod "github.com/w3-key/mps-lean/pkg/zk/mod"
+	zkprm "github.com/w3-key/mps-lean/pkg/zk/prm"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+
+	// PublicData[j] is the encoded public data of helper j's config.
+	PublicData map[party.ID][]byte
+
+	// Commitments[j][l] = Sⱼₗ = sⱼₗ⋅G for all helpers j, l.
+	Commitments map[party.ID]map[party.ID]curve.Point
+
+	// Masks[j] = sⱼᵢ is the mask received from helper j.
+	// Only set for helpers.
+	Masks map[party.ID]curve.Scalar
+
+	// TargetPaillier, TargetPedersen and TargetElGamal are the new public parameters of the Target.
+	TargetPaillier *paillier.PublicKey
+	TargetPedersen *pedersen.Parameters
+	TargetElGamal  curve.Point
+
+	// PaillierSecret and ElGamalSecret are the new secrets of the Target.
+	// Only set for the Target.
+	PaillierSecret *paillier.SecretKey
+	ElGamalSecret  curve.Scalar
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+
+	// PublicData is the encoded public data of the helper's config.
+	PublicData []byte
+	// Commitments[j] = Sᵢⱼ = sᵢⱼ⋅G
+	Commitments *party.PointMap
+	// Masks[j] = Encⱼ(sᵢⱼ) for all helpers j ≠ i.
+	Masks map[party.ID]*paillier.Ciphertext
+
+	// Parameters are the new public parameters of the Target.
+	Parameters *parameters
+}
+
+type parameters struct {
+	// N, S, T are the Target's new Paillier and Pedersen parameters.
+	N *saferith.Modulus
+	S *saferith.Nat
+	T *saferith.Nat
+	// ElGamal = Yₜ
+	ElGamal curve.Point
+	// Mod proves that N is Blum.
+	Mod *zkmod.Proof
+	// Prm proves that S, T are correct Pedersen parameters.
+	Prm *zkprm.Proof
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// From the Target:
+//   - validate Nₜ, sₜ, tₜ with zkmod and zkprm.
+//
+// From helper j:
+//   - if we are a helper, verify that the public data matches ours and that ∑ₗ Sⱼₗ = λⱼ(t)⋅Xⱼ
+//   - if we are a helper, decrypt sⱼᵢ and verify sⱼᵢ⋅G = Sⱼᵢ
+//   - store the public data and Sⱼₗ.
+func (r *round2) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if from == r.Target {
+		return r.storeTarget(body.Parameters)
+	}
+
+	if len(body.PublicData) == 0 || body.Commitments == nil || body.Masks == nil {
+		return round.ErrNilFields
+	}
+	Commitments := body.Commitments.Points
+	if len(Commitments) != len(r.Helpers) || len(body.Masks) != len(r.Helpers)-1 {
+		return errors.New("invalid number of masks")
+	}
+	for _, l := range r.Helpers {
+		if _, ok = Commitments[l]; !ok {
+			return errors.New("missing mask commitment")
+		}
+		if _, ok = body.Masks[l]; !ok && l != from {
+			return errors.New("missing mask")
+		}
+	}
+
+	if r.Config != nil {
+		if !bytes.Equal(body.PublicData, r.PublicData[r.SelfID()]) {
+			return errors.New("public data differs from ours")
+		}
+		if err := verifyCommitments(r.Config, r.Helpers, r.Target, from, Commitments); err != nil {
+			return err
+		}
+
+		// sⱼᵢ = Decᵢ(Encᵢ(sⱼᵢ))
+		ciphertext := body.Masks[r.SelfID()]
+		if !r.Config.Paillier.ValidateCiphertexts(ciphertext) {
+			return errors.New("invalid mask ciphertext")
+		}
+		DecryptedMask, err := r.Config.Paillier.Dec(ciphertext)
+		if err != nil {
+			return err
+		}
+		Mask := r.Group().NewScalar().SetNat(DecryptedMask.Mod(r.Group().Order()))
+		if DecryptedMask.Eq(curve.MakeInt(Mask)) != 1 {
+			return errors.New("decrypted mask is not in the correct range")
+		}
+		if !Mask.ActOnBase().Equal(Commitments[r.SelfID()]) {
+			return errors.New("mask does not match commitment")
+		}
+		r.Masks[from] = Mask
+	}
+
+	r.PublicData[from] = body.PublicData
+	r.Commitments[from] = Commitments
+	return nil
+}
+
+// storeTarget validates and stores the Target's new public parameters.
+func (r *round2) storeTarget(body *parameters) error {
+	if body == nil || body.N == nil || body.S == nil || body.T == nil || body.ElGamal == nil || body.Mod == nil || body.Prm == nil {
+		return round.ErrNilFields
+	}
+	if body.ElGamal.IsIdentity() {
+		return errors.New("ElGamal public key is identity")
+	}
+	if err := paillier.ValidateN(body.N); err != nil {
+		return err
+	}
+	if err := pedersen.ValidateParameters(body.N, body.S, body.T); err != nil {
+		return err
+	}
+	if !body.Mod.Verify(zkmod.Public{N: body.N}, r.HashForID(r.Target), r.Pool) {
+		return errors.New("failed to validate mod proof")
+	}
+	if !body.Prm.Verify(zkprm.Public{N: body.N, S: body.S, T: body.T}, r.HashForID(r.Target), r.Pool) {
+		return errors.New("failed to validate prm proof")
+	}
+
+	r.TargetPaillier = paillier.NewPublicKey(body.N)
+	r.TargetPedersen = pedersen.New(r.TargetPaillier.Modulus(), body.S, body.T)
+	r.TargetElGamal = body.ElGamal
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// Helper i:
+//   - compute zᵢ = ∑ⱼ sⱼᵢ
+//   - broadcast Encₜ(zᵢ).
+//
+// Target:
+//   - verify that all helpers sent the same public data, containing X
+//   - verify ∑ₗ Sⱼₗ = λⱼ(t)⋅Xⱼ for all helpers j.
+func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if r.Config != nil {
+		// zᵢ = ∑ⱼ sⱼᵢ
+		Share := r.Group().NewScalar()
+		for _, j := range r.Helpers {
+			Share.Add(r.Masks[j])
+		}
+		ciphertext, _ := r.TargetPaillier.Enc(r.Rand(), curve.MakeInt(Share))
+		if err := r.BroadcastMessage(out, &broadcast3{Share: ciphertext}); err != nil {
+			return r, err
+		}
+		return &round3{round2: r}, nil
+	}
+
+	PublicData := r.PublicData[r.Helpers[0]]
+	for _, j := range r.Helpers {
+		if !bytes.Equal(r.PublicData[j], PublicData) {
+			return r.AbortRound(errors.New("helpers sent different public data")), nil
+		}
+	}
+	Public, err := config.UnmarshalPublic(r.Group(), PublicData)
+	if err != nil {
+		return r.AbortRound(err), nil
+	}
+	if !Public.PublicPoint().Equal(r.PublicKey) {
+		return r.AbortRound(errors.New("helpers sent a different public key")), nil
+	}
+	if !config.ValidThreshold(Public.Threshold, len(r.Helpers)) {
+		return r.AbortRound(errors.New("not enough helpers")), nil
+	}
+	if _, ok := Public.Public[r.SelfID()]; !ok {
+		return r.AbortRound(errors.New("public data does not contain our share")), nil
+	}
+	for _, j := range r.Helpers {
+		if err = verifyCommitments(Public, r.Helpers, r.Target, j, r.Commitments[j]); err != nil {
+			return r.AbortRound(err, j), nil
+		}
+	}
+
+	if err = r.BroadcastMessage(out, &broadcast3{}); err != nil {
+		return r, err
+	}
+	return &round3{
+		round2:       r,
+		PublicConfig: Public,
+		Shares:       map[party.ID]curve.Scalar{},
+	}, nil
+}
+
+// verifyCommitments checks that the masks of helper j sum to its contribution ∑ₗ Sⱼₗ = λⱼ(t)⋅Xⱼ.
+func verifyCommitments(c *config.Config, helpers party.IDSlice, target, j party.ID, commitments map[party.ID]curve.Point) error {
+	public, ok := c.Public[j]
+	if !ok {
+		return errors.New("helper does not hold a share")
+	}
+	group := c.Group
+	lagrange := polynomial.LagrangeAt(group, helpers, target.Scalar(group))
+	sum := group.NewPoint()
+	for _, l := range helpers {
+		sum = sum.Add(commitments[l])
+	}
+	if !sum.Equal(lagrange[j].Act(public.ECDSA)) {
+		return errors.New("mask commitments do not match public share")
+	}
+	return nil
+}
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2{
+		Commitments: party.EmptyPointMap(r.Group()),
+		Parameters:  &parameters{ElGamal: r.Group().NewPoint()},
+	}
+}
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
@@ -0,0 +1,152 @@
+package recovery
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+var _ round.Round = (*round3)(nil)
+
+type round3 struct {
+	*round2
+
+	// PublicConfig contains the public data sent by the helpers.
+	// Only set for the Target.
+	PublicConfig *config.Config
+
+	// Shares[j] = zⱼ = ∑ₗ sₗⱼ is the share of xₜ received from helper j.
+	// Only set for the Target.
+	Shares map[party.ID]curve.Scalar
+}
+
+type broadcast3 struct {
+	round.NormalBroadcastContent
+	// Share = Encₜ(zᵢ)
+	Share *paillier.Ciphertext
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// Target:
+//   - decrypt zⱼ and verify zⱼ⋅G = ∑ₗ Sₗⱼ
+//   - store zⱼ.
+func (r *round3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	// the Target does not send a share, and helpers cannot decrypt the shares.
+	if from == r.Target || r.Config != nil {
+		return nil
+	}
+
+	if body.Share == nil {
+		return round.ErrNilFields
+	}
+	if !r.TargetPaillier.ValidateCiphertexts(body.Share) {
+		return errors.New("invalid share ciphertext")
+	}
+	DecryptedShare, err := r.PaillierSecret.Dec(body.Share)
+	if err != nil {
+		return err
+	}
+	Share := r.Group().NewScalar().SetNat(DecryptedShare.Mod(r.Group().Order()))
+	if DecryptedShare.Eq(curve.MakeInt(Share)) != 1 {
+		return errors.New("decrypted share is not in the correct range")
+	}
+
+	// ∑ₗ Sₗⱼ
+	ExpectedPublicShare := r.Group().NewPoint()
+	for _, l := range r.Helpers {
+		ExpectedPublicShare = ExpectedPublicShare.Add(r.Commitments[l][from])
+	}
+	if !Share.ActOnBase().Equal(ExpectedPublicShare) {
+		return errors.New("share does not match mask commitments")
+	}
+
+	r.Shares[from] = Share
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round3) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round3) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// Target:
+//   - compute xₜ = ∑ⱼ zⱼ and verify xₜ⋅G = Xₜ
+//   - return the recovered config.
+//
+// Helper:
+//   - return the config where the public parameters of the Target are replaced.
+func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
+	if r.Config != nil {
+		return r.ResultRound(r.updatedConfig(r.Config)), nil
+	}
+
+	// xₜ = ∑ⱼ zⱼ
+	ECDSA := r.Group().NewScalar()
+	for _, j := range r.Helpers {
+		ECDSA.Add(r.Shares[j])
+	}
+	if !ECDSA.ActOnBase().Equal(r.PublicConfig.Public[r.SelfID()].ECDSA) {
+		return r.AbortRound(errors.New("recovered share does not match public share")), nil
+	}
+
+	c := r.updatedConfig(r.PublicConfig)
+	c.ID = r.SelfID()
+	c.ECDSA = ECDSA
+	c.ElGamal = r.ElGamalSecret
+	c.Paillier = r.PaillierSecret
+	return r.ResultRound(c), nil
+}
+
+// updatedConfig returns a copy of c, where the Target's Paillier, Pedersen and ElGamal public keys are replaced.
+func (r *round3) updatedConfig(c *config.Config) *config.Config {
+	Public := make(map[party.ID]*config.Public, len(c.Public))
+	for j, public := range c.Public {
+		Public[j] = public
+	}
+	Public[r.Target] = &config.Public{
+		ECDSA:    c.Public[r.Target].ECDSA,
+		ElGamal:  r.TargetElGamal,
+		Paillier: r.TargetPaillier,
+		Pedersen: r.TargetPedersen,
+	}
+	return &config.Config{
+		Group:             c.Group,
+		ID:                c.ID,
+		Threshold:         c.Threshold,
+		ECDSA:             c.ECDSA,
+		ElGamal:           c.ElGamal,
+		Paillier:          c.Paillier,
+		RID:               c.RID,
+		ChainKey:          c.ChainKey,
+		Depth:             c.Depth,
+		ParentFingerprint: c.ParentFingerprint,
+		ChildNumber:       c.ChildNumber,
+		Public:            Public,
+	}
+}
+
+// RoundNumber implements round.Content.
+func (broadcast3) RoundNumber() round.Number { return 3 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (round3) BroadcastContent() round.BroadcastContent { return &broadcast3{} }
+
+// MessageContent implements round.Round.
+func (round3) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round3) Number() round.Number { return 3 }
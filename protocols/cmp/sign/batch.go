@@ -0,0 +1,385 @@
+package sign
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	"github.com/w3-key/mps-lean/protocols/cmp/config"
+)
+
+var (
+	_ round.Round          = (*batchRound)(nil)
+	_ round.BroadcastRound = (*batchBroadcastRound)(nil)
+)
+
+// protocolSignBatchID runs one signing session for each message, sending the messages of all sessions together.
+const protocolSignBatchID = "cmp/sign-batch"
+
+// BatchError is returned when some messages of a batch could not be signed.
+type BatchError struct {
+	// Signatures[i] is the signature of the i-th message, or nil if it could not be signed.
+	Signatures []*ecdsa.Signature
+	// Errors[i] is the reason the i-th message could not be signed.
+	Errors map[int]error
+}
+
+// Error implements error.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("sign: failed to sign %d of %d messages", len(e.Errors), len(e.Signatures))
+}
+
+// StartSignBatch returns a protocol.StartFunc for signing all messages in a single session.
+//
+// Every message is signed by its own instance of the signing protocol, but the messages of all instances
+// for a given round are sent together.
+// This only saves round trips: each message still needs its own Paillier encryptions and proofs.
+// Returns []*ecdsa.Signature if successful. If some messages could not be signed, the protocol aborts
+// with a *BatchError which contains the signatures of the other messages.
+// An invalid message for one instance only aborts that instance, and is reported under its index.
+func StartSignBatch(config *config.Config, signers []party.ID, messages [][]byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if len(messages) == 0 {
+			return nil, errors.New("sign.Batch: no messages")
+		}
+
+		info := round.Info{
+			ProtocolID:       protocolSignBatchID,
+			FinalRoundNumber: protocolSignRounds,
+			SelfID:           config.ID,
+			PartyIDs:         signers,
+			Threshold:        config.Threshold,
+			Group:            config.Group,
+		}
+		info.Apply(opts...)
+
+		auxInfo := make([]hash.WriterToWithDomain, 0, len(messages)+1)
+		auxInfo = append(auxInfo, config)
+		for i, message := range messages {
+			if len(message) == 0 {
+				return nil, fmt.Errorf("sign.Batch: message %d is nil", i)
+			}
+			auxInfo = append(auxInfo, types.SigningMessage(message))
+		}
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
+		if err != nil {
+			return nil, fmt.Errorf("sign.Batch: %w", err)
+		}
+
+		// each session is bound to the batch and its position in it,
+		// and has its own randomness since the sessions are not finalized in a fixed order.
+		itemInfo := info
+		itemInfo.ProtocolID = protocolSignID
+		readers := sample.Fork(helper.Rand(), len(messages))
+		items := make([]round.Session, len(messages))
+		for i, message := range messages {
+			itemInfo.Rand = readers[i]
+			index := make([]byte, 4)
+			binary.BigEndian.PutUint32(index, uint32(i))
+			itemHelper, err := round.NewSession(itemInfo, helper.SSID(), pl, config, types.SigningMessage(message),
+				&hash.BytesWithDomain{TheDomain: "Batch Index", Bytes: index})
+			if err != nil {
+				return nil, fmt.Errorf("sign.Batch: %w", err)
+			}
+			if items[i], err = newRound1(itemHelper, config, message, nil); err != nil {
+				return nil, err
+			}
+		}
+
+		return newBatchRound(helper, items, 1, map[round.Number]*batchOutgoing{}), nil
+	}
+}
+
+// batchRound runs one round of every signing session in the batch.
+//
+// Sessions may skip rounds when they identify culprits, so only the sessions whose round
+// has the same number as the batch receive messages and are finalized.
+type batchRound struct {
+	*round.Helper
+
+	// Items[i] is the current round of the session signing the i-th message.
+	Items []round.Session
+
+	// Outgoing[n] contains the messages the sessions send in round n.
+	Outgoing map[round.Number]*batchOutgoing
+
+	number round.Number
+}
+
+// batchBroadcastRound is a batchRound for which at least one session expects a broadcast message.
+type batchBroadcastRound struct {
+	*batchRound
+}
+
+// batchOutgoing contains the encoded messages of each session for a given round.
+type batchOutgoing struct {
+	Broadcast []cbor.RawMessage
+	Messages  map[party.ID][]cbor.RawMessage
+}
+
+type batchMessage struct {
+	Number round.Number
+	// Items[i] is the encoded content sent by the i-th session, or nil if it does not send anything.
+	Items []cbor.RawMessage
+}
+
+type batchBroadcast struct {
+	round.ReliableBroadcastContent
+	Number round.Number
+	// Items[i] is the encoded content broadcast by the i-th session, or nil if it does not send anything.
+	Items []cbor.RawMessage
+}
+
+// newBatchRound returns the batch round with the given number, or the result once all sessions are finished.
+func newBatchRound(helper *round.Helper, items []round.Session, number round.Number, outgoing map[round.Number]*batchOutgoing) round.Session {
+	r := &batchRound{
+		Helper:   helper,
+		Items:    items,
+		Outgoing: outgoing,
+		number:   number,
+	}
+	if !r.finished() {
+		for _, item := range r.current() {
+			if _, ok := item.(round.BroadcastRound); ok {
+				return &batchBroadcastRound{r}
+			}
+		}
+		return r
+	}
+
+	var culprits party.IDSlice
+	signatures := make([]*ecdsa.Signature, len(items))
+	errs := map[int]error{}
+	for i, item := range items {
+		switch R := item.(type) {
+		case *round.Output:
+			signatures[i] = R.Result.(*ecdsa.Signature)
+		case *round.Abort:
+			errs[i] = protocol.Error{Culprits: R.Culprits, Err: R.Err}
+			for _, j := range R.Culprits {
+				if !culprits.Contains(j) {
+					culprits = party.NewIDSlice(append(culprits, j))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return helper.AbortRound(&BatchError{Signatures: signatures, Errors: errs}, culprits...)
+	}
+	return helper.ResultRound(signatures)
+}
+
+// finished returns true if all sessions have either produced a signature or aborted.
+func (r *batchRound) finished() bool {
+	for _, item := range r.Items {
+		switch item.(type) {
+		case *round.Output, *round.Abort:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// current returns the sessions taking part in this round, indexed by their position in the batch.
+func (r *batchRound) current() map[int]round.Session {
+	items := make(map[int]round.Session, len(r.Items))
+	for i, item := range r.Items {
+		if item.Number() == r.number {
+			items[i] = item
+		}
+	}
+	return items
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify the message of each session, aborting only the sessions whose message is invalid.
+func (r *batchRound) VerifyMessage(msg round.Message) error {
+	return r.forEachMessage(msg, func(item round.Session, itemMsg round.Message) error {
+		return item.VerifyMessage(itemMsg)
+	})
+}
+
+// StoreMessage implements round.Round.
+//
+// - store the message of each session, aborting only the sessions whose message is invalid.
+func (r *batchRound) StoreMessage(msg round.Message) error {
+	return r.forEachMessage(msg, func(item round.Session, itemMsg round.Message) error {
+		return item.StoreMessage(itemMsg)
+	})
+}
+
+func (r *batchRound) forEachMessage(msg round.Message, f func(round.Session, round.Message) error) error {
+	body, ok := msg.Content.(*batchMessage)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if len(body.Items) != len(r.Items) {
+		return errors.New("invalid number of messages")
+	}
+	for i, item := range r.current() {
+		content := item.MessageContent()
+		if content == nil {
+			continue
+		}
+		if err := decodeBatchItem(body.Items[i], content); err != nil {
+			r.abortItem(i, err, msg.From)
+			continue
+		}
+		if err := f(item, round.Message{From: msg.From, To: msg.To, Content: content}); err != nil {
+			r.abortItem(i, err, msg.From)
+		}
+	}
+	return nil
+}
+
+// abortItem stops the session signing the i-th message, blaming culprit, while the other sessions continue.
+func (r *batchRound) abortItem(i int, err error, culprit party.ID) {
+	r.Items[i] = r.AbortRound(fmt.Errorf("message %d: %w", i, err), culprit)
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - store the broadcast message of each session, aborting only the sessions whose message is invalid.
+func (r *batchBroadcastRound) StoreBroadcastMessage(msg round.Message) error {
+	body, ok := msg.Content.(*batchBroadcast)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if len(body.Items) != len(r.Items) {
+		return errors.New("invalid number of messages")
+	}
+	for i, item := range r.current() {
+		b, ok := item.(round.BroadcastRound)
+		if !ok {
+			continue
+		}
+		content := b.BroadcastContent()
+		if err := decodeBatchItem(body.Items[i], content); err != nil {
+			r.abortItem(i, err, msg.From)
+			continue
+		}
+		if err := b.StoreBroadcastMessage(round.Message{From: msg.From, Broadcast: true, Content: content}); err != nil {
+			r.abortItem(i, err, msg.From)
+		}
+	}
+	return nil
+}
+
+func decodeBatchItem(data cbor.RawMessage, content round.Content) error {
+	if len(data) == 0 {
+		return round.ErrNilFields
+	}
+	return cbor.Unmarshal(data, content)
+}
+
+// Finalize implements round.Round
+//
+// - finalize each session taking part in this round
+// - send the messages of all sessions for the next round together.
+func (r *batchRound) Finalize(out chan<- *round.Message) (round.Session, error) {
+	items := make([]round.Session, len(r.Items))
+	copy(items, r.Items)
+	for i, item := range r.current() {
+		itemOut := make(chan *round.Message, r.N()+1)
+		next, err := item.Finalize(itemOut)
+		close(itemOut)
+		if err != nil {
+			return r, fmt.Errorf("message %d: %w", i, err)
+		}
+		items[i] = next
+		for msg := range itemOut {
+			if err = r.queue(i, msg); err != nil {
+				return r, err
+			}
+		}
+	}
+
+	// the next round is the earliest one in which a session is still running
+	var number round.Number
+	for _, item := range items {
+		if n := item.Number(); n != 0 && (number == 0 || n < number) {
+			number = n
+		}
+	}
+
+	if outgoing, ok := r.Outgoing[number]; ok {
+		delete(r.Outgoing, number)
+		if outgoing.Broadcast != nil {
+			if err := r.BroadcastMessage(out, &batchBroadcast{Number: number, Items: outgoing.Broadcast}); err != nil {
+				return r, err
+			}
+		}
+		for j, contents := range outgoing.Messages {
+			if err := r.SendMessage(out, &batchMessage{Number: number, Items: contents}, j); err != nil {
+				return r, err
+			}
+		}
+	}
+
+	return newBatchRound(r.Helper, items, number, r.Outgoing), nil
+}
+
+// queue encodes the message sent by the i-th session, to be sent in the round it is intended for.
+func (r *batchRound) queue(i int, msg *round.Message) error {
+	data, err := cbor.Marshal(msg.Content)
+	if err != nil {
+		return err
+	}
+	number := msg.Content.RoundNumber()
+	outgoing, ok := r.Outgoing[number]
+	if !ok {
+		outgoing = &batchOutgoing{Messages: map[party.ID][]cbor.RawMessage{}}
+		r.Outgoing[number] = outgoing
+	}
+	if msg.Broadcast {
+		if outgoing.Broadcast == nil {
+			outgoing.Broadcast = make([]cbor.RawMessage, len(r.Items))
+		}
+		outgoing.Broadcast[i] = data
+		return nil
+	}
+	to := party.IDSlice{msg.To}
+	if msg.To == "" {
+		to = r.OtherPartyIDs()
+	}
+	for _, j := range to {
+		if outgoing.Messages[j] == nil {
+			outgoing.Messages[j] = make([]cbor.RawMessage, len(r.Items))
+		}
+		outgoing.Messages[j][i] = data
+	}
+	return nil
+}
+
+// MessageContent implements round.Round.
+func (r *batchRound) MessageContent() round.Content {
+	for _, item := range r.current() {
+		if item.MessageContent() != nil {
+			return &batchMessage{}
+		}
+	}
+	return nil
+}
+
+// BroadcastContent implements round.BroadcastRound.
+func (batchBroadcastRound) BroadcastContent() round.BroadcastContent { return &batchBroadcast{} }
+
+// RoundNumber implements round.Content.
+func (m batchMessage) RoundNumber() round.Number { return m.Number }
+
+// RoundNumber implements round.Content.
+func (m batchBroadcast) RoundNumber() round.Number { return m.Number }
+
+// Number implements round.Round.
+func (r *batchRound) Number() round.Number { return r.number }
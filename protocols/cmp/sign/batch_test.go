@@ -0,0 +1,237 @@
+package sign
+
+import (
+	"errors"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"golang.org/x/crypto/sha3"
+)
+
+func batchMessages(n int) [][]byte {
+	messages := make([][]byte, n)
+	for i := range messages {
+		messages[i] = make([]byte, 32)
+		sha3.ShakeSum128(messages[i], []byte{byte(i)})
+	}
+	return messages
+}
+
+func TestSignBatch(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	partyIDs = partyIDs[:T+1]
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messages := batchMessages(3)
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, partyID := range partyIDs {
+		r, err := StartSignBatch(configs[partyID], partyIDs, messages, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		resultRound := r.(*round.Output)
+		require.IsType(t, []*ecdsa.Signature{}, resultRound.Result, "expected signatures result")
+		signatures := resultRound.Result.([]*ecdsa.Signature)
+		require.Len(t, signatures, len(messages))
+		for i, signature := range signatures {
+			assert.True(t, signature.Verify(publicPoint, messages[i]), "expected valid signature")
+		}
+	}
+}
+
+// batchRule applies rule to the session signing the message at index.
+type batchRule struct {
+	rule  test.Rule
+	index int
+}
+
+func (r *batchRule) ModifyBefore(rPrevious round.Session) {
+	if b, ok := batchOf(rPrevious); ok {
+		r.rule.ModifyBefore(b.Items[r.index])
+	}
+}
+
+func (r *batchRule) ModifyAfter(rNext round.Session) {
+	if b, ok := batchOf(rNext); ok {
+		r.rule.ModifyAfter(b.Items[r.index])
+	}
+}
+
+func (r *batchRule) ModifyContent(rNext round.Session, to party.ID, content round.Content) {
+	b, ok := batchOf(rNext)
+	if !ok {
+		return
+	}
+	item := b.Items[r.index]
+	var items []cbor.RawMessage
+	var itemContent round.Content
+	switch body := content.(type) {
+	case *batchBroadcast:
+		bItem, ok := item.(round.BroadcastRound)
+		if !ok {
+			return
+		}
+		items, itemContent = body.Items, bItem.BroadcastContent()
+	case *batchMessage:
+		items, itemContent = body.Items, item.MessageContent()
+	}
+	if itemContent == nil || items[r.index] == nil {
+		return
+	}
+	if err := cbor.Unmarshal(items[r.index], itemContent); err != nil {
+		panic(err)
+	}
+	r.rule.ModifyContent(item, to, itemContent)
+	items[r.index], _ = cbor.Marshal(itemContent)
+}
+
+func batchOf(r round.Session) (*batchRound, bool) {
+	switch b := r.(type) {
+	case *batchRound:
+		return b, true
+	case *batchBroadcastRound:
+		return b.batchRound, true
+	}
+	return nil, false
+}
+
+func TestSignBatchIdentifiableAbort(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messages := batchMessages(2)
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSignBatch(configs[partyID], partyIDs, messages, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	culprit := partyIDs[1]
+	rule := &batchRule{rule: &chiRule{culprit: culprit}, index: 1}
+	for {
+		err, done := test.Rounds(rounds, rule)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Abort{}, r, "expected abort round")
+		abort := r.(*round.Abort)
+		var batchErr *BatchError
+		require.True(t, errors.As(abort.Err, &batchErr), "expected batch error")
+		assert.True(t, batchErr.Signatures[0].Verify(publicPoint, messages[0]), "expected valid signature")
+		assert.Nil(t, batchErr.Signatures[1])
+		require.Contains(t, batchErr.Errors, 1)
+		assert.NotContains(t, batchErr.Errors, 0)
+		if r.SelfID() == culprit {
+			continue
+		}
+		assert.Equal(t, []party.ID{culprit}, abort.Culprits, "expected culprit to be identified")
+		var itemErr protocol.Error
+		require.True(t, errors.As(batchErr.Errors[1], &itemErr))
+		assert.Equal(t, []party.ID{culprit}, itemErr.Culprits)
+	}
+}
+
+// malformedItemRule makes culprit send an undecodable message for the session at index in round 2.
+type malformedItemRule struct {
+	culprit party.ID
+	index   int
+}
+
+func (malformedItemRule) ModifyBefore(round.Session) {}
+
+func (malformedItemRule) ModifyAfter(round.Session) {}
+
+func (r *malformedItemRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	body, ok := content.(*batchMessage)
+	if !ok || rNext.SelfID() != r.culprit || body.Number != 2 {
+		return
+	}
+	body.Items[r.index] = cbor.RawMessage{0x01}
+}
+
+func TestSignBatchMalformedItem(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	publicPoint := configs[partyIDs[0]].PublicPoint()
+
+	messages := batchMessages(2)
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSignBatch(configs[partyID], partyIDs, messages, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	culprit := partyIDs[1]
+	rule := &malformedItemRule{culprit: culprit, index: 1}
+	for {
+		err, done := test.Rounds(rounds, rule)
+		require.NoError(t, err, "a malformed message should not fail the whole batch")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		if r.SelfID() == culprit {
+			continue
+		}
+		require.IsType(t, &round.Abort{}, r, "expected abort round")
+		abort := r.(*round.Abort)
+		var batchErr *BatchError
+		require.True(t, errors.As(abort.Err, &batchErr), "expected batch error")
+		assert.True(t, batchErr.Signatures[0].Verify(publicPoint, messages[0]), "expected valid signature")
+		assert.Nil(t, batchErr.Signatures[1])
+		require.Contains(t, batchErr.Errors, 1)
+		assert.NotContains(t, batchErr.Errors, 0)
+		var itemErr protocol.Error
+		require.True(t, errors.As(batchErr.Errors[1], &itemErr))
+		assert.Equal(t, []party.ID{culprit}, itemErr.Culprits)
+	}
+}
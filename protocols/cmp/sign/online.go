@@ -0,0 +1,134 @@
+package sign
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/types"
+)
+
+// SignatureShare is a single party's contribution to a signature created from a PreSignature.
+// It contains no secret material and can be sent to whoever combines the shares.
+type SignatureShare struct {
+	// ID of the PreSignature which was consumed to create this share.
+	ID types.RID
+	// R = k⁻¹⋅G
+	R curve.Point
+	// RBar[j] = (k⁻¹kⱼ)⋅G
+	RBar *party.PointMap
+	// S[j] = χⱼ⋅R
+	S *party.PointMap
+	// Sigma = σᵢ = kᵢm + rχᵢ
+	Sigma curve.Scalar
+}
+
+// EmptySignatureShare returns a SignatureShare with a given group, ready for unmarshalling.
+func EmptySignatureShare(group curve.Curve) *SignatureShare {
+	return &SignatureShare{
+		R:     group.NewPoint(),
+		RBar:  party.EmptyPointMap(group),
+		S:     party.EmptyPointMap(group),
+		Sigma: group.NewScalar(),
+	}
+}
+
+// SingleSign consumes the PreSignature with the given id from store, and returns this party's
+// share of the signature for messageHash.
+//
+// The PreSignature is removed from the store before the share is computed,
+// so any further attempt to sign with the same id fails with ErrPreSignatureUsed.
+func SingleSign(store PreSignatureStore, id types.RID, messageHash []byte) (*SignatureShare, error) {
+	if len(messageHash) == 0 {
+		return nil, errors.New("sign: message is nil")
+	}
+	preSignature, err := store.Take(id)
+	if err != nil {
+		return nil, err
+	}
+	if err = preSignature.Validate(); err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	return &SignatureShare{
+		ID:    preSignature.ID,
+		R:     preSignature.R,
+		RBar:  preSignature.RBar,
+		S:     preSignature.S,
+		Sigma: preSignature.SignatureShare(messageHash),
+	}, nil
+}
+
+// CombineShares returns the signature on messageHash obtained by adding the shares of all signers.
+//
+// All shares must have been created from the same PreSignature, and must carry identical RBar and S,
+// since these are used to identify the parties with invalid shares.
+// If the resulting signature is not valid for publicKey, a protocol.Error is returned listing them.
+func CombineShares(publicKey curve.Point, messageHash []byte, shares map[party.ID]*SignatureShare) (*ecdsa.Signature, error) {
+	var reference *SignatureShare
+	for _, share := range shares {
+		if share == nil || share.R == nil || share.Sigma == nil || share.RBar == nil || share.S == nil {
+			return nil, errors.New("sign: nil signature share")
+		}
+		if reference == nil {
+			reference = share
+			continue
+		}
+		if !bytes.Equal(reference.ID, share.ID) || !reference.R.Equal(share.R) {
+			return nil, errors.New("sign: signature shares come from different presignatures")
+		}
+		if !equalPointMaps(reference.RBar, share.RBar) || !equalPointMaps(reference.S, share.S) {
+			return nil, errors.New("sign: signature shares disagree on RBar or S")
+		}
+	}
+	if reference == nil {
+		return nil, errors.New("sign: no signature shares")
+	}
+
+	preSignature := &ecdsa.PreSignature{
+		ID:   reference.ID,
+		R:    reference.R,
+		RBar: reference.RBar,
+		S:    reference.S,
+	}
+	signerIDs := preSignature.SignerIDs()
+	if len(shares) != len(signerIDs) {
+		return nil, errors.New("sign: missing signature shares")
+	}
+	for j := range shares {
+		if !signerIDs.Contains(j) {
+			return nil, fmt.Errorf("sign: signature share from unexpected party %s", j)
+		}
+	}
+
+	sigmas := make(map[party.ID]ecdsa.SignatureShare, len(shares))
+	for j, share := range shares {
+		sigmas[j] = share.Sigma
+	}
+
+	signature := preSignature.Signature(sigmas)
+	if !signature.Verify(publicKey, messageHash) {
+		return nil, protocol.Error{
+			Culprits: preSignature.VerifySignatureShares(sigmas, messageHash),
+			Err:      errors.New("sign: failed to validate signature"),
+		}
+	}
+	return signature, nil
+}
+
+// equalPointMaps returns true if a and b contain the same points for the same parties.
+func equalPointMaps(a, b *party.PointMap) bool {
+	if len(a.Points) != len(b.Points) {
+		return false
+	}
+	for j, p := range a.Points {
+		q, ok := b.Points[j]
+		if !ok || p == nil || q == nil || !p.Equal(q) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,250 @@
+package sign
+
+import (
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/pkg/types"
+	"golang.org/x/crypto/sha3"
+)
+
+// dealPreSignatures generates consistent PreSignatures for partyIDs, as the presign protocol would.
+func dealPreSignatures(t *testing.T, group curve.Curve, partyIDs []party.ID) (curve.Point, map[party.ID]*ecdsa.PreSignature) {
+	secret, publicKey := sample.ScalarPointPair(rand.Reader, group)
+	id, err := types.NewRID(rand.Reader)
+	require.NoError(t, err)
+
+	k := group.NewScalar()
+	kShares := make(map[party.ID]curve.Scalar, len(partyIDs))
+	chiShares := make(map[party.ID]curve.Scalar, len(partyIDs))
+	chi := group.NewScalar()
+	for i, j := range partyIDs {
+		kShares[j] = sample.Scalar(rand.Reader, group)
+		k.Add(kShares[j])
+		if i < len(partyIDs)-1 {
+			chiShares[j] = sample.Scalar(rand.Reader, group)
+			chi.Add(chiShares[j])
+		}
+	}
+	// χ = ∑ⱼ χⱼ = k⋅x
+	last := partyIDs[len(partyIDs)-1]
+	chiShares[last] = group.NewScalar().Set(k).Mul(secret).Sub(chi)
+
+	kInv := group.NewScalar().Set(k).Invert()
+	R := kInv.ActOnBase()
+	RBar := make(map[party.ID]curve.Point, len(partyIDs))
+	S := make(map[party.ID]curve.Point, len(partyIDs))
+	for _, j := range partyIDs {
+		RBar[j] = group.NewScalar().Set(kInv).Mul(kShares[j]).ActOnBase()
+		S[j] = chiShares[j].Act(R)
+	}
+
+	preSignatures := make(map[party.ID]*ecdsa.PreSignature, len(partyIDs))
+	for _, j := range partyIDs {
+		preSignatures[j] = &ecdsa.PreSignature{
+			ID:       id.Copy(),
+			R:        R,
+			RBar:     party.NewPointMap(RBar),
+			S:        party.NewPointMap(S),
+			KShare:   kShares[j],
+			ChiShare: chiShares[j],
+		}
+	}
+	return publicKey, preSignatures
+}
+
+func newStores(t *testing.T, group curve.Curve, partyIDs []party.ID) map[string]map[party.ID]PreSignatureStore {
+	memory := make(map[party.ID]PreSignatureStore, len(partyIDs))
+	file := make(map[party.ID]PreSignatureStore, len(partyIDs))
+	for _, j := range partyIDs {
+		memory[j] = NewMemoryStore()
+		s, err := NewFileStore(t.TempDir(), group)
+		require.NoError(t, err)
+		file[j] = s
+	}
+	return map[string]map[party.ID]PreSignatureStore{"memory": memory, "file": file}
+}
+
+func TestSingleSign(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(3)
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+	otherHash := make([]byte, 32)
+	sha3.ShakeSum128(otherHash, []byte("world"))
+
+	for name, stores := range newStores(t, group, partyIDs) {
+		t.Run(name, func(t *testing.T) {
+			publicKey, preSignatures := dealPreSignatures(t, group, partyIDs)
+			var id types.RID
+			for _, j := range partyIDs {
+				require.NoError(t, stores[j].Put(preSignatures[j]))
+				assert.ErrorIs(t, stores[j].Put(preSignatures[j]), ErrPreSignatureExists)
+				id = preSignatures[j].ID
+			}
+
+			shares := make(map[party.ID]*SignatureShare, len(partyIDs))
+			for _, j := range partyIDs {
+				share, err := SingleSign(stores[j], id, messageHash)
+				require.NoError(t, err)
+				shares[j] = share
+
+				_, err = SingleSign(stores[j], id, otherHash)
+				assert.ErrorIs(t, err, ErrPreSignatureUsed, "presignature must not be reused")
+				assert.ErrorIs(t, stores[j].Put(preSignatures[j]), ErrPreSignatureUsed, "consumed presignature must not be stored again")
+			}
+
+			signature, err := CombineShares(publicKey, messageHash, shares)
+			require.NoError(t, err)
+			assert.True(t, signature.Verify(publicKey, messageHash))
+
+			_, err = SingleSign(stores[partyIDs[0]], types.EmptyRID(), messageHash)
+			assert.ErrorIs(t, err, ErrPreSignatureNotFound)
+		})
+	}
+}
+
+func TestCombineSharesCulprit(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(3)
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	publicKey, preSignatures := dealPreSignatures(t, group, partyIDs)
+	shares := make(map[party.ID]*SignatureShare, len(partyIDs))
+	for _, j := range partyIDs {
+		store := NewMemoryStore()
+		require.NoError(t, store.Put(preSignatures[j]))
+		share, err := SingleSign(store, preSignatures[j].ID, messageHash)
+		require.NoError(t, err)
+		shares[j] = share
+	}
+	culprit := partyIDs[1]
+	shares[culprit].Sigma = group.NewScalar().Set(shares[culprit].Sigma).Add(sample.Scalar(rand.Reader, group))
+
+	_, err := CombineShares(publicKey, messageHash, shares)
+	var protocolErr protocol.Error
+	require.True(t, errors.As(err, &protocolErr))
+	assert.Equal(t, []party.ID{culprit}, protocolErr.Culprits)
+}
+
+func TestStoreConcurrentTake(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(2)
+	_, preSignatures := dealPreSignatures(t, group, partyIDs)
+
+	for name, stores := range newStores(t, group, partyIDs) {
+		t.Run(name, func(t *testing.T) {
+			store := stores[partyIDs[0]]
+			preSignature := preSignatures[partyIDs[0]]
+			require.NoError(t, store.Put(preSignature))
+
+			const attempts = 16
+			var wg sync.WaitGroup
+			results := make(chan error, attempts)
+			for i := 0; i < attempts; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := store.Take(preSignature.ID)
+					results <- err
+				}()
+			}
+			wg.Wait()
+			close(results)
+
+			successes := 0
+			for err := range results {
+				if err == nil {
+					successes++
+				} else {
+					assert.ErrorIs(t, err, ErrPreSignatureUsed)
+				}
+			}
+			assert.Equal(t, 1, successes, "presignature must be taken exactly once")
+		})
+	}
+}
+
+func TestCombineSharesInconsistent(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(3)
+
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	publicKey, preSignatures := dealPreSignatures(t, group, partyIDs)
+	shares := make(map[party.ID]*SignatureShare, len(partyIDs))
+	for _, j := range partyIDs {
+		store := NewMemoryStore()
+		require.NoError(t, store.Put(preSignatures[j]))
+		share, err := SingleSign(store, preSignatures[j].ID, messageHash)
+		require.NoError(t, err)
+		shares[j] = share
+	}
+
+	// a malicious share replaces the S of an honest party, so that its valid σ looks wrong.
+	malicious, honest := partyIDs[0], partyIDs[1]
+	S := make(map[party.ID]curve.Point, len(partyIDs))
+	for j, p := range shares[malicious].S.Points {
+		S[j] = p
+	}
+	S[honest] = sample.Scalar(rand.Reader, group).ActOnBase()
+	shares[malicious].S = party.NewPointMap(S)
+
+	_, err := CombineShares(publicKey, messageHash, shares)
+	require.Error(t, err)
+	var protocolErr protocol.Error
+	assert.False(t, errors.As(err, &protocolErr), "honest parties must not be blamed")
+}
+
+func TestFileStoreConcurrentPutTake(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(2)
+	_, preSignatures := dealPreSignatures(t, group, partyIDs)
+	preSignature := preSignatures[partyIDs[0]]
+
+	store, err := NewFileStore(t.TempDir(), group)
+	require.NoError(t, err)
+	require.NoError(t, store.Put(preSignature))
+
+	const attempts = 16
+	var wg sync.WaitGroup
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := store.Take(preSignature.ID)
+			results <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_ = store.Put(preSignature)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	successes := 0
+	for err := range results {
+		if err == nil {
+			successes++
+		}
+	}
+	assert.Equal(t, 1, successes, "presignature must be taken exactly once")
+	_, err = store.Take(preSignature.ID)
+	assert.ErrorIs(t, err, ErrPreSignatureUsed)
+}
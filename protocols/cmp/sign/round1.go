@@ -1,8 +1,7 @@
 package sign
 
 import (
-	"crypto/rand"
-
+	"github.com/w3-key/mps-lean/pkg/elgamal"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/math/sample"
 	"github.com/w3-key/mps-lean/pkg/paillier"
@@ -24,9 +23,8 @@ type round1 struct {
 	Paillier       map[party.ID]*paillier.PublicKey
 	Pedersen       map[party.ID]*pedersen.Parameters
 	ECDSA          map[party.ID]curve.Point
-	Message []byte
-	JustInfo      bool
-	PublicPoint curve.Point
+	ElGamal        map[party.ID]elgamal.PublicKey
+	Message        []byte
 }
 
 // VerifyMessage implements round.Round.
@@ -52,14 +50,14 @@ func (round1) StoreMessage(round.Message) error { return nil }
 func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// γᵢ <- 𝔽,
 	// Γᵢ = [γᵢ]⋅G
-	GammaShare, BigGammaShare := sample.ScalarPointPair(rand.Reader, r.Group())
+	GammaShare, BigGammaShare := sample.ScalarPointPair(r.Rand(), r.Group())
 	// Gᵢ = Encᵢ(γᵢ;νᵢ)
-	G, GNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(GammaShare))
+	G, GNonce := r.Paillier[r.SelfID()].Enc(r.Rand(), curve.MakeInt(GammaShare))
 
 	// kᵢ <- 𝔽,
-	KShare := sample.Scalar(rand.Reader, r.Group())
+	KShare := sample.Scalar(r.Rand(), r.Group())
 	// Kᵢ = Encᵢ(kᵢ;ρᵢ)
-	K, KNonce := r.Paillier[r.SelfID()].Enc(curve.MakeInt(KShare))
+	K, KNonce := r.Paillier[r.SelfID()].Enc(r.Rand(), curve.MakeInt(KShare))
 
 	otherIDs := r.OtherPartyIDs()
 	broadcastMsg := broadcast2{K: K, G: G}
@@ -67,9 +65,10 @@ func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
 		return r, err
 	}
 
+	readers := sample.Fork(r.Rand(), len(otherIDs))
 	errors := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
-		proof := zkenc.NewProof(r.Group(), r.HashForID(r.SelfID()), zkenc.Public{
+		proof := zkenc.NewProof(readers[i], r.Group(), r.HashForID(r.SelfID()), zkenc.Public{
 			K:      K,
 			Prover: r.Paillier[r.SelfID()],
 			Aux:    r.Pedersen[j],
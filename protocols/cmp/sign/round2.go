@@ -5,6 +5,7 @@ import (
 
 	"github.com/cronokirby/saferith"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
 	"github.com/w3-key/mps-lean/pkg/mta"
 	"github.com/w3-key/mps-lean/pkg/paillier"
 	"github.com/w3-key/mps-lean/pkg/party"
@@ -103,29 +104,26 @@ func (round2) StoreMessage(round.Message) error { return nil }
 // Finalize implements round.Round
 //
 // - compute Hash(ssid, K₁, G₁, …, Kₙ, Gₙ).
+// - send the MtA ciphertexts to each party, and broadcast all Dᵢⱼ, D̂ᵢⱼ with Γᵢ.
 func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
-	if err := r.BroadcastMessage(out, &broadcast3{
-		BigGammaShare: r.BigGammaShare[r.SelfID()],
-	}); err != nil {
-		return r, err
-	}
-
 	otherIDs := r.OtherPartyIDs()
 	type mtaOut struct {
-		err       error
-		DeltaBeta *saferith.Int
-		ChiBeta   *saferith.Int
+		msg                        *message3
+		DeltaBeta                  *saferith.Int
+		ChiBeta                    *saferith.Int
+		DeltaD, DeltaF, ChiD, ChiF *paillier.Ciphertext
 	}
+	readers := sample.Fork(r.Rand(), len(otherIDs))
 	mtaOuts := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
-		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffG(r.Group(), r.HashForID(r.SelfID()),
+		DeltaBeta, DeltaD, DeltaF, DeltaProof := mta.ProveAffG(readers[i], r.Group(), r.HashForID(r.SelfID()),
 			r.GammaShare, r.BigGammaShare[r.SelfID()], r.K[j],
 			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
-		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(r.Group(),
+		ChiBeta, ChiD, ChiF, ChiProof := mta.ProveAffG(readers[i], r.Group(),
 			r.HashForID(r.SelfID()), curve.MakeInt(r.SecretECDSA), r.ECDSA[r.SelfID()], r.K[j],
 			r.SecretPaillier, r.Paillier[j], r.Pedersen[j])
 
-		proof := zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()),
+		proof := zklogstar.NewProof(readers[i], r.Group(), r.HashForID(r.SelfID()),
 			zklogstar.Public{
 				C:      r.G[r.SelfID()],
 				X:      r.BigGammaShare[r.SelfID()],
@@ -136,31 +134,48 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 				Rho: r.GNonce,
 			})
 
-		err := r.SendMessage(out, &message3{
-			DeltaD:     DeltaD,
-			DeltaF:     DeltaF,
-			DeltaProof: DeltaProof,
-			ChiD:       ChiD,
-			ChiF:       ChiF,
-			ChiProof:   ChiProof,
-			ProofLog:   proof,
-		}, j)
 		return mtaOut{
-			err:       err,
+			msg: &message3{
+				DeltaD:     DeltaD,
+				DeltaF:     DeltaF,
+				DeltaProof: DeltaProof,
+				ChiD:       ChiD,
+				ChiF:       ChiF,
+				ChiProof:   ChiProof,
+				ProofLog:   proof,
+			},
 			DeltaBeta: DeltaBeta,
 			ChiBeta:   ChiBeta,
+			DeltaD:    DeltaD,
+			DeltaF:    DeltaF,
+			ChiD:      ChiD,
+			ChiF:      ChiF,
 		}
 	})
 	DeltaShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs)-1)
 	ChiShareBetas := make(map[party.ID]*saferith.Int, len(otherIDs)-1)
+	sent := newMtaCiphertexts(len(otherIDs))
 	for idx, mtaOutRaw := range mtaOuts {
 		j := otherIDs[idx]
 		m := mtaOutRaw.(mtaOut)
-		if m.err != nil {
-			return r, m.err
-		}
 		DeltaShareBetas[j] = m.DeltaBeta
 		ChiShareBetas[j] = m.ChiBeta
+		sent.DeltaD[j], sent.DeltaF[j] = m.DeltaD, m.DeltaF
+		sent.ChiD[j], sent.ChiF[j] = m.ChiD, m.ChiF
+	}
+
+	if err := r.BroadcastMessage(out, &broadcast3{
+		BigGammaShare: r.BigGammaShare[r.SelfID()],
+		DeltaD:        sent.DeltaD,
+		ChiD:          sent.ChiD,
+	}); err != nil {
+		return r, err
+	}
+	// the broadcast goes out first, since the messages are checked against it.
+	for idx, mtaOutRaw := range mtaOuts {
+		if err := r.SendMessage(out, mtaOutRaw.(mtaOut).msg, otherIDs[idx]); err != nil {
+			return r, err
+		}
 	}
 
 	return &round3{
@@ -169,6 +184,11 @@ func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
 		ChiShareBeta:    ChiShareBetas,
 		DeltaShareAlpha: map[party.ID]*saferith.Int{},
 		ChiShareAlpha:   map[party.ID]*saferith.Int{},
+		Sent:            sent,
+		Received:        newMtaCiphertexts(len(otherIDs)),
+		BroadcastD: map[party.ID]*mtaCiphertexts{
+			r.SelfID(): {DeltaD: sent.DeltaD, ChiD: sent.ChiD},
+		},
 	}, nil
 }
 
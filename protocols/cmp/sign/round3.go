@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/elgamal"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
 	"github.com/w3-key/mps-lean/pkg/paillier"
 	"github.com/w3-key/mps-lean/pkg/party"
 	"github.com/w3-key/mps-lean/pkg/round"
@@ -26,6 +28,33 @@ type round3 struct {
 	ChiShareAlpha map[party.ID]*saferith.Int
 	// ChiShareBeta[j] = β̂ᵢⱼ
 	ChiShareBeta map[party.ID]*saferith.Int
+
+	// Sent[j] are the MtA ciphertexts sent to party j,
+	// and Received[j] those received from j.
+	// They are only used to identify a culprit if the protocol fails.
+	Sent, Received *mtaCiphertexts
+
+	// BroadcastD[j] contains only the D ciphertexts j sent to every other party,
+	// as broadcast by j in round 3.
+	// Since they are part of the broadcast, all parties agree on them when identifying a culprit.
+	BroadcastD map[party.ID]*mtaCiphertexts
+}
+
+// mtaCiphertexts holds the ciphertexts of both MtA exchanged with each party.
+type mtaCiphertexts struct {
+	// DeltaD[j] = Dᵢⱼ, DeltaF[j] = Fᵢⱼ
+	DeltaD, DeltaF map[party.ID]*paillier.Ciphertext
+	// ChiD[j] = D̂ᵢⱼ, ChiF[j] = F̂ᵢⱼ
+	ChiD, ChiF map[party.ID]*paillier.Ciphertext
+}
+
+func newMtaCiphertexts(n int) *mtaCiphertexts {
+	return &mtaCiphertexts{
+		DeltaD: make(map[party.ID]*paillier.Ciphertext, n),
+		DeltaF: make(map[party.ID]*paillier.Ciphertext, n),
+		ChiD:   make(map[party.ID]*paillier.Ciphertext, n),
+		ChiF:   make(map[party.ID]*paillier.Ciphertext, n),
+	}
 }
 
 type message3 struct {
@@ -41,12 +70,15 @@ type message3 struct {
 type broadcast3 struct {
 	round.NormalBroadcastContent
 	BigGammaShare curve.Point // BigGammaShare = Γⱼ
+	// DeltaD[l] = Dⱼₗ and ChiD[l] = D̂ⱼₗ, the ciphertexts j sent to l.
+	DeltaD, ChiD map[party.ID]*paillier.Ciphertext
 }
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - store Γⱼ
+// - store Γⱼ, and the Dⱼₗ, D̂ⱼₗ sent to all other parties.
 func (r *round3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
 	body, ok := msg.Content.(*broadcast3)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
@@ -54,12 +86,25 @@ func (r *round3) StoreBroadcastMessage(msg round.Message) error {
 	if body.BigGammaShare.IsIdentity() {
 		return round.ErrNilFields
 	}
-	r.BigGammaShare[msg.From] = body.BigGammaShare
+	if len(body.DeltaD) != r.N()-1 || len(body.ChiD) != r.N()-1 {
+		return round.ErrNilFields
+	}
+	for _, l := range r.PartyIDs() {
+		if l == from {
+			continue
+		}
+		if !r.Paillier[l].ValidateCiphertexts(body.DeltaD[l], body.ChiD[l]) {
+			return errors.New("invalid broadcast D")
+		}
+	}
+	r.BigGammaShare[from] = body.BigGammaShare
+	r.BroadcastD[from] = &mtaCiphertexts{DeltaD: body.DeltaD, ChiD: body.ChiD}
 	return nil
 }
 
 // VerifyMessage implements round.Round.
 //
+// - check that Dⱼᵢ, D̂ⱼᵢ are the ones j broadcast.
 // - verify zkproofs affg (2x) zklog*.
 func (r *round3) VerifyMessage(msg round.Message) error {
 	from, to := msg.From, msg.To
@@ -68,6 +113,14 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 		return round.ErrInvalidContent
 	}
 
+	if body.DeltaD == nil || body.ChiD == nil {
+		return round.ErrNilFields
+	}
+	broadcastD := r.BroadcastD[from]
+	if !body.DeltaD.Equal(broadcastD.DeltaD[to]) || !body.ChiD.Equal(broadcastD.ChiD[to]) {
+		return errors.New("D differs from the broadcast one")
+	}
+
 	if !body.DeltaProof.Verify(r.HashForID(from), zkaffg.Public{
 		Kv:       r.K[to],
 		Dv:       body.DeltaD,
@@ -107,7 +160,7 @@ func (r *round3) VerifyMessage(msg round.Message) error {
 // StoreMessage implements round.Round.
 //
 // - Decrypt MtA shares,
-// - save αᵢⱼ, α̂ᵢⱼ.
+// - save αᵢⱼ, α̂ᵢⱼ, and the received ciphertexts.
 func (r *round3) StoreMessage(msg round.Message) error {
 	from, body := msg.From, msg.Content.(*message3)
 
@@ -125,6 +178,9 @@ func (r *round3) StoreMessage(msg round.Message) error {
 	r.DeltaShareAlpha[from] = DeltaShareAlpha
 	r.ChiShareAlpha[from] = ChiShareAlpha
 
+	r.Received.DeltaD[from], r.Received.DeltaF[from] = body.DeltaD, body.DeltaF
+	r.Received.ChiD[from], r.Received.ChiF[from] = body.ChiD, body.ChiF
+
 	return nil
 }
 
@@ -133,7 +189,8 @@ func (r *round3) StoreMessage(msg round.Message) error {
 // - Γ = ∑ⱼ Γⱼ
 // - Δᵢ = [kᵢ]Γ
 // - δᵢ = γᵢ kᵢ + ∑ⱼ δᵢⱼ
-// - χᵢ = xᵢ kᵢ + ∑ⱼ χᵢⱼ.
+// - χᵢ = xᵢ kᵢ + ∑ⱼ χᵢⱼ
+// - commit to χᵢ with ElGamal, so that Sᵢ = [χᵢ]R can be proven correct before σᵢ is accepted.
 func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Γ = ∑ⱼ Γⱼ
 	Gamma := r.Group().NewPoint()
@@ -167,18 +224,25 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 	}
 
 	DeltaShareScalar := r.Group().NewScalar().SetNat(DeltaShare.Mod(r.Group().Order()))
+	ChiShareScalar := r.Group().NewScalar().SetNat(ChiShare.Mod(r.Group().Order()))
+
+	// Ẑᵢ = (b̂ᵢ⋅G, χᵢ⋅G+b̂ᵢ⋅Yᵢ)
+	ElGamalChi, ElGamalChiNonce := elgamal.Encrypt(r.Rand(), r.ElGamal[r.SelfID()], ChiShareScalar)
+
 	if err := r.BroadcastMessage(out, &broadcast4{
 		DeltaShare:    DeltaShareScalar,
 		BigDeltaShare: BigDeltaShare,
+		ElGamalChi:    ElGamalChi,
 	}); err != nil {
 		return r, err
 	}
 
 	otherIDs := r.OtherPartyIDs()
+	readers := sample.Fork(r.Rand(), len(otherIDs))
 	errs := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
 		j := otherIDs[i]
 
-		proofLog := zklogstar.NewProof(r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
+		proofLog := zklogstar.NewProof(readers[i], r.Group(), r.HashForID(r.SelfID()), zklogstar.Public{
 			C:      r.K[r.SelfID()],
 			X:      BigDeltaShare,
 			G:      Gamma,
@@ -200,11 +264,13 @@ func (r *round3) Finalize(out chan<- *round.Message) (round.Session, error) {
 		}
 	}
 	return &round4{
-		round3:         r,
-		DeltaShares:    map[party.ID]curve.Scalar{r.SelfID(): DeltaShareScalar},
-		BigDeltaShares: map[party.ID]curve.Point{r.SelfID(): BigDeltaShare},
-		Gamma:          Gamma,
-		ChiShare:       r.Group().NewScalar().SetNat(ChiShare.Mod(r.Group().Order())),
+		round3:          r,
+		DeltaShares:     map[party.ID]curve.Scalar{r.SelfID(): DeltaShareScalar},
+		BigDeltaShares:  map[party.ID]curve.Point{r.SelfID(): BigDeltaShare},
+		Gamma:           Gamma,
+		ChiShare:        ChiShareScalar,
+		ElGamalChi:      map[party.ID]*elgamal.Ciphertext{r.SelfID(): ElGamalChi},
+		ElGamalChiNonce: ElGamalChiNonce,
 	}, nil
 }
 
@@ -3,9 +3,11 @@ package sign
 import (
 	"errors"
 
+	"github.com/w3-key/mps-lean/pkg/elgamal"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/party"
 	"github.com/w3-key/mps-lean/pkg/round"
+	zkelog "github.com/w3-key/mps-lean/pkg/zk/elog"
 	zklogstar "github.com/w3-key/mps-lean/pkg/zk/logstar"
 )
 
@@ -24,6 +26,11 @@ type round4 struct {
 
 	// ChiShare = χᵢ
 	ChiShare curve.Scalar
+
+	// ElGamalChi[j] = Ẑⱼ = (b̂ⱼ⋅G, χⱼ⋅G+b̂ⱼ⋅Yⱼ)
+	ElGamalChi map[party.ID]*elgamal.Ciphertext
+	// ElGamalChiNonce = b̂ᵢ
+	ElGamalChiNonce elgamal.Nonce
 }
 
 type message4 struct {
@@ -36,21 +43,24 @@ type broadcast4 struct {
 	DeltaShare curve.Scalar
 	// BigDeltaShare = Δⱼ = [kⱼ]•Γⱼ
 	BigDeltaShare curve.Point
+	// ElGamalChi = Ẑⱼ
+	ElGamalChi *elgamal.Ciphertext
 }
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - store δⱼ, Δⱼ
+// - store δⱼ, Δⱼ, Ẑⱼ
 func (r *round4) StoreBroadcastMessage(msg round.Message) error {
 	body, ok := msg.Content.(*broadcast4)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
-	if body.DeltaShare.IsZero() || body.BigDeltaShare.IsIdentity() {
+	if body.DeltaShare.IsZero() || body.BigDeltaShare.IsIdentity() || !body.ElGamalChi.Valid() {
 		return round.ErrNilFields
 	}
 	r.BigDeltaShares[msg.From] = body.BigDeltaShare
 	r.DeltaShares[msg.From] = body.DeltaShare
+	r.ElGamalChi[msg.From] = body.ElGamalChi
 	return nil
 }
 
@@ -87,7 +97,8 @@ func (round4) StoreMessage(round.Message) error {
 //
 // - set δ = ∑ⱼ δⱼ
 // - set Δ = ∑ⱼ Δⱼ
-// - verify Δ = [δ]G
+// - verify Δ = [δ]G, or start the identification round if it fails
+// - compute R̄ⱼ = [δ⁻¹]Δⱼ, Sᵢ = [χᵢ]R and prove zkelog(Ẑᵢ, Sᵢ)
 // - compute σᵢ = rχᵢ + kᵢm.
 func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// δ = ∑ⱼ δⱼ
@@ -102,13 +113,31 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	// Δ == [δ]G
 	deltaComputed := Delta.ActOnBase()
 	if !deltaComputed.Equal(BigDelta) {
-		return r.AbortRound(errors.New("computed Δ is inconsistent with [δ]G")), nil
+		return r.identify(out, nil, nil)
 	}
 
 	deltaInv := r.Group().NewScalar().Set(Delta).Invert() // δ⁻¹
 	BigR := deltaInv.Act(r.Gamma)                         // R = [δ⁻¹] Γ
 	R := BigR.XScalar()                                   // r = R|ₓ
 
+	// R̄ⱼ = [δ⁻¹] Δⱼ
+	RBar := make(map[party.ID]curve.Point, r.N())
+	for _, j := range r.PartyIDs() {
+		RBar[j] = deltaInv.Act(r.BigDeltaShares[j])
+	}
+	// Sᵢ = [χᵢ] R
+	S := r.ChiShare.Act(BigR)
+
+	proof := zkelog.NewProof(r.Rand(), r.Group(), r.HashForID(r.SelfID()), zkelog.Public{
+		E:             r.ElGamalChi[r.SelfID()],
+		ElGamalPublic: r.ElGamal[r.SelfID()],
+		Base:          BigR,
+		Y:             S,
+	}, zkelog.Private{
+		Y:      r.ChiShare,
+		Lambda: r.ElGamalChiNonce,
+	})
+
 	// km = Hash(m)⋅kᵢ
 	km := curve.FromHash(r.Group(), r.Message)
 	km.Mul(r.KShare)
@@ -117,7 +146,7 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	SigmaShare := r.Group().NewScalar().Set(R).Mul(r.ChiShare).Add(km)
 
 	// Send to all
-	err := r.BroadcastMessage(out, &broadcast5{SigmaShare: SigmaShare})
+	err := r.BroadcastMessage(out, &broadcast5{SigmaShare: SigmaShare, S: S, Proof: proof})
 	if err != nil {
 		return r, err
 	}
@@ -139,6 +168,8 @@ func (r *round4) Finalize(out chan<- *round.Message) (round.Session, error) {
 	return &round5{
 		round4:      r,
 		SigmaShares: map[party.ID]curve.Scalar{r.SelfID(): SigmaShare},
+		RBar:        RBar,
+		S:           map[party.ID]curve.Point{r.SelfID(): S},
 		Delta:       Delta,
 		BigDelta:    BigDelta,
 		BigR:        BigR,
@@ -165,6 +196,7 @@ func (r *round4) BroadcastContent() round.BroadcastContent {
 	return &broadcast4{
 		DeltaShare:    r.Group().NewScalar(),
 		BigDeltaShare: r.Group().NewPoint(),
+		ElGamalChi:    elgamal.Empty(r.Group()),
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/party"
 	"github.com/w3-key/mps-lean/pkg/round"
+	zkelog "github.com/w3-key/mps-lean/pkg/zk/elog"
 )
 
 var _ round.Round = (*round5)(nil)
@@ -17,6 +18,12 @@ type round5 struct {
 	// SigmaShares[j] = σⱼ = m⋅kⱼ + χⱼ⋅R|ₓ
 	SigmaShares map[party.ID]curve.Scalar
 
+	// RBar[j] = R̄ⱼ = [δ⁻¹]Δⱼ = [k⁻¹kⱼ]G
+	RBar map[party.ID]curve.Point
+
+	// S[j] = Sⱼ = [χⱼ]R
+	S map[party.ID]curve.Point
+
 	// Delta = δ = ∑ⱼ δⱼ
 	// computed from received shares
 	Delta curve.Scalar
@@ -35,23 +42,48 @@ type round5 struct {
 
 type broadcast5 struct {
 	round.NormalBroadcastContent
+	// SigmaShare = σᵢ
 	SigmaShare curve.Scalar
+	// S = Sᵢ = [χᵢ]R
+	S curve.Point
+	// Proof = zkelog(Ẑᵢ, Sᵢ)
+	Proof *zkelog.Proof
 }
 
 // StoreBroadcastMessage implements round.BroadcastRound.
 //
-// - save σⱼ
+// - verify zkelog(Ẑⱼ, Sⱼ), so that Sⱼ is bound to the χⱼ committed to in round 4
+// - verify [σⱼ]R = [m]R̄ⱼ + [r]Sⱼ
+// - save σⱼ, Sⱼ.
 func (r *round5) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
 	body, ok := msg.Content.(*broadcast5)
 	if !ok || body == nil {
 		return round.ErrInvalidContent
 	}
 
-	if body.SigmaShare.IsZero() {
+	if body.SigmaShare.IsZero() || body.S.IsIdentity() || body.Proof == nil {
 		return round.ErrNilFields
 	}
 
-	r.SigmaShares[msg.From] = body.SigmaShare
+	if !body.Proof.Verify(r.HashForID(from), zkelog.Public{
+		E:             r.ElGamalChi[from],
+		ElGamalPublic: r.ElGamal[from],
+		Base:          r.BigR,
+		Y:             body.S,
+	}) {
+		return errors.New("failed to validate elog proof for S")
+	}
+
+	m := curve.FromHash(r.Group(), r.Message)
+	lhs := body.SigmaShare.Act(r.BigR)
+	rhs := m.Act(r.RBar[from]).Add(r.R.Act(body.S))
+	if !lhs.Equal(rhs) {
+		return errors.New("invalid σ share")
+	}
+
+	r.SigmaShares[from] = body.SigmaShare
+	r.S[from] = body.S
 	return nil
 }
 
@@ -63,9 +95,19 @@ func (round5) StoreMessage(round.Message) error { return nil }
 
 // Finalize implements round.Round
 //
+// - verify ∑ⱼ Sⱼ = X
 // - compute σ = ∑ⱼ σⱼ
-// - verify signature.
-func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
+// - verify signature, or start the identification round if either check fails.
+func (r *round5) Finalize(out chan<- *round.Message) (round.Session, error) {
+	// ∑ⱼ Sⱼ = [χ]R = [kx]([k⁻¹]G) = X
+	S := r.Group().NewPoint()
+	for _, j := range r.PartyIDs() {
+		S = S.Add(r.S[j])
+	}
+	if !S.Equal(r.PublicKey) {
+		return r.identify(out, r.SigmaShares, r.R)
+	}
+
 	// compute σ = ∑ⱼ σⱼ
 	Sigma := r.Group().NewScalar()
 
@@ -89,14 +131,6 @@ func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
 		S: Sigma,
 	}
 
-	signatureParts := SignatureParts{
-		r.KShare,
-		r.BigR,
-		r.ChiShare,
-		r.Group(),
-		r.PublicPoint,
-	}
-
 	//b, _ := Sigma.MarshalBinary()
 
 	//if r.SelfID() == "a" {
@@ -104,14 +138,10 @@ func (r *round5) Finalize(chan<- *round.Message) (round.Session, error) {
 	//}
 
 	if !signature.Verify(r.PublicKey, r.Message) {
-		return r.AbortRound(errors.New("failed to validate signature")), nil
+		return r.identify(out, r.SigmaShares, r.R)
 	}
 
-	if r.JustInfo {
-		return r.ResultRound(signatureParts), nil
-	} else {
-		return r.ResultRound(signature), nil
-	}
+	return r.ResultRound(signature), nil
 }
 
 // MessageContent implements round.Round.
@@ -124,6 +154,8 @@ func (broadcast5) RoundNumber() round.Number { return 5 }
 func (r *round5) BroadcastContent() round.BroadcastContent {
 	return &broadcast5{
 		SigmaShare: r.Group().NewScalar(),
+		S:          r.Group().NewPoint(),
+		Proof:      zkelog.Empty(r.Group()),
 	}
 }
 
@@ -0,0 +1,375 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cronokirby/saferith"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zkdec "github.com/w3-key/mps-lean/pkg/zk/dec"
+	zkmul "github.com/w3-key/mps-lean/pkg/zk/mul"
+	zkmulstar "github.com/w3-key/mps-lean/pkg/zk/mulstar"
+)
+
+var _ round.Round = (*round6)(nil)
+
+// round6 is only reached if Δ ≠ [δ]G in round 4, or if the signature does not verify in round 5.
+// Each party reveals the ciphertexts it used during the MtA, and proves that
+// its δᵢ (and σᵢ) was computed correctly from them, so that culprits can be identified.
+type round6 struct {
+	*round4
+
+	// SigmaShares[j] = σⱼ, or nil if the failure happened before σ was computed.
+	SigmaShares map[party.ID]curve.Scalar
+	// R = r = R|ₓ, or nil if the failure happened before σ was computed.
+	R curve.Scalar
+
+	// H[j] = Hⱼ = encⱼ(kⱼ⋅γⱼ)
+	H map[party.ID]*paillier.Ciphertext
+	// HatH[j] = Ĥⱼ = encⱼ(kⱼ⋅xⱼ)
+	HatH map[party.ID]*paillier.Ciphertext
+	// HProof[j] = zkmul(Kⱼ, Gⱼ, Hⱼ)
+	HProof map[party.ID]*zkmul.Proof
+	// Published[j] are the F ciphertexts j claims to have sent to the other parties.
+	// The D ciphertexts are taken from the round 3 broadcast instead, so that a party cannot
+	// publish a false Dⱼₗ to make l's valid proofs fail.
+	Published map[party.ID]*mtaCiphertexts
+	// Proofs[j] contains the proofs j sent to this party.
+	Proofs map[party.ID]*message6
+}
+
+type broadcast6 struct {
+	round.NormalBroadcastContent
+	// H = Hᵢ = encᵢ(kᵢ⋅γᵢ)
+	H *paillier.Ciphertext
+	// HatH = Ĥᵢ = encᵢ(kᵢ⋅xᵢ)
+	HatH *paillier.Ciphertext
+	// HProof = zkmul(Kᵢ, Gᵢ, Hᵢ)
+	HProof *zkmul.Proof
+	// Sent contains the F ciphertexts sent to each party in round 2.
+	Sent *mtaCiphertexts
+}
+
+type message6 struct {
+	// HatHProof = zkmul*(Kᵢ, Ĥᵢ, Xᵢ)
+	HatHProof *zkmulstar.Proof
+	// DeltaProof = zkdec(encᵢ(δᵢ), δᵢ)
+	DeltaProof *zkdec.Proof
+	// SigmaProof = zkdec(encᵢ(σᵢ), σᵢ), nil if σ was not computed.
+	SigmaProof *zkdec.Proof
+}
+
+// identify reveals this party's MtA ciphertexts and proves the correctness of δᵢ and σᵢ.
+// SigmaShares and R are nil if the failure happened before σ was computed.
+//
+// - Hᵢ = kᵢ ⊙ Gᵢ, Ĥᵢ = xᵢ ⊙ Kᵢ
+// - encᵢ(δᵢ) = Hᵢ ⊕ ∑ⱼ Dᵢⱼ ⊕ ∑ⱼ (-1) ⊙ Fⱼᵢ
+// - encᵢ(χᵢ) = Ĥᵢ ⊕ ∑ⱼ D̂ᵢⱼ ⊕ ∑ⱼ (-1) ⊙ F̂ⱼᵢ
+// - encᵢ(σᵢ) = (m ⊙ Kᵢ) ⊕ (r ⊙ encᵢ(χᵢ))
+// - broadcast Hᵢ, Ĥᵢ with zkmul, and the sent F ciphertexts.
+// - send zkmul*, zkdec(δᵢ) and zkdec(σᵢ) to each party.
+func (r *round4) identify(out chan<- *round.Message, SigmaShares map[party.ID]curve.Scalar, R curve.Scalar) (round.Session, error) {
+	self := r.SelfID()
+	pk := r.Paillier[self]
+	KShareInt := curve.MakeInt(r.KShare)
+	SecretECDSAInt := curve.MakeInt(r.SecretECDSA)
+
+	// Hᵢ = kᵢ ⊙ Gᵢ
+	H := r.G[self].Clone().Mul(pk, KShareInt)
+	HNonce := H.Randomize(pk, sample.UnitModN(r.Rand(), pk.N()))
+	HProof := zkmul.NewProof(r.Rand(), r.Group(), r.HashForID(self), zkmul.Public{
+		X:      r.K[self],
+		Y:      r.G[self],
+		C:      H,
+		Prover: pk,
+	}, zkmul.Private{
+		X:    KShareInt,
+		Rho:  HNonce,
+		RhoX: r.KNonce,
+	})
+
+	// Ĥᵢ = xᵢ ⊙ Kᵢ
+	HatH := r.K[self].Clone().Mul(pk, SecretECDSAInt)
+	HatHNonce := HatH.Randomize(pk, sample.UnitModN(r.Rand(), pk.N()))
+
+	published := &mtaCiphertexts{DeltaF: r.Sent.DeltaF, ChiF: r.Sent.ChiF}
+	if err := r.BroadcastMessage(out, &broadcast6{
+		H:      H,
+		HatH:   HatH,
+		HProof: HProof,
+		Sent:   published,
+	}); err != nil {
+		return r, err
+	}
+
+	// use what we actually received and sent, verifiers check the same values against the published ones.
+	DeltaCt := combineMtA(pk, H, r.Received.DeltaD, r.Sent.DeltaF)
+	DeltaPlaintext, DeltaNonce, err := r.SecretPaillier.DecWithRandomness(DeltaCt)
+	if err != nil {
+		return r, fmt.Errorf("failed to decrypt δ: %w", err)
+	}
+
+	var SigmaCt *paillier.Ciphertext
+	var SigmaPlaintext *saferith.Int
+	var SigmaNonce *saferith.Nat
+	if SigmaShares != nil {
+		ChiCt := combineMtA(pk, HatH, r.Received.ChiD, r.Sent.ChiF)
+		SigmaCt = sigmaCiphertext(r.Group(), pk, r.K[self], ChiCt, r.Message, R)
+		SigmaPlaintext, SigmaNonce, err = r.SecretPaillier.DecWithRandomness(SigmaCt)
+		if err != nil {
+			return r, fmt.Errorf("failed to decrypt σ: %w", err)
+		}
+	}
+
+	otherIDs := r.OtherPartyIDs()
+	readers := sample.Fork(r.Rand(), len(otherIDs))
+	errs := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
+		j := otherIDs[i]
+		msg := &message6{
+			HatHProof: zkmulstar.NewProof(readers[i], r.Group(), r.HashForID(self), zkmulstar.Public{
+				C:        r.K[self],
+				D:        HatH,
+				X:        r.ECDSA[self],
+				Verifier: pk,
+				Aux:      r.Pedersen[j],
+			}, zkmulstar.Private{
+				X:   SecretECDSAInt,
+				Rho: HatHNonce,
+			}),
+			DeltaProof: zkdec.NewProof(readers[i], r.Group(), r.HashForID(self), zkdec.Public{
+				C:      DeltaCt,
+				X:      r.DeltaShares[self],
+				Prover: pk,
+				Aux:    r.Pedersen[j],
+			}, zkdec.Private{
+				Y:   DeltaPlaintext,
+				Rho: DeltaNonce,
+			}),
+		}
+		if SigmaCt != nil {
+			msg.SigmaProof = zkdec.NewProof(readers[i], r.Group(), r.HashForID(self), zkdec.Public{
+				C:      SigmaCt,
+				X:      SigmaShares[self],
+				Prover: pk,
+				Aux:    r.Pedersen[j],
+			}, zkdec.Private{
+				Y:   SigmaPlaintext,
+				Rho: SigmaNonce,
+			})
+		}
+		return r.SendMessage(out, msg, j)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return r, err.(error)
+		}
+	}
+
+	return &round6{
+		round4:      r,
+		SigmaShares: SigmaShares,
+		R:           R,
+		H:           map[party.ID]*paillier.Ciphertext{self: H},
+		HatH:        map[party.ID]*paillier.Ciphertext{self: HatH},
+		HProof:      map[party.ID]*zkmul.Proof{self: HProof},
+		Published:   map[party.ID]*mtaCiphertexts{self: published},
+		Proofs:      map[party.ID]*message6{},
+	}, nil
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - store Hⱼ, Ĥⱼ, and the F ciphertexts sent by j.
+func (r *round6) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast6)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.HProof == nil || body.Sent == nil {
+		return round.ErrNilFields
+	}
+
+	if !r.Paillier[from].ValidateCiphertexts(body.H, body.HatH) {
+		return errors.New("invalid H, Ĥ")
+	}
+
+	for _, l := range r.PartyIDs() {
+		if l == from {
+			continue
+		}
+		if !r.Paillier[from].ValidateCiphertexts(body.Sent.DeltaF[l], body.Sent.ChiF[l]) {
+			return errors.New("invalid published F")
+		}
+	}
+
+	r.H[from] = body.H
+	r.HatH[from] = body.HatH
+	r.HProof[from] = body.HProof
+	r.Published[from] = body.Sent
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+//
+// The proofs are verified in Finalize, once all published ciphertexts have been received.
+func (r *round6) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message6)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+
+	if body.HatHProof == nil || body.DeltaProof == nil {
+		return round.ErrNilFields
+	}
+
+	if r.SigmaShares != nil && body.SigmaProof == nil {
+		return round.ErrNilFields
+	}
+
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round6) StoreMessage(msg round.Message) error {
+	r.Proofs[msg.From] = msg.Content.(*message6)
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - check that the published F ciphertexts match the ones j sent to us.
+// - verify zkmul(Hⱼ), zkmul*(Ĥⱼ)
+// - verify zkdec(encⱼ(δⱼ)), and zkdec(encⱼ(σⱼ)) if σ was computed.
+// - abort, naming all parties whose verification failed.
+func (r *round6) Finalize(chan<- *round.Message) (round.Session, error) {
+	self := r.SelfID()
+	otherIDs := r.OtherPartyIDs()
+
+	results := r.Pool.Parallelize(len(otherIDs), func(i int) interface{} {
+		j := otherIDs[i]
+		pk := r.Paillier[j]
+		published := r.Published[j]
+		proofs := r.Proofs[j]
+
+		// the ciphertexts j published must be the ones it sent to us
+		if !published.DeltaF[self].Equal(r.Received.DeltaF[j]) || !published.ChiF[self].Equal(r.Received.ChiF[j]) {
+			return false
+		}
+
+		if !r.HProof[j].Verify(r.Group(), r.HashForID(j), zkmul.Public{
+			X:      r.K[j],
+			Y:      r.G[j],
+			C:      r.H[j],
+			Prover: pk,
+		}) {
+			return false
+		}
+
+		if !proofs.HatHProof.Verify(r.Group(), r.HashForID(j), zkmulstar.Public{
+			C:        r.K[j],
+			D:        r.HatH[j],
+			X:        r.ECDSA[j],
+			Verifier: pk,
+			Aux:      r.Pedersen[self],
+		}) {
+			return false
+		}
+
+		// Dₗⱼ as broadcast by l in round 3, which j checked against the ones it received,
+		// and Fⱼₗ as published by j
+		DeltaD := make(map[party.ID]*paillier.Ciphertext, len(otherIDs))
+		ChiD := make(map[party.ID]*paillier.Ciphertext, len(otherIDs))
+		for _, l := range r.PartyIDs() {
+			if l == j {
+				continue
+			}
+			DeltaD[l] = r.BroadcastD[l].DeltaD[j]
+			ChiD[l] = r.BroadcastD[l].ChiD[j]
+		}
+
+		DeltaCt := combineMtA(pk, r.H[j], DeltaD, published.DeltaF)
+		if !proofs.DeltaProof.Verify(r.HashForID(j), zkdec.Public{
+			C:      DeltaCt,
+			X:      r.DeltaShares[j],
+			Prover: pk,
+			Aux:    r.Pedersen[self],
+		}) {
+			return false
+		}
+
+		if r.SigmaShares != nil {
+			ChiCt := combineMtA(pk, r.HatH[j], ChiD, published.ChiF)
+			SigmaCt := sigmaCiphertext(r.Group(), pk, r.K[j], ChiCt, r.Message, r.R)
+			if !proofs.SigmaProof.Verify(r.HashForID(j), zkdec.Public{
+				C:      SigmaCt,
+				X:      r.SigmaShares[j],
+				Prover: pk,
+				Aux:    r.Pedersen[self],
+			}) {
+				return false
+			}
+		}
+		return true
+	})
+
+	var culprits []party.ID
+	for idx, valid := range results {
+		if !valid.(bool) {
+			culprits = append(culprits, otherIDs[idx])
+		}
+	}
+
+	if r.SigmaShares != nil {
+		return r.AbortRound(errors.New("failed to validate signature"), culprits...), nil
+	}
+	return r.AbortRound(errors.New("computed Δ is inconsistent with [δ]G"), culprits...), nil
+}
+
+// combineMtA returns start ⊕ ∑ⱼ D[j] ⊕ ∑ⱼ (-1) ⊙ F[j], where all ciphertexts are encrypted under pk.
+// If D and F are the ciphertexts of an MtA where the receiver holds start = enc(a⋅b),
+// the result is the encryption of the receiver's additive share a⋅b + ∑ⱼ (αⱼ + βⱼ).
+func combineMtA(pk *paillier.PublicKey, start *paillier.Ciphertext, D, F map[party.ID]*paillier.Ciphertext) *paillier.Ciphertext {
+	minusOne := new(saferith.Int).SetUint64(1).Neg(1)
+	result := start.Clone()
+	for _, Dj := range D {
+		result.Add(pk, Dj)
+	}
+	for _, Fj := range F {
+		result.Add(pk, Fj.Clone().Mul(pk, minusOne))
+	}
+	return result
+}
+
+// sigmaCiphertext returns encᵢ(σᵢ) = (m ⊙ Kᵢ) ⊕ (r ⊙ encᵢ(χᵢ)).
+func sigmaCiphertext(group curve.Curve, pk *paillier.PublicKey, K, ChiCt *paillier.Ciphertext, message []byte, R curve.Scalar) *paillier.Ciphertext {
+	m := curve.MakeInt(curve.FromHash(group, message))
+	rChi := ChiCt.Clone().Mul(pk, curve.MakeInt(R))
+	return K.Clone().Mul(pk, m).Add(pk, rChi)
+}
+
+// MessageContent implements round.Round.
+func (r *round6) MessageContent() round.Content {
+	return &message6{
+		HatHProof:  zkmulstar.Empty(r.Group()),
+		DeltaProof: zkdec.Empty(r.Group()),
+		SigmaProof: zkdec.Empty(r.Group()),
+	}
+}
+
+// RoundNumber implements round.Content.
+func (message6) RoundNumber() round.Number { return 6 }
+
+// RoundNumber implements round.Content.
+func (broadcast6) RoundNumber() round.Number { return 6 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (round6) BroadcastContent() round.BroadcastContent { return &broadcast6{} }
+
+// Number implements round.Round.
+func (round6) Number() round.Number { return 6 }
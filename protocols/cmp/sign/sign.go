@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/w3-key/mps-lean/pkg/elgamal"
+	"github.com/w3-key/mps-lean/pkg/hash"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
 	"github.com/w3-key/mps-lean/pkg/math/polynomial"
 	"github.com/w3-key/mps-lean/pkg/paillier"
@@ -17,54 +19,22 @@ import (
 )
 
 // protocolSignID for the "3 round" variant using echo broadcast.
+// The last round is only executed to identify culprits when the protocol fails.
 const (
 	protocolSignID                  = "cmp/sign"
-	protocolSignRounds round.Number = 5
+	protocolSignRounds round.Number = 6
 )
 
-type SignatureParts struct {
-	GroupKShare curve.Scalar
-	GroupBigR curve.Point
-	GroupChiShare curve.Scalar
-	Group curve.Curve
-	GroupPublicPoint curve.Point
+func StartSign(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return StartSignDerived(config, signers, message, nil, pl, opts...)
 }
 
-func (s *SignatureParts) EmptyConfig() SignatureParts {
-	newGroup := curve.Secp256k1{}
-	return SignatureParts{
-		GroupKShare: newGroup.NewScalar(),
-		GroupBigR: newGroup.NewPoint(),
-		GroupChiShare: newGroup.NewScalar(),
-		Group: newGroup,
-		GroupPublicPoint: newGroup.NewPoint(),
-	}
-}
-
-func (s *SignatureParts) GetKShare() curve.Scalar {
-	return s.GroupKShare
-}
-
-func (s *SignatureParts) GetBigR() curve.Point {
-	return s.GroupBigR
-}
-
-func (s *SignatureParts) GetChiShare() curve.Scalar {
-	return s.GroupChiShare
-}
-
-func (s *SignatureParts) GetGroup() curve.Curve {
-	return s.Group
-}
-
-func (s *SignatureParts) GetGroupPublicPoint() curve.Point {
-	return s.GroupPublicPoint
-}
-
-func StartSign(config *config.Config, signers []party.ID, message []byte, pl *pool.Pool, justinfo bool) protocol.StartFunc {
+// StartSignDerived is like StartSign, but signs with the key x + tweak, where x is the key shared by config.
+//
+// The tweak is applied to each share when scaling it to the signers, and is included in the session hash,
+// so that all signers must agree on the key used. A nil tweak is the same as StartSign.
+func StartSignDerived(config *config.Config, signers []party.ID, message []byte, tweak curve.Scalar, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
 	return func(sessionID []byte) (round.Session, error) {
-		group := config.Group
-
 		// this could be used to indicate a pre-signature later on
 		if len(message) == 0 {
 			return nil, errors.New("sign.Create: message is nil")
@@ -77,48 +47,74 @@ func StartSign(config *config.Config, signers []party.ID, message []byte, pl *po
 			PartyIDs:         signers,
 			Threshold:        config.Threshold,
 			Group:            config.Group,
-			JustInfo:         justinfo,
-			PublicPoint:      config.PublicPoint(),
 		}
-
-		helper, err := round.NewSession(info, sessionID, pl, config, types.SigningMessage(message))
+		info.Apply(opts...)
+
+		auxInfo := []hash.WriterToWithDomain{config, types.SigningMessage(message)}
+		if tweak != nil {
+			tweakBytes, err := tweak.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("sign.Create: %w", err)
+			}
+			auxInfo = append(auxInfo, &hash.BytesWithDomain{TheDomain: "CMP Sign Tweak", Bytes: tweakBytes})
+		}
+		helper, err := round.NewSession(info, sessionID, pl, auxInfo...)
 		if err != nil {
 			return nil, fmt.Errorf("sign.Create: %w", err)
 		}
 
-		if !config.CanSign(helper.PartyIDs()) {
-			return nil, errors.New("sign.Create: signers is not a valid signing subset")
-		}
+		return newRound1(helper, config, message, tweak)
+	}
+}
 
-		// Scale public data
-		T := helper.N()
-		ECDSA := make(map[party.ID]curve.Point, T)
-		Paillier := make(map[party.ID]*paillier.PublicKey, T)
-		Pedersen := make(map[party.ID]*pedersen.Parameters, T)
-		PublicKey := group.NewPoint()
-		lagrange := polynomial.Lagrange(group, signers)
-		// Scale own secret
-		SecretECDSA := group.NewScalar().Set(lagrange[config.ID]).Mul(config.ECDSA)
-		SecretPaillier := config.Paillier
-		for _, j := range helper.PartyIDs() {
-			public := config.Public[j]
-			// scale public key share
-			ECDSA[j] = lagrange[j].Act(public.ECDSA)
-			Paillier[j] = public.Paillier
-			Pedersen[j] = public.Pedersen
-			PublicKey = PublicKey.Add(ECDSA[j])
+// newRound1 scales the public data of config to the signers of the session, and returns the first round for signing message.
+//
+// If tweak is not nil, it is added to every share xⱼ before scaling, so that the signing key becomes x + tweak.
+func newRound1(helper *round.Helper, config *config.Config, message []byte, tweak curve.Scalar) (*round1, error) {
+	if !config.CanSign(helper.PartyIDs()) {
+		return nil, errors.New("sign.Create: signers is not a valid signing subset")
+	}
+
+	// Scale public data
+	group := config.Group
+	T := helper.N()
+	ECDSA := make(map[party.ID]curve.Point, T)
+	ElGamal := make(map[party.ID]elgamal.PublicKey, T)
+	Paillier := make(map[party.ID]*paillier.PublicKey, T)
+	Pedersen := make(map[party.ID]*pedersen.Parameters, T)
+	PublicKey := group.NewPoint()
+	lagrange := polynomial.Lagrange(group, helper.PartyIDs())
+	// Scale own secret
+	SecretECDSA := group.NewScalar().Set(config.ECDSA)
+	var tweakG curve.Point
+	if tweak != nil {
+		SecretECDSA.Add(tweak)
+		tweakG = tweak.ActOnBase()
+	}
+	SecretECDSA.Mul(lagrange[config.ID])
+	SecretPaillier := config.Paillier
+	for _, j := range helper.PartyIDs() {
+		public := config.Public[j]
+		// scale public key share
+		Xj := public.ECDSA
+		if tweakG != nil {
+			Xj = Xj.Add(tweakG)
 		}
-		return &round1{
-			Helper:         helper,
-			PublicKey:      PublicKey,
-			SecretECDSA:    SecretECDSA,
-			SecretPaillier: SecretPaillier,
-			Paillier:       Paillier,
-			Pedersen:       Pedersen,
-			ECDSA:          ECDSA,
-			Message:        message,
-			JustInfo:       justinfo,
-			PublicPoint:    config.PublicPoint(),
-		}, nil
+		ECDSA[j] = lagrange[j].Act(Xj)
+		ElGamal[j] = public.ElGamal
+		Paillier[j] = public.Paillier
+		Pedersen[j] = public.Pedersen
+		PublicKey = PublicKey.Add(ECDSA[j])
 	}
+	return &round1{
+		Helper:         helper,
+		PublicKey:      PublicKey,
+		SecretECDSA:    SecretECDSA,
+		SecretPaillier: SecretPaillier,
+		Paillier:       Paillier,
+		Pedersen:       Pedersen,
+		ECDSA:          ECDSA,
+		ElGamal:        ElGamal,
+		Message:        message,
+	}, nil
 }
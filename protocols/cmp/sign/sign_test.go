@@ -1,16 +1,24 @@
 package sign
 
 import (
+	"crypto/rand"
 	mrand "math/rand"
+	"sync"
 	"testing"
 
+	"github.com/cronokirby/saferith"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/elgamal"
 	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/paillier"
+	"github.com/w3-key/mps-lean/pkg/party"
 	"github.com/w3-key/mps-lean/pkg/pool"
 	"github.com/w3-key/mps-lean/pkg/round"
 	"github.com/w3-key/mps-lean/pkg/test"
+	zkelog "github.com/w3-key/mps-lean/pkg/zk/elog"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -57,3 +65,330 @@ func TestRound(t *testing.T) {
 		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature")
 	}
 }
+
+func TestRoundDerived(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	signers := partyIDs[1:]
+
+	tweak, _, err := configs[partyIDs[0]].DeriveTweak("m/44/60/0/0/7")
+	require.NoError(t, err)
+	derived, err := configs[partyIDs[0]].DerivePath("m/44/60/0/0/7")
+	require.NoError(t, err)
+	publicPoint := derived.PublicPoint()
+
+	messageHash := make([]byte, 32)
+	_, _ = rand.Read(messageHash)
+
+	rounds := make([]round.Session, 0, len(signers))
+	for _, partyID := range signers {
+		r, err := StartSignDerived(configs[partyID], signers, messageHash, tweak, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		signature := r.(*round.Output).Result.(*ecdsa.Signature)
+		assert.True(t, signature.Verify(publicPoint, messageHash), "expected valid signature for the derived key")
+		assert.False(t, signature.Verify(configs[partyIDs[0]].PublicPoint(), messageHash), "signature should not be valid for the parent key")
+	}
+
+	// the tweak is bound to the session
+	r1, err := StartSignDerived(configs[signers[0]], signers, messageHash, tweak, pl)(nil)
+	require.NoError(t, err)
+	r2, err := StartSign(configs[signers[0]], signers, messageHash, pl)(nil)
+	require.NoError(t, err)
+	assert.NotEqual(t, r1.SSID(), r2.SSID(), "sessions with different tweaks should differ")
+}
+
+func TestRoundDeterministic(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+	messageHash := make([]byte, 32)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	run := func(seed int64) *ecdsa.Signature {
+		rounds := make([]round.Session, 0, N)
+		for i, partyID := range partyIDs {
+			rand := mrand.New(mrand.NewSource(seed + int64(i)))
+			r, err := StartSign(configs[partyID], partyIDs, messageHash, pl, round.WithRand(rand))([]byte("session"))
+			require.NoError(t, err, "round creation should not result in an error")
+			rounds = append(rounds, r)
+		}
+		for {
+			err, done := test.Rounds(rounds, nil)
+			require.NoError(t, err, "failed to process round")
+			if done {
+				break
+			}
+		}
+		signature := rounds[0].(*round.Output).Result.(*ecdsa.Signature)
+		require.True(t, signature.Verify(configs[partyIDs[0]].PublicPoint(), messageHash), "expected valid signature")
+		return signature
+	}
+
+	first := run(1)
+	second := run(1)
+	assert.True(t, first.R.Equal(second.R) && first.S.Equal(second.S), "same randomness should give the same signature")
+	third := run(2)
+	assert.False(t, first.R.Equal(third.R), "different randomness should give a different nonce")
+}
+
+// chiRule makes culprit commit to a wrong χ share in round 4, and then use it consistently,
+// so that its S and σ shares pass the checks of round 5, and the error is only detected
+// once all shares are combined.
+type chiRule struct {
+	culprit    party.ID
+	elGamalChi *elgamal.Ciphertext
+}
+
+func (chiRule) ModifyBefore(round.Session) {}
+
+func (r *chiRule) ModifyAfter(rNext round.Session) {
+	r4, ok := rNext.(*round4)
+	if !ok || r4.SelfID() != r.culprit {
+		return
+	}
+	group := r4.Group()
+	// χ' = χ + e
+	chi := group.NewScalar().Set(r4.ChiShare).Add(sample.Scalar(rand.Reader, group))
+	r.elGamalChi, r4.ElGamalChiNonce = elgamal.Encrypt(rand.Reader, r4.ElGamal[r.culprit], chi)
+	r4.ChiShare = chi
+	r4.ElGamalChi[r.culprit] = r.elGamalChi
+}
+
+func (r *chiRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	body, ok := content.(*broadcast4)
+	if !ok || rNext.SelfID() != r.culprit {
+		return
+	}
+	body.ElGamalChi = r.elGamalChi
+}
+
+func TestIdentifiableAbort(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	messageHash := make([]byte, 64)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSign(configs[partyID], partyIDs, messageHash, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	culprit := partyIDs[1]
+	rule := &chiRule{culprit: culprit}
+	for {
+		err, done := test.Rounds(rounds, rule)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Abort{}, r, "expected abort round")
+		if r.SelfID() == culprit {
+			continue
+		}
+		assert.Equal(t, []party.ID{culprit}, r.(*round.Abort).Culprits, "expected culprit to be identified")
+	}
+}
+
+// publishRule makes culprit broadcast a wrong δ share in round 4, so that the identification round is reached,
+// and then publish a false F ciphertext for victim in round 6, in an attempt to have victim blamed.
+type publishRule struct {
+	culprit, victim party.ID
+	delta           curve.Scalar
+	forged          *paillier.Ciphertext
+
+	mtx sync.Mutex
+	// received is the set of parties whose round 6 stored the forged ciphertext.
+	received map[party.ID]bool
+}
+
+func (r *publishRule) ModifyBefore(rPrevious round.Session) {
+	r6, ok := rPrevious.(*round6)
+	if !ok || r6.SelfID() == r.culprit || r.forged == nil {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.received[r6.SelfID()] = r6.Published[r.culprit].DeltaF[r.victim].Equal(r.forged)
+}
+
+func (r *publishRule) ModifyAfter(rNext round.Session) {
+	r4, ok := rNext.(*round4)
+	if !ok || r4.SelfID() != r.culprit {
+		return
+	}
+	group := r4.Group()
+	r.delta = group.NewScalar().Set(r4.DeltaShares[r.culprit]).Add(sample.Scalar(rand.Reader, group))
+	r4.DeltaShares[r.culprit] = r.delta
+}
+
+func (r *publishRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	if rNext.SelfID() != r.culprit {
+		return
+	}
+	switch body := content.(type) {
+	case *broadcast4:
+		body.DeltaShare = r.delta
+	case *broadcast6:
+		r6 := rNext.(*round6)
+		// Fᵢⱼ is also used by the verifiers of j, so a false one must not make j's δ look wrong.
+		r.forged = r6.Sent.DeltaF[r.victim].Clone().Mul(r6.Paillier[r.culprit], new(saferith.Int).SetUint64(2))
+		DeltaF := make(map[party.ID]*paillier.Ciphertext, len(body.Sent.DeltaF))
+		for j, F := range body.Sent.DeltaF {
+			DeltaF[j] = F
+		}
+		DeltaF[r.victim] = r.forged
+		body.Sent = &mtaCiphertexts{DeltaF: DeltaF, ChiF: body.Sent.ChiF}
+	}
+}
+
+func TestIdentifiableAbortPublishedD(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	messageHash := make([]byte, 64)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSign(configs[partyID], partyIDs, messageHash, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	culprit, victim := partyIDs[1], partyIDs[2]
+	rule := &publishRule{culprit: culprit, victim: victim, received: map[party.ID]bool{}}
+	for {
+		err, done := test.Rounds(rounds, rule)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Abort{}, r, "expected abort round")
+		if r.SelfID() == culprit {
+			continue
+		}
+		assert.Equal(t, []party.ID{culprit}, r.(*round.Abort).Culprits, "expected only the culprit to be identified")
+		assert.True(t, rule.received[r.SelfID()], "the forged F should have been published")
+	}
+}
+
+func TestSigmaShareVerification(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	N := 3
+	T := N - 1
+
+	configs, partyIDs := test.GenerateConfig(group, N, T, mrand.New(mrand.NewSource(1)), pl)
+
+	messageHash := make([]byte, 64)
+	sha3.ShakeSum128(messageHash, []byte("hello"))
+
+	rounds := make([]round.Session, 0, N)
+	for _, partyID := range partyIDs {
+		r, err := StartSign(configs[partyID], partyIDs, messageHash, pl)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		if _, ok := rounds[0].(*round5); ok {
+			break
+		}
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		require.False(t, done, "protocol should not be done before round 5")
+	}
+
+	from, to := rounds[1].(*round5), rounds[0].(*round5)
+	sigma, S := from.SigmaShares[from.SelfID()], from.S[from.SelfID()]
+	proof := zkelog.NewProof(rand.Reader, group, from.HashForID(from.SelfID()), zkelog.Public{
+		E:             from.ElGamalChi[from.SelfID()],
+		ElGamalPublic: from.ElGamal[from.SelfID()],
+		Base:          from.BigR,
+		Y:             S,
+	}, zkelog.Private{
+		Y:      from.ChiShare,
+		Lambda: from.ElGamalChiNonce,
+	})
+
+	wrongSigma := group.NewScalar().Set(sigma).Add(sample.Scalar(rand.Reader, group))
+	err := to.StoreBroadcastMessage(round.Message{
+		From:      from.SelfID(),
+		Broadcast: true,
+		Content:   &broadcast5{SigmaShare: wrongSigma, S: S, Proof: proof},
+	})
+	assert.Error(t, err, "invalid σ share should be rejected")
+
+	wrongS := sample.Scalar(rand.Reader, group).ActOnBase()
+	err = to.StoreBroadcastMessage(round.Message{
+		From:      from.SelfID(),
+		Broadcast: true,
+		Content:   &broadcast5{SigmaShare: sigma, S: wrongS, Proof: proof},
+	})
+	assert.Error(t, err, "invalid S share should be rejected")
+
+	// σ' = σ + e, S' = S + [e/r]R satisfies [σ']R = [m]R̄ + [r]S', but S' does not match Ẑ.
+	e := sample.Scalar(rand.Reader, group)
+	forgedSigma := group.NewScalar().Set(sigma).Add(e)
+	forgedS := e.Mul(group.NewScalar().Set(from.R).Invert()).Act(from.BigR).Add(S)
+	err = to.StoreBroadcastMessage(round.Message{
+		From:      from.SelfID(),
+		Broadcast: true,
+		Content:   &broadcast5{SigmaShare: forgedSigma, S: forgedS, Proof: proof},
+	})
+	assert.Error(t, err, "σ share with a matching forged S share should be rejected")
+
+	err = to.StoreBroadcastMessage(round.Message{
+		From:      from.SelfID(),
+		Broadcast: true,
+		Content:   &broadcast5{SigmaShare: sigma, S: S, Proof: proof},
+	})
+	assert.NoError(t, err, "valid σ share should be accepted")
+}
@@ -0,0 +1,213 @@
+package sign
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/types"
+)
+
+var (
+	// ErrPreSignatureUsed is returned when a PreSignature with the same ID has already been consumed.
+	ErrPreSignatureUsed = errors.New("sign: presignature has already been used")
+	// ErrPreSignatureNotFound is returned when no PreSignature with the given ID was stored.
+	ErrPreSignatureNotFound = errors.New("sign: presignature not found")
+	// ErrPreSignatureExists is returned when storing a PreSignature whose ID is already present.
+	ErrPreSignatureExists = errors.New("sign: presignature already stored")
+)
+
+// PreSignatureStore persists PreSignatures between the offline and online phases of signing.
+//
+// Implementations must guarantee that Take returns a given PreSignature at most once,
+// even under concurrent access, and must remember consumed IDs so that a PreSignature
+// cannot be stored and used again.
+// Signing two different messages with the same PreSignature reveals the secret key share.
+type PreSignatureStore interface {
+	// Put stores a PreSignature under its ID.
+	// It returns ErrPreSignatureExists or ErrPreSignatureUsed if the ID was seen before.
+	Put(preSignature *ecdsa.PreSignature) error
+	// Take atomically removes and returns the PreSignature with the given ID.
+	// It returns ErrPreSignatureUsed if it was already taken, and ErrPreSignatureNotFound if it was never stored.
+	Take(id types.RID) (*ecdsa.PreSignature, error)
+}
+
+// MemoryStore is a PreSignatureStore which keeps PreSignatures in memory.
+type MemoryStore struct {
+	mtx           sync.Mutex
+	preSignatures map[string]*ecdsa.PreSignature
+	used          map[string]struct{}
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		preSignatures: map[string]*ecdsa.PreSignature{},
+		used:          map[string]struct{}{},
+	}
+}
+
+// Put implements PreSignatureStore.
+func (s *MemoryStore) Put(preSignature *ecdsa.PreSignature) error {
+	if err := preSignature.Validate(); err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+	key := string(preSignature.ID)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.used[key]; ok {
+		return ErrPreSignatureUsed
+	}
+	if _, ok := s.preSignatures[key]; ok {
+		return ErrPreSignatureExists
+	}
+	s.preSignatures[key] = preSignature
+	return nil
+}
+
+// Take implements PreSignatureStore.
+func (s *MemoryStore) Take(id types.RID) (*ecdsa.PreSignature, error) {
+	key := string(id)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.used[key]; ok {
+		return nil, ErrPreSignatureUsed
+	}
+	preSignature, ok := s.preSignatures[key]
+	if !ok {
+		return nil, ErrPreSignatureNotFound
+	}
+	delete(s.preSignatures, key)
+	s.used[key] = struct{}{}
+	return preSignature, nil
+}
+
+// FileStore is a PreSignatureStore which keeps each PreSignature in its own file inside Dir.
+//
+// A PreSignature is consumed by creating an empty marker file for its ID with O_EXCL,
+// so that concurrent processes sharing the same directory cannot both obtain it.
+// The marker is kept after the PreSignature file is removed, so that the ID is refused
+// if it is ever stored again.
+// The files contain secret key material and are written with mode 0600.
+type FileStore struct {
+	// Dir is the directory holding the PreSignatures.
+	Dir string
+	// Group is used to unmarshal the stored PreSignatures.
+	Group curve.Curve
+}
+
+// NewFileStore returns a FileStore using dir, creating it if necessary.
+func NewFileStore(dir string, group curve.Curve) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	return &FileStore{Dir: dir, Group: group}, nil
+}
+
+func (s *FileStore) paths(id types.RID) (stored, used string) {
+	name := hex.EncodeToString(id)
+	return filepath.Join(s.Dir, name+".presig"), filepath.Join(s.Dir, name+".used")
+}
+
+// Put implements PreSignatureStore.
+func (s *FileStore) Put(preSignature *ecdsa.PreSignature) error {
+	if err := preSignature.Validate(); err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+	stored, used := s.paths(preSignature.ID)
+	if exists(used) {
+		return ErrPreSignatureUsed
+	}
+
+	data, err := cbor.Marshal(preSignature)
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+
+	// the PreSignature is written to a temporary file first, so that Take never reads a partial file.
+	f, err := os.CreateTemp(s.Dir, ".presig-*")
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("sign: %w", err)
+	}
+
+	// Link fails if a file with this ID already exists.
+	if err = os.Link(f.Name(), stored); err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return ErrPreSignatureExists
+		}
+		return fmt.Errorf("sign: %w", err)
+	}
+	// A Take of an earlier PreSignature with this ID may have completed since the first check.
+	// Take refuses the ID anyway once the marker exists, so this only reports the error early.
+	if exists(used) {
+		_ = os.Remove(stored)
+		return ErrPreSignatureUsed
+	}
+	return nil
+}
+
+// Take implements PreSignatureStore.
+func (s *FileStore) Take(id types.RID) (*ecdsa.PreSignature, error) {
+	stored, used := s.paths(id)
+	if !exists(stored) {
+		if exists(used) {
+			return nil, ErrPreSignatureUsed
+		}
+		return nil, ErrPreSignatureNotFound
+	}
+
+	// Creating the marker is the single atomic step which claims the ID.
+	// If the PreSignature disappears afterwards, the ID stays consumed.
+	marker, err := os.OpenFile(used, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, ErrPreSignatureUsed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	if err = marker.Close(); err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	data, err := os.ReadFile(stored)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrPreSignatureNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	// only the marker remains once the secret shares have been read
+	if err = os.Remove(stored); err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	preSignature := ecdsa.EmptyPreSignature(s.Group)
+	if err = cbor.Unmarshal(data, preSignature); err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+	return preSignature, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
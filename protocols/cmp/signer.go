@@ -0,0 +1,93 @@
+package cmp
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+)
+
+// Transport runs the signing session of h to completion.
+//
+// It sends the messages from h.Listen() to the other signers, and passes the messages received from them to h.Accept,
+// until the channel returned by h.Listen() is closed, as test.HandlerLoop does over a test.Network.
+// A non-nil error aborts the signature.
+type Transport func(h protocol.Handler) error
+
+// Signer implements crypto.Signer with the threshold key of a Config, so that it can be used by standard Go code
+// such as x509.CreateCertificateRequest, tls or JWS libraries.
+//
+// Each call to Sign runs the Sign protocol for the digest among the signers, over the transport.
+// The other signers must run Sign for the same digest concurrently, for instance with their own Signer.
+type Signer struct {
+	config    *Config
+	signers   []party.ID
+	transport Transport
+	pl        *pool.Pool
+}
+
+var _ crypto.Signer = (*Signer)(nil)
+
+// NewSigner returns a crypto.Signer for the key of config, whose signatures are produced by `signers`,
+// which must include config.ID, exchanging messages through `transport`.
+func NewSigner(config *Config, signers []party.ID, transport Transport, pl *pool.Pool) *Signer {
+	return &Signer{
+		config:    config,
+		signers:   signers,
+		transport: transport,
+		pl:        pl,
+	}
+}
+
+// Public returns the *ecdsa.PublicKey of the group key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.config.PublicPoint().ToECDSA()
+}
+
+// Sign signs digest among the signers, and returns the ASN.1 DER encoding of the low-S form of the signature.
+//
+// rand is ignored, and the randomness of this party is read from crypto/rand.
+// Callers of crypto.Signer pass whatever reader they were given, and the protocol reads all of its nonces,
+// Paillier randomness and proofs from it: a deterministic reader would repeat the nonces of the proofs
+// under different challenges, which reveals the nonce and key shares of this party.
+// If opts specifies a hash function, digest must have its size.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != 0 && len(digest) != opts.HashFunc().Size() {
+		return nil, fmt.Errorf("cmp: digest has %d bytes, expected %d for %v", len(digest), opts.HashFunc().Size(), opts.HashFunc())
+	}
+	sig, err := RunSign(Sign(s.config, s.signers, digest, s.pl), s.transport)
+	if err != nil {
+		return nil, err
+	}
+	if !sig.Verify(s.config.PublicPoint(), digest) {
+		return nil, errors.New("cmp: invalid signature")
+	}
+	return sig.Normalize().SerializeDER()
+}
+
+// RunSign runs the signing protocol started by start, such as Sign or SignWithTweak, over transport,
+// and returns the resulting signature.
+func RunSign(start protocol.StartFunc, transport Transport) (*ecdsa.Signature, error) {
+	h, err := protocol.NewMultiHandler(start, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = transport(h); err != nil {
+		h.Stop()
+		return nil, err
+	}
+	result, err := h.Result()
+	if err != nil {
+		return nil, err
+	}
+	sig, ok := result.(*ecdsa.Signature)
+	if !ok {
+		return nil, errors.New("cmp: unexpected signing result")
+	}
+	return sig, nil
+}
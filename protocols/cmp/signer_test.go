@@ -0,0 +1,131 @@
+package cmp
+
+import (
+	"crypto"
+	stdecdsa "crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	mrand "math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/test"
+)
+
+// coordinator is the crypto.Signer of one party, which asks the other signers to sign the same digest.
+type coordinator struct {
+	self   *Signer
+	others []*Signer
+}
+
+func (c *coordinator) Public() crypto.PublicKey {
+	return c.self.Public()
+}
+
+func (c *coordinator) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var wg sync.WaitGroup
+	errs := make([]error, len(c.others))
+	for i, other := range c.others {
+		wg.Add(1)
+		go func(i int, other *Signer) {
+			defer wg.Done()
+			_, errs[i] = other.Sign(nil, digest, opts)
+		}(i, other)
+	}
+	sig, err := c.self.Sign(rand, digest, opts)
+	wg.Wait()
+	for _, otherErr := range errs {
+		if otherErr != nil {
+			return nil, otherErr
+		}
+	}
+	return sig, err
+}
+
+func newCoordinator(group curve.Curve, pl *pool.Pool) *coordinator {
+	configs, partyIDs := test.GenerateConfig(group, 3, 1, mrand.New(mrand.NewSource(1)), pl)
+	signers := partyIDs[1:]
+	network := test.NewNetwork(signers)
+	newSigner := func(id party.ID) *Signer {
+		return NewSigner(configs[id], signers, func(h protocol.Handler) error {
+			test.HandlerLoop(id, h, network)
+			return nil
+		}, pl)
+	}
+	return &coordinator{self: newSigner(signers[0]), others: []*Signer{newSigner(signers[1])}}
+}
+
+func TestSignerCertificateRequest(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	signer := newCoordinator(curve.P256{}, pl)
+	template := &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: "threshold"},
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, signer)
+	require.NoError(t, err)
+	csr, err := x509.ParseCertificateRequest(der)
+	require.NoError(t, err)
+	assert.NoError(t, csr.CheckSignature())
+	assert.Equal(t, signer.Public(), csr.PublicKey)
+}
+
+func TestSignerSecp256k1(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	signer := newCoordinator(curve.Secp256k1{}, pl)
+	publicKey := signer.self.config.PublicPoint()
+	require.IsType(t, &stdecdsa.PublicKey{}, signer.Public())
+
+	digest := sha256.Sum256([]byte("hello"))
+	der, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	require.NoError(t, err)
+	sig, err := ecdsa.ParseDER(publicKey, digest[:], der)
+	require.NoError(t, err)
+	assert.False(t, sig.S.IsOverHalfOrder(), "signature should be normalized")
+
+	_, err = signer.self.Sign(rand.Reader, digest[:16], crypto.SHA256)
+	assert.Error(t, err, "digest should match the hash function")
+}
+
+// TestSignerIgnoresRand checks that a deterministic reader given to Sign is not used by the protocol,
+// since it would repeat the nonces of this party across signatures.
+func TestSignerIgnoresRand(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+
+	signer := newCoordinator(curve.Secp256k1{}, pl)
+	digest := sha256.Sum256([]byte("hello"))
+	sign := func() []byte {
+		signers := append([]*Signer{signer.self}, signer.others...)
+		var wg sync.WaitGroup
+		results := make([][]byte, len(signers))
+		errs := make([]error, len(signers))
+		for i, s := range signers {
+			wg.Add(1)
+			go func(i int, s *Signer) {
+				defer wg.Done()
+				results[i], errs[i] = s.Sign(mrand.New(mrand.NewSource(1)), digest[:], crypto.SHA256)
+			}(i, s)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		return results[0]
+	}
+	assert.NotEqual(t, sign(), sign(), "signatures with the same reader should use different nonces")
+}
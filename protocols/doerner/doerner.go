@@ -0,0 +1,115 @@
+package doerner
+
+import (
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/doerner/keygen"
+	"github.com/w3-key/mps-lean/protocols/doerner/sign"
+)
+
+// ConfigReceiver is the result of key generation for the receiver.
+// It contains secret key material and should be safely stored.
+type ConfigReceiver = keygen.ConfigReceiver
+
+// ConfigSender is the result of key generation for the sender.
+// It contains secret key material and should be safely stored.
+type ConfigSender = keygen.ConfigSender
+
+// EmptyConfigReceiver creates an empty ConfigReceiver with a fixed group, ready for unmarshalling.
+func EmptyConfigReceiver(group curve.Curve) *ConfigReceiver {
+	return keygen.EmptyConfigReceiver(group)
+}
+
+// EmptyConfigSender creates an empty ConfigSender with a fixed group, ready for unmarshalling.
+func EmptyConfigSender(group curve.Curve) *ConfigSender {
+	return keygen.EmptyConfigSender(group)
+}
+
+// Keygen generates a new ECDSA key shared between two parties, the sender and the receiver.
+//
+// The two parties must use opposite values for `receiver`, and the receiver must be the leader
+// of the protocol.TwoPartyHandler.
+//
+// All randomness of this party is read from crypto/rand, or from the reader set with round.WithRand.
+//
+// Returns *doerner.ConfigReceiver if `receiver` is true, and *doerner.ConfigSender otherwise.
+func Keygen(group curve.Curve, receiver bool, selfID, otherID party.ID, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		info := round.Info{
+			ProtocolID:       "doerner/keygen",
+			FinalRoundNumber: keygen.Rounds,
+			SelfID:           selfID,
+			PartyIDs:         []party.ID{selfID, otherID},
+			Threshold:        1,
+			Group:            group,
+		}
+		info.Apply(opts...)
+		helper, err := round.NewSession(info, sessionID, pl)
+		if err != nil {
+			return nil, fmt.Errorf("doerner.Keygen: %w", err)
+		}
+		if receiver {
+			return keygen.StartReceiver(helper, otherID), nil
+		}
+		return keygen.StartSender(helper, otherID), nil
+	}
+}
+
+// SignReceiver runs the signature generation protocol for the receiver.
+//
+// The receiver must be the leader of the protocol.TwoPartyHandler.
+// Each signing session must use a unique sessionID.
+//
+// Returns *ecdsa.Signature if successful.
+func SignReceiver(config *ConfigReceiver, selfID, otherID party.ID, hash []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		helper, err := newSignSession(config.Group(), config.Public, selfID, otherID, hash, sessionID, pl, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("doerner.SignReceiver: %w", err)
+		}
+		return sign.StartReceiver(config, otherID, hash, helper), nil
+	}
+}
+
+// SignSender runs the signature generation protocol for the sender.
+//
+// Each signing session must use a unique sessionID.
+//
+// Returns *ecdsa.Signature if successful.
+func SignSender(config *ConfigSender, selfID, otherID party.ID, hash []byte, pl *pool.Pool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		helper, err := newSignSession(config.Group(), config.Public, selfID, otherID, hash, sessionID, pl, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("doerner.SignSender: %w", err)
+		}
+		return sign.StartSender(config, otherID, hash, helper), nil
+	}
+}
+
+func newSignSession(group curve.Curve, public curve.Point, selfID, otherID party.ID, messageHash, sessionID []byte, pl *pool.Pool, opts ...round.Option) (*round.Helper, error) {
+	if len(messageHash) == 0 {
+		return nil, fmt.Errorf("empty message hash")
+	}
+	publicBytes, err := public.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	info := round.Info{
+		ProtocolID:       "doerner/sign",
+		FinalRoundNumber: sign.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         []party.ID{selfID, otherID},
+		Threshold:        1,
+		Group:            group,
+	}
+	info.Apply(opts...)
+	return round.NewSession(info, sessionID, pl,
+		&hash.BytesWithDomain{TheDomain: "Doerner Public Key", Bytes: publicBytes},
+		&hash.BytesWithDomain{TheDomain: "Doerner Message", Bytes: messageHash})
+}
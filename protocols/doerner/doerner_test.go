@@ -0,0 +1,171 @@
+package doerner
+
+import (
+	"crypto/rand"
+	mrand "math/rand"
+	"sync"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+const (
+	senderID   party.ID = "sender"
+	receiverID party.ID = "receiver"
+)
+
+// runTwoParty runs both parties of a two-party protocol, and returns their results.
+func runTwoParty(t *testing.T, sessionID []byte, startSender, startReceiver protocol.StartFunc) (interface{}, interface{}) {
+	resultSender, resultReceiver, errSender, errReceiver := tryTwoParty(t, sessionID, startSender, startReceiver)
+	require.NoError(t, errSender)
+	require.NoError(t, errReceiver)
+	return resultSender, resultReceiver
+}
+
+func tryTwoParty(t *testing.T, sessionID []byte, startSender, startReceiver protocol.StartFunc) (interface{}, interface{}, error, error) {
+	hSender, err := protocol.NewTwoPartyHandler(startSender, sessionID, false)
+	require.NoError(t, err)
+	hReceiver, err := protocol.NewTwoPartyHandler(startReceiver, sessionID, true)
+	require.NoError(t, err)
+
+	// relay the messages of each party to the other one, until both are done.
+	var wg sync.WaitGroup
+	relay := func(from, to *protocol.TwoPartyHandler) {
+		defer wg.Done()
+		for msg := range from.Listen() {
+			to.Accept(msg)
+		}
+	}
+	wg.Add(2)
+	go relay(hSender, hReceiver)
+	go relay(hReceiver, hSender)
+	wg.Wait()
+
+	resultSender, errSender := hSender.Result()
+	resultReceiver, errReceiver := hReceiver.Result()
+	return resultSender, resultReceiver, errSender, errReceiver
+}
+
+func runKeygen(t *testing.T, group curve.Curve, pl *pool.Pool) (*ConfigSender, *ConfigReceiver) {
+	resultSender, resultReceiver := runTwoParty(t, nil,
+		Keygen(group, false, senderID, receiverID, pl),
+		Keygen(group, true, receiverID, senderID, pl))
+	require.IsType(t, &ConfigSender{}, resultSender)
+	require.IsType(t, &ConfigReceiver{}, resultReceiver)
+	return resultSender.(*ConfigSender), resultReceiver.(*ConfigReceiver)
+}
+
+func TestSign(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	configSender, configReceiver := runKeygen(t, group, pl)
+	require.True(t, configSender.Public.Equal(configReceiver.Public))
+	secret := group.NewScalar().Set(configSender.SecretShare).Add(configReceiver.SecretShare)
+	require.True(t, secret.ActOnBase().Equal(configSender.Public))
+
+	for i := 0; i < 3; i++ {
+		hash := make([]byte, 32)
+		_, _ = rand.Read(hash)
+		sessionID := []byte{byte(i)}
+		resultSender, resultReceiver := runTwoParty(t, sessionID,
+			SignSender(configSender, senderID, receiverID, hash, pl),
+			SignReceiver(configReceiver, receiverID, senderID, hash, pl))
+
+		require.IsType(t, &ecdsa.Signature{}, resultSender)
+		require.IsType(t, &ecdsa.Signature{}, resultReceiver)
+		sigSender := resultSender.(*ecdsa.Signature)
+		sigReceiver := resultReceiver.(*ecdsa.Signature)
+		assert.True(t, sigSender.Verify(configSender.Public, hash))
+		assert.True(t, sigSender.R.Equal(sigReceiver.R))
+		assert.True(t, sigSender.S.Equal(sigReceiver.S))
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+	hash := make([]byte, 32)
+
+	run := func(seed int64) ([]byte, *ecdsa.Signature) {
+		resultSender, resultReceiver := runTwoParty(t, []byte("keygen"),
+			Keygen(group, false, senderID, receiverID, pl, round.WithRand(mrand.New(mrand.NewSource(seed)))),
+			Keygen(group, true, receiverID, senderID, pl, round.WithRand(mrand.New(mrand.NewSource(seed+1)))))
+		configSender := resultSender.(*ConfigSender)
+		configReceiver := resultReceiver.(*ConfigReceiver)
+		data, err := cbor.Marshal(configReceiver)
+		require.NoError(t, err)
+
+		resultSender, _ = runTwoParty(t, []byte("sign"),
+			SignSender(configSender, senderID, receiverID, hash, pl, round.WithRand(mrand.New(mrand.NewSource(seed+2)))),
+			SignReceiver(configReceiver, receiverID, senderID, hash, pl, round.WithRand(mrand.New(mrand.NewSource(seed+3)))))
+		signature := resultSender.(*ecdsa.Signature)
+		require.True(t, signature.Verify(configSender.Public, hash))
+		return data, signature
+	}
+
+	config1, sig1 := run(1)
+	config2, sig2 := run(1)
+	assert.Equal(t, config1, config2, "same randomness should give the same config")
+	assert.True(t, sig1.R.Equal(sig2.R) && sig1.S.Equal(sig2.S), "same randomness should give the same signature")
+	config3, _ := run(5)
+	assert.NotEqual(t, config1, config3, "different randomness should give a different config")
+}
+
+func TestConfigMarshal(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	configSender, configReceiver := runKeygen(t, group, pl)
+
+	data, err := cbor.Marshal(configSender)
+	require.NoError(t, err)
+	configSender2 := EmptyConfigSender(group)
+	require.NoError(t, cbor.Unmarshal(data, configSender2))
+	assert.Equal(t, *configSender.Setup, *configSender2.Setup)
+	assert.True(t, configSender.SecretShare.Equal(configSender2.SecretShare))
+	assert.True(t, configSender.Public.Equal(configSender2.Public))
+
+	data, err = cbor.Marshal(configReceiver)
+	require.NoError(t, err)
+	configReceiver2 := EmptyConfigReceiver(group)
+	require.NoError(t, cbor.Unmarshal(data, configReceiver2))
+	assert.Equal(t, *configReceiver.Setup, *configReceiver2.Setup)
+
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	resultSender, _ := runTwoParty(t, []byte("session"),
+		SignSender(configSender2, senderID, receiverID, hash, pl),
+		SignReceiver(configReceiver2, receiverID, senderID, hash, pl))
+	assert.True(t, resultSender.(*ecdsa.Signature).Verify(configSender.Public, hash))
+}
+
+func TestSignMismatchedConfigs(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	configSender, _ := runKeygen(t, group, pl)
+	_, configReceiver := runKeygen(t, group, pl)
+	// use the same public key, so that both parties agree on the session
+	configReceiver.Public = configSender.Public
+
+	hash := make([]byte, 32)
+	_, _ = rand.Read(hash)
+	_, _, errSender, errReceiver := tryTwoParty(t, nil,
+		SignSender(configSender, senderID, receiverID, hash, pl),
+		SignReceiver(configReceiver, receiverID, senderID, hash, pl))
+	assert.Error(t, errSender)
+	assert.Error(t, errReceiver)
+}
@@ -0,0 +1,54 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/ot"
+)
+
+// ConfigSender holds the results of key generation for the sender.
+type ConfigSender struct {
+	// Setup is an implementation detail, needed to perform signing.
+	Setup *ot.CorreOTSendSetup
+	// SecretShare is an additive share of the secret key.
+	SecretShare curve.Scalar
+	// Public is the shared public key.
+	Public curve.Point
+}
+
+// EmptyConfigSender creates a ConfigSender with a fixed group, ready for unmarshalling.
+func EmptyConfigSender(group curve.Curve) *ConfigSender {
+	return &ConfigSender{
+		Setup:       new(ot.CorreOTSendSetup),
+		SecretShare: group.NewScalar(),
+		Public:      group.NewPoint(),
+	}
+}
+
+// Group returns the elliptic curve group associated with this config.
+func (c *ConfigSender) Group() curve.Curve {
+	return c.Public.Curve()
+}
+
+// ConfigReceiver holds the results of key generation for the receiver.
+type ConfigReceiver struct {
+	// Setup is an implementation detail, needed to perform signing.
+	Setup *ot.CorreOTReceiveSetup
+	// SecretShare is an additive share of the secret key.
+	SecretShare curve.Scalar
+	// Public is the shared public key.
+	Public curve.Point
+}
+
+// EmptyConfigReceiver creates a ConfigReceiver with a fixed group, ready for unmarshalling.
+func EmptyConfigReceiver(group curve.Curve) *ConfigReceiver {
+	return &ConfigReceiver{
+		Setup:       new(ot.CorreOTReceiveSetup),
+		SecretShare: group.NewScalar(),
+		Public:      group.NewPoint(),
+	}
+}
+
+// Group returns the elliptic curve group associated with this config.
+func (c *ConfigReceiver) Group() curve.Curve {
+	return c.Public.Curve()
+}
@@ -0,0 +1,32 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+// Rounds is the number of rounds in the keygen protocol.
+//
+// The receiver runs the odd rounds, and the sender the even ones.
+const Rounds round.Number = 6
+
+// StartReceiver creates the first round of key generation for the receiver.
+//
+// The receiver must be the leader of the protocol.
+func StartReceiver(helper *round.Helper, otherID party.ID) round.Session {
+	return &round1R{
+		Helper:   helper,
+		OtherID:  otherID,
+		receiver: ot.NewCorreOTSetupReceiver(helper.Rand(), helper.Pool, helper.Hash(), helper.Group()),
+	}
+}
+
+// StartSender creates the first round of key generation for the sender.
+func StartSender(helper *round.Helper, otherID party.ID) round.Session {
+	return &round2S{
+		Helper:  helper,
+		OtherID: otherID,
+		sender:  ot.NewCorreOTSetupSender(helper.Rand(), helper.Pool, helper.Hash()),
+	}
+}
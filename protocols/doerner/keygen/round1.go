@@ -0,0 +1,59 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+var _ round.Round = (*round1R)(nil)
+
+type round1R struct {
+	*round.Helper
+
+	// OtherID is the ID of the sender.
+	OtherID party.ID
+
+	receiver *ot.CorreOTSetupReceiver
+
+	// SecretShare = x₁
+	SecretShare curve.Scalar
+	// PublicShare = X₁ = x₁⋅G
+	PublicShare curve.Point
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1R) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1R) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - sample x₁ and compute X₁ = x₁⋅G, with a proof of knowledge of x₁
+// - start the OT setup
+// - send X₁ and the proof to the sender.
+func (r *round1R) Finalize(out chan<- *round.Message) (round.Session, error) {
+	r.SecretShare, r.PublicShare = sample.ScalarPointPair(r.Rand(), r.Group())
+	Proof := zksch.NewProof(r.Rand(), r.HashForID(r.SelfID()), r.PublicShare, r.SecretShare, nil)
+
+	otMsg := r.receiver.Round1()
+
+	if err := r.SendMessage(out, &message2{
+		OTMsg:       otMsg,
+		PublicShare: r.PublicShare,
+		Proof:       Proof,
+	}, r.OtherID); err != nil {
+		return r, err
+	}
+	return &round3R{round1R: r}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1R) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1R) Number() round.Number { return 1 }
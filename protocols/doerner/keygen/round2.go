@@ -0,0 +1,103 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+var _ round.Round = (*round2S)(nil)
+
+type round2S struct {
+	*round.Helper
+
+	// OtherID is the ID of the receiver.
+	OtherID party.ID
+
+	sender *ot.CorreOTSetupSender
+	otMsg  *ot.CorreOTSetupReceiveRound1Message
+
+	// SecretShare = x₀
+	SecretShare curve.Scalar
+	// PublicShare = X₀ = x₀⋅G
+	PublicShare curve.Point
+	// OtherPublicShare = X₁
+	OtherPublicShare curve.Point
+}
+
+type message2 struct {
+	OTMsg *ot.CorreOTSetupReceiveRound1Message
+	// PublicShare = X₁
+	PublicShare curve.Point
+	// Proof is a proof of knowledge of x₁
+	Proof *zksch.Proof
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify the proof of knowledge of x₁.
+func (r *round2S) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.OTMsg == nil || body.PublicShare.IsIdentity() {
+		return round.ErrNilFields
+	}
+	if !body.Proof.Verify(r.HashForID(msg.From), body.PublicShare, nil) {
+		return errors.New("failed to validate Schnorr proof")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round2S) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message2)
+	r.otMsg = body.OTMsg
+	r.OtherPublicShare = body.PublicShare
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - sample x₀ and compute X₀ = x₀⋅G, with a proof of knowledge of x₀
+// - continue the OT setup
+// - send X₀ and the proof to the receiver.
+func (r *round2S) Finalize(out chan<- *round.Message) (round.Session, error) {
+	otMsg, err := r.sender.Round1(r.otMsg)
+	if err != nil {
+		return r.AbortRound(err, r.OtherID), nil
+	}
+
+	r.SecretShare, r.PublicShare = sample.ScalarPointPair(r.Rand(), r.Group())
+	Proof := zksch.NewProof(r.Rand(), r.HashForID(r.SelfID()), r.PublicShare, r.SecretShare, nil)
+
+	if err = r.SendMessage(out, &message3{
+		OTMsg:       otMsg,
+		PublicShare: r.PublicShare,
+		Proof:       Proof,
+	}, r.OtherID); err != nil {
+		return r, err
+	}
+	return &round4S{round2S: r}, nil
+}
+
+// RoundNumber implements round.Content.
+func (message2) RoundNumber() round.Number { return 2 }
+
+// MessageContent implements round.Round.
+func (r *round2S) MessageContent() round.Content {
+	return &message2{
+		OTMsg:       ot.EmptyCorreOTSetupReceiveRound1Message(r.Group()),
+		PublicShare: r.Group().NewPoint(),
+		Proof:       zksch.EmptyProof(r.Group()),
+	}
+}
+
+// Number implements round.Round.
+func (round2S) Number() round.Number { return 2 }
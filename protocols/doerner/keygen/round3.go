@@ -0,0 +1,82 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+var _ round.Round = (*round3R)(nil)
+
+type round3R struct {
+	*round1R
+
+	otMsg *ot.CorreOTSetupSendRound1Message
+
+	// OtherPublicShare = X₀
+	OtherPublicShare curve.Point
+}
+
+type message3 struct {
+	OTMsg *ot.CorreOTSetupSendRound1Message
+	// PublicShare = X₀
+	PublicShare curve.Point
+	// Proof is a proof of knowledge of x₀
+	Proof *zksch.Proof
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify the proof of knowledge of x₀.
+func (r *round3R) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.OTMsg == nil || body.PublicShare.IsIdentity() {
+		return round.ErrNilFields
+	}
+	if !body.Proof.Verify(r.HashForID(msg.From), body.PublicShare, nil) {
+		return errors.New("failed to validate Schnorr proof")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round3R) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message3)
+	r.otMsg = body.OTMsg
+	r.OtherPublicShare = body.PublicShare
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - continue the OT setup.
+func (r *round3R) Finalize(out chan<- *round.Message) (round.Session, error) {
+	otMsg, err := r.receiver.Round2(r.otMsg)
+	if err != nil {
+		return r.AbortRound(err, r.OtherID), nil
+	}
+	if err = r.SendMessage(out, &message4{OTMsg: otMsg}, r.OtherID); err != nil {
+		return r, err
+	}
+	return &round5R{round3R: r}, nil
+}
+
+// RoundNumber implements round.Content.
+func (message3) RoundNumber() round.Number { return 3 }
+
+// MessageContent implements round.Round.
+func (r *round3R) MessageContent() round.Content {
+	return &message3{
+		PublicShare: r.Group().NewPoint(),
+		Proof:       zksch.EmptyProof(r.Group()),
+	}
+}
+
+// Number implements round.Round.
+func (round3R) Number() round.Number { return 3 }
@@ -0,0 +1,56 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*round4S)(nil)
+
+type round4S struct {
+	*round2S
+
+	otMsg *ot.CorreOTSetupReceiveRound2Message
+}
+
+type message4 struct {
+	OTMsg *ot.CorreOTSetupReceiveRound2Message
+}
+
+// VerifyMessage implements round.Round.
+func (r *round4S) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message4)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.OTMsg == nil {
+		return round.ErrNilFields
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round4S) StoreMessage(msg round.Message) error {
+	r.otMsg = msg.Content.(*message4).OTMsg
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - continue the OT setup.
+func (r *round4S) Finalize(out chan<- *round.Message) (round.Session, error) {
+	otMsg := r.sender.Round2(r.otMsg)
+	if err := r.SendMessage(out, &message5{OTMsg: otMsg}, r.OtherID); err != nil {
+		return r, err
+	}
+	return &round6S{round4S: r}, nil
+}
+
+// RoundNumber implements round.Content.
+func (message4) RoundNumber() round.Number { return 4 }
+
+// MessageContent implements round.Round.
+func (round4S) MessageContent() round.Content { return &message4{} }
+
+// Number implements round.Round.
+func (round4S) Number() round.Number { return 4 }
@@ -0,0 +1,64 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*round5R)(nil)
+
+type round5R struct {
+	*round3R
+
+	otMsg *ot.CorreOTSetupSendRound2Message
+}
+
+type message5 struct {
+	OTMsg *ot.CorreOTSetupSendRound2Message
+}
+
+// VerifyMessage implements round.Round.
+func (r *round5R) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message5)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.OTMsg == nil {
+		return round.ErrNilFields
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round5R) StoreMessage(msg round.Message) error {
+	r.otMsg = msg.Content.(*message5).OTMsg
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - finish the OT setup, and send the last message to the sender
+// - output the config, with X = X₀ + X₁.
+func (r *round5R) Finalize(out chan<- *round.Message) (round.Session, error) {
+	otMsg, setup, err := r.receiver.Round3(r.otMsg)
+	if err != nil {
+		return r.AbortRound(err, r.OtherID), nil
+	}
+	if err = r.SendMessage(out, &message6{OTMsg: otMsg}, r.OtherID); err != nil {
+		return r, err
+	}
+	return r.ResultRound(&ConfigReceiver{
+		Setup:       setup,
+		SecretShare: r.SecretShare,
+		Public:      r.PublicShare.Add(r.OtherPublicShare),
+	}), nil
+}
+
+// RoundNumber implements round.Content.
+func (message5) RoundNumber() round.Number { return 5 }
+
+// MessageContent implements round.Round.
+func (round5R) MessageContent() round.Content { return &message5{} }
+
+// Number implements round.Round.
+func (round5R) Number() round.Number { return 5 }
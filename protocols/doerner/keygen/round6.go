@@ -0,0 +1,61 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*round6S)(nil)
+
+type round6S struct {
+	*round4S
+
+	otMsg *ot.CorreOTSetupReceiveRound3Message
+}
+
+type message6 struct {
+	OTMsg *ot.CorreOTSetupReceiveRound3Message
+}
+
+// VerifyMessage implements round.Round.
+func (r *round6S) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message6)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.OTMsg == nil {
+		return round.ErrNilFields
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round6S) StoreMessage(msg round.Message) error {
+	r.otMsg = msg.Content.(*message6).OTMsg
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - finish the OT setup
+// - output the config, with X = X₀ + X₁.
+func (r *round6S) Finalize(chan<- *round.Message) (round.Session, error) {
+	setup, err := r.sender.Round3(r.otMsg)
+	if err != nil {
+		return r.AbortRound(err, r.OtherID), nil
+	}
+	return r.ResultRound(&ConfigSender{
+		Setup:       setup,
+		SecretShare: r.SecretShare,
+		Public:      r.PublicShare.Add(r.OtherPublicShare),
+	}), nil
+}
+
+// RoundNumber implements round.Content.
+func (message6) RoundNumber() round.Number { return 6 }
+
+// MessageContent implements round.Round.
+func (round6S) MessageContent() round.Content { return &message6{} }
+
+// Number implements round.Round.
+func (round6S) Number() round.Number { return 6 }
@@ -0,0 +1,78 @@
+package sign
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+	"github.com/w3-key/mps-lean/protocols/doerner/keygen"
+)
+
+var _ round.Round = (*round1R)(nil)
+
+type round1R struct {
+	*round.Helper
+
+	Config *keygen.ConfigReceiver
+	// OtherID is the ID of the sender.
+	OtherID party.ID
+	// Message is the hash of the message to be signed.
+	Message []byte
+
+	// Nonce = k₁
+	Nonce curve.Scalar
+	// NonceCommitment = D = k₁⋅G
+	NonceCommitment curve.Point
+
+	receivers [numMultiplications]*ot.MultiplyReceiver
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1R) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1R) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - sample k₁ and compute D = k₁⋅G, with a proof of knowledge of k₁
+// - start the multiplications with inputs 1/k₁, 1/k₁ and x₁/k₁
+// - send D, the proof and the multiplication messages to the sender.
+func (r *round1R) Finalize(out chan<- *round.Message) (round.Session, error) {
+	r.Nonce, r.NonceCommitment = sample.ScalarPointPair(r.Rand(), r.Group())
+	Proof := zksch.NewProof(r.Rand(), r.HashForID(r.SelfID()), r.NonceCommitment, r.Nonce, nil)
+
+	// 1/k₁
+	kInv := r.Group().NewScalar().Set(r.Nonce).Invert()
+	// x₁/k₁
+	xkInv := r.Group().NewScalar().Set(kInv).Mul(r.Config.SecretShare)
+	inputs := [numMultiplications]curve.Scalar{kInv, kInv, xkInv}
+
+	h := r.Hash()
+	var multiplyMsgs [numMultiplications]*ot.MultiplyReceiveRound1Message
+	for i := 0; i < numMultiplications; i++ {
+		receiver, err := ot.NewMultiplyReceiver(r.Rand(), multiplyHash(h, r.NonceCommitment, i), r.Config.Setup, inputs[i])
+		if err != nil {
+			return r, err
+		}
+		r.receivers[i] = receiver
+		multiplyMsgs[i] = receiver.Round1()
+	}
+
+	if err := r.SendMessage(out, &message2{
+		NonceCommitment: r.NonceCommitment,
+		Proof:           Proof,
+		MultiplyMsgs:    multiplyMsgs,
+	}, r.OtherID); err != nil {
+		return r, err
+	}
+	return &round3R{round1R: r}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1R) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1R) Number() round.Number { return 1 }
@@ -0,0 +1,148 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+	"github.com/w3-key/mps-lean/protocols/doerner/keygen"
+)
+
+var _ round.Round = (*round2S)(nil)
+
+type round2S struct {
+	*round.Helper
+
+	Config *keygen.ConfigSender
+	// OtherID is the ID of the receiver.
+	OtherID party.ID
+	// Message is the hash of the message to be signed.
+	Message []byte
+
+	// OtherNonceCommitment = D = k₁⋅G
+	OtherNonceCommitment curve.Point
+	multiplyMsgs         [numMultiplications]*ot.MultiplyReceiveRound1Message
+
+	// R = k₀⋅k₁⋅G
+	R curve.Point
+	// SigmaShare = s₀
+	SigmaShare curve.Scalar
+}
+
+type message2 struct {
+	// NonceCommitment = D = k₁⋅G
+	NonceCommitment curve.Point
+	// Proof is a proof of knowledge of k₁
+	Proof        *zksch.Proof
+	MultiplyMsgs [numMultiplications]*ot.MultiplyReceiveRound1Message
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify the proof of knowledge of k₁.
+func (r *round2S) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.NonceCommitment.IsIdentity() {
+		return round.ErrNilFields
+	}
+	for _, m := range body.MultiplyMsgs {
+		if m == nil || m.Msg == nil || m.Msg.Msg == nil || m.Msg.Msg.CorreMsg == nil {
+			return round.ErrNilFields
+		}
+	}
+	if !body.Proof.Verify(r.HashForID(msg.From), body.NonceCommitment, nil) {
+		return errors.New("failed to validate Schnorr proof")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round2S) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message2)
+	r.OtherNonceCommitment = body.NonceCommitment
+	r.multiplyMsgs = body.MultiplyMsgs
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - sample k₀', compute R' = k₀'⋅D, k₀ = H(R') + k₀' and R = k₀⋅D
+// - compute R₀ = k₀⋅G, with a proof of knowledge of k₀
+// - sample φ and run the multiplications with inputs φ + 1/k₀, x₀/k₀ and 1/k₀, obtaining shares a₀, b₀, c₀
+// - compute s₀ = m⋅a₀ + r⋅(b₀ + c₀)
+// - compute Γ¹ = G + φ⋅k₀⋅G − a₀⋅R and Γ² = a₀⋅X − (b₀ + c₀)⋅G
+// - send R', R₀, the proof, the multiplication messages, ηᵠ = H(Γ¹) + φ and ηˢ = H(Γ²) + s₀ to the receiver.
+func (r *round2S) Finalize(out chan<- *round.Message) (round.Session, error) {
+	group := r.Group()
+	h := r.Hash()
+
+	kPrime := sample.Scalar(r.Rand(), group)
+	RPrime := kPrime.Act(r.OtherNonceCommitment)
+	k := nonceOffset(h, RPrime).Add(kPrime)
+	r.R = k.Act(r.OtherNonceCommitment)
+	NonceShare := k.ActOnBase()
+	Proof := zksch.NewProof(r.Rand(), r.HashForID(r.SelfID()), NonceShare, k, nil)
+
+	phi := sample.Scalar(r.Rand(), group)
+	// 1/k₀
+	kInv := group.NewScalar().Set(k).Invert()
+	// x₀/k₀
+	xkInv := group.NewScalar().Set(kInv).Mul(r.Config.SecretShare)
+	// φ + 1/k₀
+	phiKInv := group.NewScalar().Set(phi).Add(kInv)
+	inputs := [numMultiplications]curve.Scalar{phiKInv, xkInv, kInv}
+
+	var (
+		multiplyMsgs [numMultiplications]*ot.MultiplySendRound1Message
+		shares       [numMultiplications]curve.Scalar
+		err          error
+	)
+	for i := 0; i < numMultiplications; i++ {
+		sender := ot.NewMultiplySender(r.Rand(), multiplyHash(h, r.OtherNonceCommitment, i), r.Config.Setup, inputs[i])
+		multiplyMsgs[i], shares[i], err = sender.Round1(r.multiplyMsgs[i])
+		if err != nil {
+			return r.AbortRound(err, r.OtherID), nil
+		}
+	}
+
+	m := curve.FromHash(group, r.Message)
+	r.SigmaShare = signatureShare(m, r.R.XScalar(), shares)
+
+	// Γ¹ = G + φ⋅k₀⋅G − a₀⋅R
+	Gamma1 := group.NewBasePoint().Add(group.NewScalar().Set(phi).Mul(k).ActOnBase()).Sub(shares[0].Act(r.R))
+	// Γ² = a₀⋅X − (b₀ + c₀)⋅G
+	Gamma2 := shares[0].Act(r.Config.Public).Sub(group.NewScalar().Set(shares[1]).Add(shares[2]).ActOnBase())
+
+	if err = r.SendMessage(out, &message3{
+		RPrime:           RPrime,
+		NonceShare:       NonceShare,
+		Proof:            Proof,
+		MultiplyMsgs:     multiplyMsgs,
+		MaskedPhi:        consistencyMask(h, Gamma1, 1).Add(phi),
+		MaskedSigmaShare: consistencyMask(h, Gamma2, 2).Add(r.SigmaShare),
+	}, r.OtherID); err != nil {
+		return r, err
+	}
+	return &round4S{round2S: r}, nil
+}
+
+// RoundNumber implements round.Content.
+func (message2) RoundNumber() round.Number { return 2 }
+
+// MessageContent implements round.Round.
+func (r *round2S) MessageContent() round.Content {
+	return &message2{
+		NonceCommitment: r.Group().NewPoint(),
+		Proof:           zksch.EmptyProof(r.Group()),
+	}
+}
+
+// Number implements round.Round.
+func (round2S) Number() round.Number { return 2 }
@@ -0,0 +1,157 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/round"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+var _ round.Round = (*round3R)(nil)
+
+type round3R struct {
+	*round1R
+
+	// RPrime = R' = k₀'⋅D
+	RPrime curve.Point
+	// OtherNonceShare = R₀ = k₀⋅G
+	OtherNonceShare curve.Point
+	multiplyMsgs    [numMultiplications]*ot.MultiplySendRound1Message
+	// MaskedPhi = ηᵠ = H(Γ¹) + φ
+	MaskedPhi curve.Scalar
+	// MaskedSigmaShare = ηˢ = H(Γ²) + s₀
+	MaskedSigmaShare curve.Scalar
+}
+
+type message3 struct {
+	// RPrime = R' = k₀'⋅D
+	RPrime curve.Point
+	// NonceShare = R₀ = k₀⋅G
+	NonceShare curve.Point
+	// Proof is a proof of knowledge of k₀
+	Proof        *zksch.Proof
+	MultiplyMsgs [numMultiplications]*ot.MultiplySendRound1Message
+	// MaskedPhi = ηᵠ = H(Γ¹) + φ
+	MaskedPhi curve.Scalar
+	// MaskedSigmaShare = ηˢ = H(Γ²) + s₀
+	MaskedSigmaShare curve.Scalar
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify the proof of knowledge of k₀.
+func (r *round3R) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.RPrime.IsIdentity() || body.NonceShare.IsIdentity() || body.MaskedPhi.IsZero() || body.MaskedSigmaShare.IsZero() {
+		return round.ErrNilFields
+	}
+	for _, m := range body.MultiplyMsgs {
+		if m == nil || m.Msg == nil {
+			return round.ErrNilFields
+		}
+	}
+	if !body.Proof.Verify(r.HashForID(msg.From), body.NonceShare, nil) {
+		return errors.New("failed to validate Schnorr proof")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round3R) StoreMessage(msg round.Message) error {
+	body := msg.Content.(*message3)
+	r.RPrime = body.RPrime
+	r.OtherNonceShare = body.NonceShare
+	r.multiplyMsgs = body.MultiplyMsgs
+	r.MaskedPhi = body.MaskedPhi
+	r.MaskedSigmaShare = body.MaskedSigmaShare
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - compute R = k₁⋅R₀, and check that R = R' + H(R')⋅D
+// - finish the multiplications, obtaining shares a₁, b₁, c₁
+// - unmask the sender's values and compute the signature (R, s), see signature
+// - verify the signature (R, s), and send s₁ to the sender.
+func (r *round3R) Finalize(out chan<- *round.Message) (round.Session, error) {
+	h := r.Hash()
+
+	R := r.Nonce.Act(r.OtherNonceShare)
+	expectedR := nonceOffset(h, r.RPrime).Act(r.NonceCommitment).Add(r.RPrime)
+	if !R.Equal(expectedR) {
+		return r.AbortRound(errors.New("inconsistent nonce"), r.OtherID), nil
+	}
+
+	var shares [numMultiplications]curve.Scalar
+	for i := 0; i < numMultiplications; i++ {
+		share, err := r.receivers[i].Round2(r.multiplyMsgs[i])
+		if err != nil {
+			return r.AbortRound(err, r.OtherID), nil
+		}
+		shares[i] = share
+	}
+
+	SigmaShare, signature := r.signature(R, shares)
+	if !signature.Verify(r.Config.Public, r.Message) {
+		return r.AbortRound(errors.New("failed to validate signature"), r.OtherID), nil
+	}
+
+	if err := r.SendMessage(out, &message4{SigmaShare: SigmaShare}, r.OtherID); err != nil {
+		return r, err
+	}
+	return r.ResultRound(signature), nil
+}
+
+// signature returns s₁ and the signature (R, s), given the shares a₁, b₁, c₁ of the multiplications.
+//
+// - compute Γ¹ = a₁⋅R and φ = ηᵠ − H(Γ¹)
+// - compute θ = a₁ − φ/k₁, so that a₀ + θ is a share of 1/k
+// - compute s₁ = m⋅θ + r⋅(b₁ + c₁)
+// - compute Γ² = (b₁ + c₁)⋅G − θ⋅X and s = s₁ + ηˢ − H(Γ²).
+//
+// If the receiver's multiplication inputs were not 1/k₁, 1/k₁ and x₁/k₁, it cannot compute Γ¹ and Γ²,
+// and s is not a valid signature.
+func (r *round3R) signature(R curve.Point, shares [numMultiplications]curve.Scalar) (curve.Scalar, *ecdsa.Signature) {
+	group := r.Group()
+	h := r.Hash()
+
+	Gamma1 := shares[0].Act(R)
+	phi := group.NewScalar().Set(r.MaskedPhi).Sub(consistencyMask(h, Gamma1, 1))
+	theta := group.NewScalar().Set(r.Nonce).Invert().Mul(phi).Negate().Add(shares[0])
+
+	m := curve.FromHash(group, r.Message)
+	SigmaShare := signatureShare(m, R.XScalar(), [numMultiplications]curve.Scalar{theta, shares[1], shares[2]})
+
+	xk := group.NewScalar().Set(shares[1]).Add(shares[2])
+	Gamma2 := xk.ActOnBase().Sub(theta.Act(r.Config.Public))
+	S := group.NewScalar().Set(r.MaskedSigmaShare).Sub(consistencyMask(h, Gamma2, 2)).Add(SigmaShare)
+	return SigmaShare, &ecdsa.Signature{R: R, S: S}
+}
+
+// RoundNumber implements round.Content.
+func (message3) RoundNumber() round.Number { return 3 }
+
+// MessageContent implements round.Round.
+func (r *round3R) MessageContent() round.Content {
+	var multiplyMsgs [numMultiplications]*ot.MultiplySendRound1Message
+	for i, receiver := range r.receivers {
+		multiplyMsgs[i] = receiver.EmptyMultiplySendRound1Message()
+	}
+	return &message3{
+		RPrime:           r.Group().NewPoint(),
+		NonceShare:       r.Group().NewPoint(),
+		Proof:            zksch.EmptyProof(r.Group()),
+		MultiplyMsgs:     multiplyMsgs,
+		MaskedPhi:        r.Group().NewScalar(),
+		MaskedSigmaShare: r.Group().NewScalar(),
+	}
+}
+
+// Number implements round.Round.
+func (round3R) Number() round.Number { return 3 }
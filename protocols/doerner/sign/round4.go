@@ -0,0 +1,67 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/ecdsa"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*round4S)(nil)
+
+type round4S struct {
+	*round2S
+
+	// OtherSigmaShare = s₁
+	OtherSigmaShare curve.Scalar
+}
+
+type message4 struct {
+	// SigmaShare = s₁
+	SigmaShare curve.Scalar
+}
+
+// VerifyMessage implements round.Round.
+func (r *round4S) VerifyMessage(msg round.Message) error {
+	body, ok := msg.Content.(*message4)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.SigmaShare.IsZero() {
+		return round.ErrNilFields
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+func (r *round4S) StoreMessage(msg round.Message) error {
+	r.OtherSigmaShare = msg.Content.(*message4).SigmaShare
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - compute s = s₀ + s₁
+// - verify the signature (R, s).
+func (r *round4S) Finalize(chan<- *round.Message) (round.Session, error) {
+	signature := &ecdsa.Signature{
+		R: r.R,
+		S: r.Group().NewScalar().Set(r.SigmaShare).Add(r.OtherSigmaShare),
+	}
+	if !signature.Verify(r.Config.Public, r.Message) {
+		return r.AbortRound(errors.New("failed to validate signature"), r.OtherID), nil
+	}
+	return r.ResultRound(signature), nil
+}
+
+// RoundNumber implements round.Content.
+func (message4) RoundNumber() round.Number { return 4 }
+
+// MessageContent implements round.Round.
+func (r *round4S) MessageContent() round.Content {
+	return &message4{SigmaShare: r.Group().NewScalar()}
+}
+
+// Number implements round.Round.
+func (round4S) Number() round.Number { return 4 }
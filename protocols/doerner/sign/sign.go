@@ -0,0 +1,74 @@
+package sign
+
+import (
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/doerner/keygen"
+)
+
+// Rounds is the number of rounds in the signing protocol.
+//
+// The receiver runs the odd rounds, and the sender the even ones.
+const Rounds round.Number = 4
+
+// StartReceiver creates the first round of signing for the receiver.
+//
+// The receiver must be the leader of the protocol.
+func StartReceiver(config *keygen.ConfigReceiver, otherID party.ID, hash []byte, helper *round.Helper) round.Session {
+	return &round1R{
+		Helper:  helper,
+		Config:  config,
+		OtherID: otherID,
+		Message: hash,
+	}
+}
+
+// StartSender creates the first round of signing for the sender.
+func StartSender(config *keygen.ConfigSender, otherID party.ID, hash []byte, helper *round.Helper) round.Session {
+	return &round2S{
+		Helper:  helper,
+		Config:  config,
+		OtherID: otherID,
+		Message: hash,
+	}
+}
+
+// numMultiplications is the number of OT based multiplications performed during signing.
+//
+// With k = k₀⋅k₁ and x = x₀ + x₁, we need additive shares of
+//
+//	1/k   = (1/k₀)⋅(1/k₁)
+//	x/k   = (x₀/k₀)⋅(1/k₁) + (1/k₀)⋅(x₁/k₁)
+//
+// As in DKLs18, the sender adds a random φ to its first input, obtaining shares of (φ + 1/k₀)/k₁ instead.
+// It then only reveals φ and s₀ masked by H(Γ¹) and H(Γ²), which the receiver can only compute if its
+// inputs were consistent with D = k₁⋅G and the public key X.
+const numMultiplications = 3
+
+// multiplyHash returns the hash used for the i-th multiplication.
+//
+// It includes the receiver's nonce D = k₁⋅G, so that the OT setup is never used with the same hash twice.
+func multiplyHash(h *hash.Hash, D curve.Point, i int) *hash.Hash {
+	return h.Fork(D, &hash.BytesWithDomain{TheDomain: "Doerner Multiply", Bytes: []byte{byte(i)}})
+}
+
+// nonceOffset returns H(R') used by the sender to derive k₀ = H(R') + k₀'.
+func nonceOffset(h *hash.Hash, RPrime curve.Point) curve.Scalar {
+	return sample.Scalar(h.Fork(RPrime).Digest(), RPrime.Curve())
+}
+
+// consistencyMask returns H(Γ), used by the sender to mask φ (i = 1) and s₀ (i = 2).
+func consistencyMask(h *hash.Hash, Gamma curve.Point, i int) curve.Scalar {
+	return sample.Scalar(h.Fork(Gamma, &hash.BytesWithDomain{TheDomain: "Doerner Consistency", Bytes: []byte{byte(i)}}).Digest(), Gamma.Curve())
+}
+
+// signatureShare returns sᵢ = m⋅aᵢ + r⋅(bᵢ + cᵢ), where aᵢ is a share of 1/k and bᵢ + cᵢ a share of x/k.
+func signatureShare(m, r curve.Scalar, shares [numMultiplications]curve.Scalar) curve.Scalar {
+	group := m.Curve()
+	s := group.NewScalar().Set(m).Mul(shares[0])
+	xk := group.NewScalar().Set(shares[1]).Add(shares[2])
+	return s.Add(xk.Mul(r))
+}
@@ -0,0 +1,134 @@
+package sign
+
+import (
+	"crypto/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/ot"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/pool"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/doerner/keygen"
+)
+
+const (
+	senderID   party.ID = "sender"
+	receiverID party.ID = "receiver"
+)
+
+func newHelper(t *testing.T, group curve.Curve, protocolID string, finalRound round.Number, selfID, otherID party.ID, pl *pool.Pool) *round.Helper {
+	info := round.Info{
+		ProtocolID:       protocolID,
+		FinalRoundNumber: finalRound,
+		SelfID:           selfID,
+		PartyIDs:         []party.ID{selfID, otherID},
+		Threshold:        1,
+		Group:            group,
+	}
+	helper, err := round.NewSession(info, nil, pl)
+	require.NoError(t, err)
+	return helper
+}
+
+func runKeygen(t *testing.T, group curve.Curve, pl *pool.Pool) (*keygen.ConfigSender, *keygen.ConfigReceiver) {
+	hSender, err := protocol.NewTwoPartyHandler(func([]byte) (round.Session, error) {
+		return keygen.StartSender(newHelper(t, group, "doerner/keygen", keygen.Rounds, senderID, receiverID, pl), receiverID), nil
+	}, nil, false)
+	require.NoError(t, err)
+	hReceiver, err := protocol.NewTwoPartyHandler(func([]byte) (round.Session, error) {
+		return keygen.StartReceiver(newHelper(t, group, "doerner/keygen", keygen.Rounds, receiverID, senderID, pl), senderID), nil
+	}, nil, true)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	relay := func(from, to *protocol.TwoPartyHandler) {
+		defer wg.Done()
+		for msg := range from.Listen() {
+			to.Accept(msg)
+		}
+	}
+	wg.Add(2)
+	go relay(hSender, hReceiver)
+	go relay(hReceiver, hSender)
+	wg.Wait()
+
+	resultSender, err := hSender.Result()
+	require.NoError(t, err)
+	resultReceiver, err := hReceiver.Result()
+	require.NoError(t, err)
+	return resultSender.(*keygen.ConfigSender), resultReceiver.(*keygen.ConfigReceiver)
+}
+
+// deliver verifies and stores the single message in out for r.
+func deliver(t *testing.T, r round.Round, out chan *round.Message) {
+	require.Len(t, out, 1)
+	msg := <-out
+	require.NoError(t, r.VerifyMessage(*msg))
+	require.NoError(t, r.StoreMessage(*msg))
+}
+
+// TestMaliciousReceiverInput checks that a receiver which replaces its first multiplication input
+// 1/k₁ by (m'/m)/k₁ cannot obtain a signature, neither on m nor on m'.
+//
+// Without the consistency check, s₀ + s₁ would be a valid signature on m'.
+func TestMaliciousReceiverInput(t *testing.T) {
+	pl := pool.NewPool(0)
+	defer pl.TearDown()
+	group := curve.Secp256k1{}
+
+	configSender, configReceiver := runKeygen(t, group, pl)
+
+	message := make([]byte, 32)
+	forged := make([]byte, 32)
+	_, _ = rand.Read(message)
+	_, _ = rand.Read(forged)
+
+	for _, malicious := range []bool{false, true} {
+		receiver := StartReceiver(configReceiver, senderID, message, newHelper(t, group, "doerner/sign", Rounds, receiverID, senderID, pl)).(*round1R)
+		sender := StartSender(configSender, receiverID, message, newHelper(t, group, "doerner/sign", Rounds, senderID, receiverID, pl)).(*round2S)
+
+		out := make(chan *round.Message, 1)
+		r, err := receiver.Finalize(out)
+		require.NoError(t, err)
+		r3 := r.(*round3R)
+		if malicious {
+			// (m'/m)/k₁
+			input := curve.FromHash(group, message).Invert().Mul(curve.FromHash(group, forged))
+			input.Mul(group.NewScalar().Set(r3.Nonce).Invert())
+			multiplier, err := ot.NewMultiplyReceiver(rand.Reader, multiplyHash(r3.Hash(), r3.NonceCommitment, 0), configReceiver.Setup, input)
+			require.NoError(t, err)
+			r3.receivers[0] = multiplier
+			msg := <-out
+			msg.Content.(*message2).MultiplyMsgs[0] = multiplier.Round1()
+			out <- msg
+		}
+		deliver(t, sender, out)
+		_, err = sender.Finalize(out)
+		require.NoError(t, err)
+		deliver(t, r3, out)
+
+		R := r3.Nonce.Act(r3.OtherNonceShare)
+		var shares [numMultiplications]curve.Scalar
+		for i := range shares {
+			shares[i], err = r3.receivers[i].Round2(r3.multiplyMsgs[i])
+			require.NoError(t, err)
+		}
+		_, signature := r3.signature(R, shares)
+		if !malicious {
+			assert.True(t, signature.Verify(configReceiver.Public, message))
+			continue
+		}
+		assert.False(t, signature.Verify(configReceiver.Public, message))
+		assert.False(t, signature.Verify(configReceiver.Public, forged))
+
+		rNext, err := r3.Finalize(out)
+		require.NoError(t, err)
+		require.IsType(t, &round.Abort{}, rNext)
+		assert.Empty(t, out, "s₁ should not be sent")
+	}
+}
@@ -0,0 +1,92 @@
+package frost
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/frost/keygen"
+	"github.com/w3-key/mps-lean/protocols/frost/sign"
+)
+
+type (
+	// Config contains the result of key generation, and is needed for signing.
+	// It contains secret key material and should be safely stored.
+	Config = keygen.Config
+	// TaprootConfig is like Config, but the public key is a BIP-340 public key.
+	TaprootConfig = keygen.TaprootConfig
+	// Signature is a Schnorr signature produced by Sign.
+	Signature = sign.Signature
+)
+
+// EmptyConfig creates an empty Config with a fixed group, ready for unmarshalling.
+//
+// This needs to be used for unmarshalling, otherwise the points on the curve can't
+// be decoded.
+func EmptyConfig(group curve.Curve) *Config {
+	return keygen.EmptyConfig(group)
+}
+
+// EmptySignature creates an empty Signature with a fixed group, ready for unmarshalling.
+func EmptySignature(group curve.Curve) Signature {
+	return sign.EmptySignature(group)
+}
+
+// Keygen generates a new shared Schnorr key over the curve defined by `group`.
+// After a successful execution, all participants posses a unique share of this key,
+// which is needed for signing.
+//
+// Any subset of threshold+1 participants can later create a signature.
+// With curve.Edwards25519, the encoding of the public key is an Ed25519 public key.
+// All randomness of this party is read from crypto/rand, or from the reader set with round.WithRand.
+// Returns *frost.Config if successful.
+func Keygen(group curve.Curve, selfID party.ID, participants []party.ID, threshold int, opts ...round.Option) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "frost/keygen-threshold",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        threshold,
+		Group:            group,
+	}
+	info.Apply(opts...)
+	return keygen.Start(info, false)
+}
+
+// KeygenTaproot is like Keygen, but produces a key compatible with BIP-340, over secp256k1.
+//
+// Returns *frost.TaprootConfig if successful.
+func KeygenTaproot(selfID party.ID, participants []party.ID, threshold int, opts ...round.Option) protocol.StartFunc {
+	info := round.Info{
+		ProtocolID:       "frost/keygen-threshold-taproot",
+		FinalRoundNumber: keygen.Rounds,
+		SelfID:           selfID,
+		PartyIDs:         participants,
+		Threshold:        threshold,
+		Group:            curve.Secp256k1{},
+	}
+	info.Apply(opts...)
+	return keygen.Start(info, true)
+}
+
+// Sign generates a Schnorr signature for `messageHash`, with the key from a previous Keygen.
+//
+// `signers` must contain at least config.Threshold+1 participants, including config.ID.
+// Over curve.Edwards25519, `messageHash` is the message itself, and the signature can be
+// converted with Signature.Ed25519 into an RFC 8032 signature.
+// Returns *frost.Signature if successful.
+func Sign(config *Config, signers []party.ID, messageHash []byte, opts ...round.Option) protocol.StartFunc {
+	return sign.Start(config, signers, messageHash, false, opts...)
+}
+
+// SignTaproot generates a BIP-340 signature for `messageHash`, with the key from a previous KeygenTaproot.
+//
+// The signature can be used for a Taproot key-path spend, and verified with config.PublicKey.
+// Returns taproot.Signature if successful.
+func SignTaproot(config *TaprootConfig, signers []party.ID, messageHash []byte, opts ...round.Option) protocol.StartFunc {
+	genericConfig, err := config.Config()
+	if err != nil {
+		return func([]byte) (round.Session, error) { return nil, err }
+	}
+	return sign.Start(genericConfig, signers, messageHash, true, opts...)
+}
@@ -0,0 +1,89 @@
+package frost
+
+import (
+	"crypto/ed25519"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/taproot"
+	"github.com/w3-key/mps-lean/pkg/test"
+)
+
+func do(t *testing.T, id party.ID, ids []party.ID, threshold int, message []byte, n *test.Network, wg *sync.WaitGroup) {
+	defer wg.Done()
+	h, err := protocol.NewMultiHandler(Keygen(curve.Secp256k1{}, id, ids, threshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	c := r.(*Config)
+
+	h, err = protocol.NewMultiHandler(KeygenTaproot(id, ids, threshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &TaprootConfig{}, r)
+	c0Taproot := r.(*TaprootConfig)
+
+	h, err = protocol.NewMultiHandler(Sign(c, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+	signResult, err := h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Signature{}, signResult)
+	signature := signResult.(*Signature)
+	assert.True(t, signature.Verify(c.PublicKey, message))
+
+	h, err = protocol.NewMultiHandler(Keygen(curve.Edwards25519{}, id, ids, threshold), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(id, h, n)
+	r, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Config{}, r)
+	cEd25519 := r.(*Config)
+
+	h, err = protocol.NewMultiHandler(Sign(cEd25519, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, &Signature{}, signResult)
+	ed25519Signature, err := signResult.(*Signature).Ed25519()
+	require.NoError(t, err)
+	ed25519PublicKey, err := cEd25519.PublicKey.MarshalBinary()
+	require.NoError(t, err)
+	assert.True(t, ed25519.Verify(ed25519PublicKey, message, ed25519Signature))
+
+	h, err = protocol.NewMultiHandler(SignTaproot(c0Taproot, ids, message), nil)
+	require.NoError(t, err)
+	test.HandlerLoop(c.ID, h, n)
+	signResult, err = h.Result()
+	require.NoError(t, err)
+	require.IsType(t, taproot.Signature{}, signResult)
+	taprootSignature := signResult.(taproot.Signature)
+	assert.True(t, c0Taproot.PublicKey.Verify(taprootSignature, message))
+}
+
+func TestFrost(t *testing.T) {
+	N := 5
+	T := N - 1
+	message := []byte("hello")
+
+	partyIDs := test.PartyIDs(N)
+
+	n := test.NewNetwork(partyIDs)
+
+	var wg sync.WaitGroup
+	for _, id := range partyIDs {
+		wg.Add(1)
+		go do(t, id, partyIDs, T, message, n, &wg)
+	}
+	wg.Wait()
+}
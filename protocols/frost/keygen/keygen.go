@@ -0,0 +1,43 @@
+package keygen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+// Rounds is the number of rounds in the FROST keygen protocol.
+const Rounds round.Number = 3
+
+// Start returns the first round of the FROST key generation.
+//
+// If taproot is set, the group must be Secp256k1, and the result is a *TaprootConfig
+// whose public key has an even y coordinate, as required by BIP-340.
+// Otherwise, the result is a *Config.
+func Start(info round.Info, taproot bool) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		if taproot {
+			if _, ok := info.Group.(curve.Secp256k1); !ok {
+				return nil, errors.New("frost/keygen: taproot requires the secp256k1 group")
+			}
+		}
+		helper, err := round.NewSession(info, sessionID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("frost/keygen: %w", err)
+		}
+
+		// aᵢ₀
+		secret := sample.Scalar(helper.Rand(), helper.Group())
+		return &round1{
+			Helper:     helper,
+			taproot:    taproot,
+			Polynomial: polynomial.NewPolynomial(helper.Rand(), helper.Group(), helper.Threshold(), secret),
+			Secret:     secret,
+		}, nil
+	}
+}
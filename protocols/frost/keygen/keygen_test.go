@@ -0,0 +1,109 @@
+package keygen
+
+import (
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/test"
+)
+
+var group = curve.Secp256k1{}
+
+func runKeygen(t *testing.T, partyIDs party.IDSlice, threshold int, taproot bool) []round.Session {
+	rounds := make([]round.Session, 0, len(partyIDs))
+	for _, partyID := range partyIDs {
+		info := round.Info{
+			ProtocolID:       "frost/keygen-test",
+			FinalRoundNumber: Rounds,
+			SelfID:           partyID,
+			PartyIDs:         partyIDs,
+			Threshold:        threshold,
+			Group:            group,
+		}
+		r, err := Start(info, taproot)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+
+	for {
+		err, done := test.Rounds(rounds, nil)
+		require.NoError(t, err, "failed to process round")
+		if done {
+			break
+		}
+	}
+	return rounds
+}
+
+func TestKeygen(t *testing.T) {
+	N := 5
+	T := 2
+	partyIDs := test.PartyIDs(N)
+
+	rounds := runKeygen(t, partyIDs, T, false)
+
+	configs := make(map[party.ID]*Config, N)
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r)
+		require.IsType(t, &Config{}, r.(*round.Output).Result)
+		c := r.(*round.Output).Result.(*Config)
+
+		data, err := cbor.Marshal(c)
+		require.NoError(t, err)
+		c2 := EmptyConfig(group)
+		require.NoError(t, cbor.Unmarshal(data, c2))
+		configs[c2.ID] = c2
+	}
+
+	first := configs[partyIDs[0]]
+	for _, c := range configs {
+		assert.True(t, first.PublicKey.Equal(c.PublicKey), "public key is different")
+		assert.EqualValues(t, first.ChainKey, c.ChainKey, "chain key is different")
+		assert.Equal(t, T, c.Threshold)
+		for l, Y := range first.VerificationShares.Points {
+			assert.True(t, Y.Equal(c.VerificationShares.Points[l]), "verification share is different")
+		}
+		assert.True(t, c.PrivateShare.ActOnBase().Equal(first.VerificationShares.Points[c.ID]), "private share doesn't match")
+	}
+
+	// any T+1 shares reconstruct the secret key
+	signers := partyIDs[1 : T+2]
+	lagrange := polynomial.Lagrange(group, signers)
+	secret := group.NewScalar()
+	for _, l := range signers {
+		secret.Add(group.NewScalar().Set(lagrange[l]).Mul(configs[l].PrivateShare))
+	}
+	assert.True(t, secret.ActOnBase().Equal(first.PublicKey), "shares don't reconstruct the public key")
+}
+
+func TestKeygenTaproot(t *testing.T) {
+	N := 3
+	T := 1
+	partyIDs := test.PartyIDs(N)
+
+	// repeat, so that both even and odd public keys are likely to be generated
+	for i := 0; i < 4; i++ {
+		rounds := runKeygen(t, partyIDs, T, true)
+		var publicKey []byte
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r)
+			require.IsType(t, &TaprootConfig{}, r.(*round.Output).Result)
+			c := r.(*round.Output).Result.(*TaprootConfig)
+			if publicKey == nil {
+				publicKey = c.PublicKey
+			}
+			assert.EqualValues(t, publicKey, c.PublicKey, "public key is different")
+			assert.True(t, c.PrivateShare.ActOnBase().Equal(c.VerificationShares[c.ID]), "private share doesn't match")
+
+			generic, err := c.Config()
+			require.NoError(t, err)
+			assert.True(t, generic.PublicKey.(*curve.Secp256k1Point).HasEvenY(), "public key should be even")
+		}
+	}
+}
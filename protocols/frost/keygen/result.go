@@ -0,0 +1,96 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/taproot"
+	"github.com/w3-key/mps-lean/pkg/types"
+)
+
+// Config contains all the information produced after key generation, from the perspective
+// of a single participant.
+//
+// When unmarshalling, EmptyConfig needs to be called to set the group, before
+// calling cbor.Unmarshal, or equivalent methods.
+type Config struct {
+	// ID is the identifier for this participant.
+	ID party.ID
+	// Threshold is the number of accepted corruptions while still being able to sign.
+	Threshold int
+	// PrivateShare is the fraction of the secret key owned by this participant.
+	PrivateShare curve.Scalar
+	// PublicKey is the shared public key for this consortium of signers.
+	//
+	// This key can be used to verify signatures produced by the consortium.
+	PublicKey curve.Point
+	// ChainKey is the additional randomness we've agreed upon.
+	//
+	// This is only ever useful if you do BIP-32 key derivation, or something similar.
+	ChainKey types.RID
+	// VerificationShares is a map between parties and a commitment to their private share.
+	//
+	// This will later be used to verify the integrity of the signing protocol.
+	VerificationShares *party.PointMap
+}
+
+// EmptyConfig creates an empty Config with a fixed group, ready for unmarshalling.
+//
+// This needs to be used for unmarshalling, otherwise the points on the curve can't
+// be decoded.
+func EmptyConfig(group curve.Curve) *Config {
+	return &Config{
+		PrivateShare:       group.NewScalar(),
+		PublicKey:          group.NewPoint(),
+		VerificationShares: party.EmptyPointMap(group),
+	}
+}
+
+// Curve returns the Elliptic Curve Group associated with this result.
+func (r *Config) Curve() curve.Curve {
+	return r.PublicKey.Curve()
+}
+
+// TaprootConfig is like Config, but for Taproot.
+//
+// The main difference is that our public key is an actual taproot public key.
+type TaprootConfig struct {
+	// ID is the identifier for this participant.
+	ID party.ID
+	// Threshold is the number of accepted corruptions while still being able to sign.
+	Threshold int
+	// PrivateShare is the fraction of the secret key owned by this participant.
+	PrivateShare *curve.Secp256k1Scalar
+	// PublicKey is the shared public key for this consortium of signers.
+	//
+	// This key can be used to verify signatures produced by the consortium.
+	PublicKey taproot.PublicKey
+	// ChainKey is the additional randomness we've agreed upon.
+	//
+	// This is only ever useful if you do BIP-32 key derivation, or something similar.
+	ChainKey types.RID
+	// VerificationShares is a map between parties and a commitment to their private share.
+	//
+	// This will later be used to verify the integrity of the signing protocol.
+	VerificationShares map[party.ID]*curve.Secp256k1Point
+}
+
+// Config converts a TaprootConfig to a generic Config over Secp256k1,
+// where the public key is the point with even y coordinate.
+func (r *TaprootConfig) Config() (*Config, error) {
+	PublicKey, err := curve.Secp256k1{}.LiftX(r.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	VerificationShares := make(map[party.ID]curve.Point, len(r.VerificationShares))
+	for l, Y := range r.VerificationShares {
+		VerificationShares[l] = Y
+	}
+	return &Config{
+		ID:                 r.ID,
+		Threshold:          r.Threshold,
+		PrivateShare:       r.PrivateShare,
+		PublicKey:          PublicKey,
+		ChainKey:           r.ChainKey,
+		VerificationShares: party.NewPointMap(VerificationShares),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package keygen
+
+import (
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+
+	// taproot indicates whether the result should be compatible with BIP-340.
+	taproot bool
+
+	// Polynomial = fᵢ(X), with fᵢ(0) = aᵢ₀
+	Polynomial *polynomial.Polynomial
+	// Secret = aᵢ₀
+	Secret curve.Scalar
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - compute Φᵢ = Fᵢ(X) = fᵢ(X)•G
+// - compute a proof σᵢ of knowledge of aᵢ₀
+// - sample a chain key cᵢ, and commit to it
+// - broadcast Φᵢ, σᵢ and the commitment.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	Phi := polynomial.NewPolynomialExponent(r.Polynomial)
+	Sigma := zksch.NewProof(r.Rand(), r.HashForID(r.SelfID()), Phi.Constant(), r.Secret, nil)
+
+	ChainKey, err := types.NewRID(r.Rand())
+	if err != nil {
+		return r, err
+	}
+	Commitment, Decommitment, err := r.HashForID(r.SelfID()).Commit(r.Rand(), ChainKey)
+	if err != nil {
+		return r, err
+	}
+
+	if err = r.BroadcastMessage(out, &broadcast2{
+		Phi:        Phi,
+		Sigma:      Sigma,
+		Commitment: Commitment,
+	}); err != nil {
+		return r, err
+	}
+
+	return &round2{
+		round1:       r,
+		Phi:          map[party.ID]*polynomial.Exponent{r.SelfID(): Phi},
+		ChainKeys:    map[party.ID]types.RID{r.SelfID(): ChainKey},
+		Commitments:  map[party.ID]hash.Commitment{r.SelfID(): Commitment},
+		Decommitment: Decommitment,
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
@@ -0,0 +1,118 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/types"
+	zksch "github.com/w3-key/mps-lean/pkg/zk/sch"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+
+	// Phi[j] = Φⱼ = Fⱼ(X)
+	Phi map[party.ID]*polynomial.Exponent
+	// ChainKeys[j] = cⱼ
+	ChainKeys map[party.ID]types.RID
+	// Commitments[j] = H(cⱼ, uⱼ)
+	Commitments map[party.ID]hash.Commitment
+	// Decommitment = uᵢ
+	Decommitment hash.Decommitment
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// Phi = Φᵢ = Fᵢ(X)
+	Phi *polynomial.Exponent
+	// Sigma = σᵢ is a proof of knowledge of aᵢ₀
+	Sigma *zksch.Proof
+	// Commitment = H(cᵢ, uᵢ)
+	Commitment hash.Commitment
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify that Φⱼ has the right degree
+// - verify σⱼ with respect to Φⱼ(0)
+// - save Φⱼ and the commitment.
+func (r *round2) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Phi == nil || body.Sigma == nil {
+		return round.ErrNilFields
+	}
+	if body.Phi.Degree() != r.Threshold() {
+		return errors.New("polynomial has the wrong degree")
+	}
+	if body.Phi.Constant().IsIdentity() {
+		return errors.New("polynomial has an identity constant")
+	}
+	if err := body.Commitment.Validate(); err != nil {
+		return err
+	}
+	if !body.Sigma.Verify(r.HashForID(from), body.Phi.Constant(), nil) {
+		return errors.New("failed to validate Schnorr proof")
+	}
+	r.Phi[from] = body.Phi
+	r.Commitments[from] = body.Commitment
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - send fᵢ(j) to each party j
+// - broadcast the decommitment of cᵢ.
+func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	if err := r.BroadcastMessage(out, &broadcast3{
+		ChainKey:     r.ChainKeys[r.SelfID()],
+		Decommitment: r.Decommitment,
+	}); err != nil {
+		return r, err
+	}
+
+	for _, j := range r.OtherPartyIDs() {
+		if err := r.SendMessage(out, &message3{
+			Share: r.Polynomial.Evaluate(j.Scalar(r.Group())),
+		}, j); err != nil {
+			return r, err
+		}
+	}
+
+	return &round3{
+		round2: r,
+		Shares: map[party.ID]curve.Scalar{r.SelfID(): r.Polynomial.Evaluate(r.SelfID().Scalar(r.Group()))},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2{
+		Phi:   polynomial.EmptyExponent(r.Group()),
+		Sigma: zksch.EmptyProof(r.Group()),
+	}
+}
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
@@ -0,0 +1,166 @@
+package keygen
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/taproot"
+	"github.com/w3-key/mps-lean/pkg/types"
+)
+
+var _ round.Round = (*round3)(nil)
+
+type round3 struct {
+	*round2
+
+	// Shares[j] = fⱼ(i)
+	Shares map[party.ID]curve.Scalar
+}
+
+type message3 struct {
+	// Share = fᵢ(j)
+	Share curve.Scalar
+}
+
+type broadcast3 struct {
+	round.NormalBroadcastContent
+	// ChainKey = cᵢ
+	ChainKey types.RID
+	// Decommitment = uᵢ
+	Decommitment hash.Decommitment
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify the decommitment of cⱼ
+// - save cⱼ.
+func (r *round3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if err := body.ChainKey.Validate(); err != nil {
+		return err
+	}
+	if err := body.Decommitment.Validate(); err != nil {
+		return err
+	}
+	if !r.HashForID(from).Decommit(r.Commitments[from], body.Decommitment, body.ChainKey) {
+		return errors.New("failed to decommit chain key")
+	}
+	r.ChainKeys[from] = body.ChainKey
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+//
+// - verify that fⱼ(i)•G = Φⱼ(i).
+func (r *round3) VerifyMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*message3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Share == nil || body.Share.IsZero() {
+		return round.ErrNilFields
+	}
+	expected := r.Phi[from].Evaluate(r.SelfID().Scalar(r.Group()))
+	if !body.Share.ActOnBase().Equal(expected) {
+		return errors.New("share is inconsistent with the polynomial")
+	}
+	return nil
+}
+
+// StoreMessage implements round.Round.
+//
+// - save fⱼ(i).
+func (r *round3) StoreMessage(msg round.Message) error {
+	r.Shares[msg.From] = msg.Content.(*message3).Share
+	return nil
+}
+
+// Finalize implements round.Round
+//
+// - compute the private share sᵢ = ∑ⱼ fⱼ(i)
+// - compute the public key Y = ∑ⱼ Φⱼ(0), and the verification shares Yₗ = ∑ⱼ Φⱼ(l)
+// - compute the chain key c = ⊕ⱼ cⱼ
+// - if taproot is used and Y has an odd y coordinate, negate sᵢ, Y and Yₗ.
+func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
+	PrivateShare := r.Group().NewScalar()
+	for _, j := range r.PartyIDs() {
+		PrivateShare.Add(r.Shares[j])
+	}
+
+	ChainKey := types.EmptyRID()
+	for _, j := range r.PartyIDs() {
+		ChainKey.XOR(r.ChainKeys[j])
+	}
+
+	Phis := make([]*polynomial.Exponent, 0, r.N())
+	for _, j := range r.PartyIDs() {
+		Phis = append(Phis, r.Phi[j])
+	}
+	Phi, err := polynomial.Sum(Phis)
+	if err != nil {
+		return r, err
+	}
+	PublicKey := Phi.Constant()
+	VerificationShares := make(map[party.ID]curve.Point, r.N())
+	for _, l := range r.PartyIDs() {
+		VerificationShares[l] = Phi.Evaluate(l.Scalar(r.Group()))
+	}
+
+	if !r.taproot {
+		return r.ResultRound(&Config{
+			ID:                 r.SelfID(),
+			Threshold:          r.Threshold(),
+			PrivateShare:       PrivateShare,
+			PublicKey:          PublicKey,
+			ChainKey:           ChainKey,
+			VerificationShares: party.NewPointMap(VerificationShares),
+		}), nil
+	}
+
+	// BIP-340 public keys are implicitly even, so we negate the whole sharing if necessary.
+	if !PublicKey.(*curve.Secp256k1Point).HasEvenY() {
+		PrivateShare.Negate()
+		PublicKey = PublicKey.Negate()
+		for l, Y := range VerificationShares {
+			VerificationShares[l] = Y.Negate()
+		}
+	}
+	secpVerificationShares := make(map[party.ID]*curve.Secp256k1Point, len(VerificationShares))
+	for l, Y := range VerificationShares {
+		secpVerificationShares[l] = Y.(*curve.Secp256k1Point)
+	}
+	return r.ResultRound(&TaprootConfig{
+		ID:                 r.SelfID(),
+		Threshold:          r.Threshold(),
+		PrivateShare:       PrivateShare.(*curve.Secp256k1Scalar),
+		PublicKey:          taproot.PublicKey(PublicKey.(*curve.Secp256k1Point).XBytes()),
+		ChainKey:           ChainKey,
+		VerificationShares: secpVerificationShares,
+	}), nil
+}
+
+// RoundNumber implements round.Content.
+func (message3) RoundNumber() round.Number { return 3 }
+
+// MessageContent implements round.Round.
+func (r *round3) MessageContent() round.Content {
+	return &message3{Share: r.Group().NewScalar()}
+}
+
+// RoundNumber implements round.Content.
+func (broadcast3) RoundNumber() round.Number { return 3 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (round3) BroadcastContent() round.BroadcastContent { return &broadcast3{} }
+
+// Number implements round.Round.
+func (round3) Number() round.Number { return 3 }
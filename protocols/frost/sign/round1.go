@@ -0,0 +1,58 @@
+package sign
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*round1)(nil)
+
+type round1 struct {
+	*round.Helper
+
+	// taproot indicates whether the signature should be compatible with BIP-340.
+	taproot bool
+	// M is the hash of the message we're signing.
+	M []byte
+	// Y is the public key we're signing for.
+	Y curve.Point
+	// YShares[l] = Yₗ = sₗ•G is the verification share of party l.
+	YShares map[party.ID]curve.Point
+	// PrivateShare = sᵢ
+	PrivateShare curve.Scalar
+}
+
+// VerifyMessage implements round.Round.
+func (r *round1) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (r *round1) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - sample nonces dᵢ, eᵢ
+// - broadcast the commitments Dᵢ = dᵢ•G, Eᵢ = eᵢ•G.
+func (r *round1) Finalize(out chan<- *round.Message) (round.Session, error) {
+	d, D := sample.ScalarPointPair(r.Rand(), r.Group())
+	e, E := sample.ScalarPointPair(r.Rand(), r.Group())
+
+	if err := r.BroadcastMessage(out, &broadcast2{D: D, E: E}); err != nil {
+		return r, err
+	}
+
+	return &round2{
+		round1: r,
+		d:      d,
+		e:      e,
+		D:      map[party.ID]curve.Point{r.SelfID(): D},
+		E:      map[party.ID]curve.Point{r.SelfID(): E},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round1) MessageContent() round.Content { return nil }
+
+// Number implements round.Round.
+func (round1) Number() round.Number { return 1 }
@@ -0,0 +1,134 @@
+package sign
+
+import (
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+)
+
+var _ round.Round = (*round2)(nil)
+
+type round2 struct {
+	*round1
+
+	// d, e = dᵢ, eᵢ are the nonces of this party.
+	d, e curve.Scalar
+	// D[l], E[l] = Dₗ, Eₗ
+	D, E map[party.ID]curve.Point
+}
+
+type broadcast2 struct {
+	round.ReliableBroadcastContent
+	// D = Dᵢ = dᵢ•G
+	D curve.Point
+	// E = Eᵢ = eᵢ•G
+	E curve.Point
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - save Dⱼ, Eⱼ.
+func (r *round2) StoreBroadcastMessage(msg round.Message) error {
+	body, ok := msg.Content.(*broadcast2)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.D.IsIdentity() || body.E.IsIdentity() {
+		return round.ErrNilFields
+	}
+	r.D[msg.From] = body.D
+	r.E[msg.From] = body.E
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round2) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round2) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - compute the binding factors ρₗ = H(l, M, B), where B = {(l, Dₗ, Eₗ)}
+// - compute Rₗ = Dₗ + ρₗ•Eₗ and R = ∑ₗ Rₗ
+// - if taproot is used and R has an odd y coordinate, negate R, Rₗ and the nonces
+// - compute the challenge c = H(R, Y, M)
+// - broadcast zᵢ = dᵢ + eᵢ•ρᵢ + λᵢ•sᵢ•c.
+func (r *round2) Finalize(out chan<- *round.Message) (round.Session, error) {
+	group := r.Group()
+
+	// the session hash already includes M
+	h := r.Hash()
+	for _, l := range r.PartyIDs() {
+		_ = h.WriteAny(l, r.D[l], r.E[l])
+	}
+
+	R := group.NewPoint()
+	RShares := make(map[party.ID]curve.Point, r.N())
+	var rho curve.Scalar
+	for _, l := range r.PartyIDs() {
+		rhoL := sample.Scalar(h.Fork(l).Digest(), group)
+		if l == r.SelfID() {
+			rho = rhoL
+		}
+		RShares[l] = rhoL.Act(r.E[l]).Add(r.D[l])
+		R = R.Add(RShares[l])
+	}
+
+	d := group.NewScalar().Set(r.d)
+	e := group.NewScalar().Set(r.e)
+	var c curve.Scalar
+	if r.taproot {
+		if !R.(*curve.Secp256k1Point).HasEvenY() {
+			R = R.Negate()
+			for l, RShare := range RShares {
+				RShares[l] = RShare.Negate()
+			}
+			d.Negate()
+			e.Negate()
+		}
+		c = computeTaprootChallenge(R.(*curve.Secp256k1Point), r.Y.(*curve.Secp256k1Point), r.M)
+	} else {
+		var err error
+		if c, err = computeChallenge(R, r.Y, r.M); err != nil {
+			return r, err
+		}
+	}
+
+	// zᵢ = dᵢ + eᵢ•ρᵢ + λᵢ•sᵢ•c
+	lambda := polynomial.Lagrange(group, r.PartyIDs())
+	z := group.NewScalar().Set(lambda[r.SelfID()]).Mul(r.PrivateShare).Mul(c)
+	z.Add(e.Mul(rho)).Add(d)
+
+	if err := r.BroadcastMessage(out, &broadcast3{Z: z}); err != nil {
+		return r, err
+	}
+
+	return &round3{
+		round2:  r,
+		R:       R,
+		RShares: RShares,
+		c:       c,
+		lambda:  lambda,
+		Z:       map[party.ID]curve.Scalar{r.SelfID(): z},
+	}, nil
+}
+
+// MessageContent implements round.Round.
+func (round2) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast2) RoundNumber() round.Number { return 2 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round2) BroadcastContent() round.BroadcastContent {
+	return &broadcast2{
+		D: r.Group().NewPoint(),
+		E: r.Group().NewPoint(),
+	}
+}
+
+// Number implements round.Round.
+func (round2) Number() round.Number { return 2 }
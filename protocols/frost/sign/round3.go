@@ -0,0 +1,108 @@
+package sign
+
+import (
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/taproot"
+)
+
+var _ round.Round = (*round3)(nil)
+
+type round3 struct {
+	*round2
+
+	// R = ∑ₗ Rₗ is the commitment point of the signature.
+	R curve.Point
+	// RShares[l] = Rₗ = Dₗ + ρₗ•Eₗ
+	RShares map[party.ID]curve.Point
+	// c = H(R, Y, M)
+	c curve.Scalar
+	// lambda[l] = λₗ
+	lambda map[party.ID]curve.Scalar
+	// Z[l] = zₗ
+	Z map[party.ID]curve.Scalar
+}
+
+type broadcast3 struct {
+	round.NormalBroadcastContent
+	// Z = zᵢ
+	Z curve.Scalar
+}
+
+// StoreBroadcastMessage implements round.BroadcastRound.
+//
+// - verify that zⱼ•G = Rⱼ + c•λⱼ•Yⱼ
+// - save zⱼ.
+func (r *round3) StoreBroadcastMessage(msg round.Message) error {
+	from := msg.From
+	body, ok := msg.Content.(*broadcast3)
+	if !ok || body == nil {
+		return round.ErrInvalidContent
+	}
+	if body.Z.IsZero() {
+		return round.ErrNilFields
+	}
+
+	expected := r.Group().NewScalar().Set(r.c).Mul(r.lambda[from]).Act(r.YShares[from]).Add(r.RShares[from])
+	if !body.Z.ActOnBase().Equal(expected) {
+		return errors.New("failed to verify signature share")
+	}
+	r.Z[from] = body.Z
+	return nil
+}
+
+// VerifyMessage implements round.Round.
+func (round3) VerifyMessage(round.Message) error { return nil }
+
+// StoreMessage implements round.Round.
+func (round3) StoreMessage(round.Message) error { return nil }
+
+// Finalize implements round.Round
+//
+// - compute z = ∑ₗ zₗ
+// - verify and output the signature (R, z).
+func (r *round3) Finalize(chan<- *round.Message) (round.Session, error) {
+	z := r.Group().NewScalar()
+	for _, l := range r.PartyIDs() {
+		z.Add(r.Z[l])
+	}
+
+	if r.taproot {
+		zBytes, err := z.MarshalBinary()
+		if err != nil {
+			return r, err
+		}
+		sig := make(taproot.Signature, 0, taproot.SignatureLen)
+		sig = append(sig, r.R.(*curve.Secp256k1Point).XBytes()...)
+		sig = append(sig, zBytes...)
+
+		publicKey := taproot.PublicKey(r.Y.(*curve.Secp256k1Point).XBytes())
+		if !publicKey.Verify(sig, r.M) {
+			return r.AbortRound(errors.New("failed to validate signature")), nil
+		}
+		return r.ResultRound(sig), nil
+	}
+
+	sig := &Signature{R: r.R, Z: z}
+	if !sig.Verify(r.Y, r.M) {
+		return r.AbortRound(errors.New("failed to validate signature")), nil
+	}
+	return r.ResultRound(sig), nil
+}
+
+// MessageContent implements round.Round.
+func (round3) MessageContent() round.Content { return nil }
+
+// RoundNumber implements round.Content.
+func (broadcast3) RoundNumber() round.Number { return 3 }
+
+// BroadcastContent implements round.BroadcastRound.
+func (r *round3) BroadcastContent() round.BroadcastContent {
+	return &broadcast3{Z: r.Group().NewScalar()}
+}
+
+// Number implements round.Round.
+func (round3) Number() round.Number { return 3 }
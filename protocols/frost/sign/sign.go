@@ -0,0 +1,89 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/protocol"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/protocols/frost/keygen"
+)
+
+const (
+	// Rounds is the number of rounds in the FROST signing protocol.
+	Rounds round.Number = 3
+	// protocolID is the identifier for FROST signing.
+	protocolID = "frost/sign-threshold"
+	// protocolIDTaproot is the identifier for FROST signing compatible with BIP-340.
+	protocolIDTaproot = "frost/sign-threshold-taproot"
+)
+
+// Start returns the first round of FROST signing for the message hash `messageHash`.
+//
+// If taproot is set, the config must be over Secp256k1 with an even public key,
+// and the result is a taproot.Signature. Otherwise, the result is a *Signature.
+//
+// Over Edwards25519, messageHash is the message itself, and the resulting *Signature
+// follows RFC 8032.
+// The nonces of this party are sampled from crypto/rand, or from the reader set with round.WithRand.
+func Start(config *keygen.Config, signers []party.ID, messageHash []byte, taproot bool, opts ...round.Option) protocol.StartFunc {
+	return func(sessionID []byte) (round.Session, error) {
+		group := config.Curve()
+		id := protocolID
+		if taproot {
+			P, ok := config.PublicKey.(*curve.Secp256k1Point)
+			if !ok || !P.HasEvenY() {
+				return nil, errors.New("frost/sign: taproot requires an even secp256k1 public key")
+			}
+			id = protocolIDTaproot
+		}
+		if len(messageHash) == 0 {
+			return nil, errors.New("frost/sign: empty message hash")
+		}
+
+		info := round.Info{
+			ProtocolID:       id,
+			FinalRoundNumber: Rounds,
+			SelfID:           config.ID,
+			PartyIDs:         signers,
+			Threshold:        config.Threshold,
+			Group:            group,
+		}
+		info.Apply(opts...)
+		publicKey, err := config.PublicKey.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("frost/sign: %w", err)
+		}
+		helper, err := round.NewSession(info, sessionID, nil,
+			&hash.BytesWithDomain{TheDomain: "FROST Public Key", Bytes: publicKey},
+			&hash.BytesWithDomain{TheDomain: "FROST Message", Bytes: messageHash})
+		if err != nil {
+			return nil, fmt.Errorf("frost/sign: %w", err)
+		}
+
+		signerIDs := helper.PartyIDs()
+		if len(signerIDs) <= config.Threshold {
+			return nil, errors.New("frost/sign: not enough signers")
+		}
+		YShares := make(map[party.ID]curve.Point, len(signerIDs))
+		for _, l := range signerIDs {
+			Y, ok := config.VerificationShares.Points[l]
+			if !ok {
+				return nil, fmt.Errorf("frost/sign: missing verification share for %s", l)
+			}
+			YShares[l] = Y
+		}
+
+		return &round1{
+			Helper:       helper,
+			taproot:      taproot,
+			M:            messageHash,
+			Y:            config.PublicKey,
+			YShares:      YShares,
+			PrivateShare: config.PrivateShare,
+		}, nil
+	}
+}
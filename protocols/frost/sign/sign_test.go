@@ -0,0 +1,180 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/polynomial"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/party"
+	"github.com/w3-key/mps-lean/pkg/round"
+	"github.com/w3-key/mps-lean/pkg/taproot"
+	"github.com/w3-key/mps-lean/pkg/test"
+	"github.com/w3-key/mps-lean/protocols/frost/keygen"
+)
+
+// generateConfigs deals a random secret key among partyIDs, using a trusted dealer.
+//
+// If even is set, the secret is chosen such that the public key has an even y coordinate.
+func generateConfigs(group curve.Curve, partyIDs party.IDSlice, threshold int, even bool) map[party.ID]*keygen.Config {
+	secret := sample.Scalar(rand.Reader, group)
+	publicKey := secret.ActOnBase()
+	if even && !publicKey.(*curve.Secp256k1Point).HasEvenY() {
+		secret.Negate()
+		publicKey = publicKey.Negate()
+	}
+	f := polynomial.NewPolynomial(rand.Reader, group, threshold, secret)
+
+	privateShares := make(map[party.ID]curve.Scalar, len(partyIDs))
+	verificationShares := make(map[party.ID]curve.Point, len(partyIDs))
+	for _, l := range partyIDs {
+		privateShares[l] = f.Evaluate(l.Scalar(group))
+		verificationShares[l] = privateShares[l].ActOnBase()
+	}
+
+	configs := make(map[party.ID]*keygen.Config, len(partyIDs))
+	for _, l := range partyIDs {
+		configs[l] = &keygen.Config{
+			ID:                 l,
+			Threshold:          threshold,
+			PrivateShare:       privateShares[l],
+			PublicKey:          publicKey,
+			VerificationShares: party.NewPointMap(verificationShares),
+		}
+	}
+	return configs
+}
+
+func runSign(t *testing.T, configs map[party.ID]*keygen.Config, signers party.IDSlice, messageHash []byte, taproot bool, rule test.Rule) ([]round.Session, error) {
+	rounds := make([]round.Session, 0, len(signers))
+	for _, l := range signers {
+		r, err := Start(configs[l], signers, messageHash, taproot)(nil)
+		require.NoError(t, err, "round creation should not result in an error")
+		rounds = append(rounds, r)
+	}
+	for {
+		err, done := test.Rounds(rounds, rule)
+		if err != nil {
+			return rounds, err
+		}
+		if done {
+			return rounds, nil
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	group := curve.Secp256k1{}
+	N := 5
+	T := 2
+	partyIDs := test.PartyIDs(N)
+	configs := generateConfigs(group, partyIDs, T, false)
+	publicKey := configs[partyIDs[0]].PublicKey
+
+	messageHash := make([]byte, 32)
+	_, _ = rand.Read(messageHash)
+
+	signers := partyIDs[:T+1]
+	rounds, err := runSign(t, configs, signers, messageHash, false, nil)
+	require.NoError(t, err, "failed to process round")
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		require.IsType(t, &Signature{}, r.(*round.Output).Result)
+		sig := r.(*round.Output).Result.(*Signature)
+		assert.True(t, sig.Verify(publicKey, messageHash), "expected valid signature")
+		assert.False(t, sig.Verify(publicKey, []byte("other message")), "signature should be bound to the message")
+	}
+}
+
+func TestSignEd25519(t *testing.T) {
+	group := curve.Edwards25519{}
+	N := 4
+	T := 2
+	partyIDs := test.PartyIDs(N)
+	configs := generateConfigs(group, partyIDs, T, false)
+	publicKey, err := configs[partyIDs[0]].PublicKey.MarshalBinary()
+	require.NoError(t, err)
+
+	message := []byte("hello edwards25519")
+	rounds, err := runSign(t, configs, partyIDs[1:], message, false, nil)
+	require.NoError(t, err, "failed to process round")
+
+	for _, r := range rounds {
+		require.IsType(t, &round.Output{}, r, "expected result round")
+		require.IsType(t, &Signature{}, r.(*round.Output).Result)
+		sig := r.(*round.Output).Result.(*Signature)
+		assert.True(t, sig.Verify(configs[partyIDs[0]].PublicKey, message), "expected valid signature")
+
+		sigBytes, err := sig.Ed25519()
+		require.NoError(t, err)
+		assert.True(t, ed25519.Verify(publicKey, message, sigBytes), "expected valid Ed25519 signature")
+		assert.False(t, ed25519.Verify(publicKey, []byte("other message"), sigBytes), "signature should be bound to the message")
+	}
+}
+
+func TestSignTaproot(t *testing.T) {
+	group := curve.Secp256k1{}
+	N := 3
+	T := 1
+	partyIDs := test.PartyIDs(N)
+	configs := generateConfigs(group, partyIDs, T, true)
+	publicKey := taproot.PublicKey(configs[partyIDs[0]].PublicKey.(*curve.Secp256k1Point).XBytes())
+
+	// repeat, so that both even and odd nonces are likely to be generated
+	for i := 0; i < 4; i++ {
+		messageHash := make([]byte, 32)
+		_, _ = rand.Read(messageHash)
+
+		rounds, err := runSign(t, configs, partyIDs[1:], messageHash, true, nil)
+		require.NoError(t, err, "failed to process round")
+
+		for _, r := range rounds {
+			require.IsType(t, &round.Output{}, r, "expected result round")
+			require.IsType(t, taproot.Signature{}, r.(*round.Output).Result)
+			sig := r.(*round.Output).Result.(taproot.Signature)
+			assert.True(t, publicKey.Verify(sig, messageHash), "expected valid signature")
+		}
+	}
+}
+
+func TestSignTaprootOddKey(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(2)
+	var configs map[party.ID]*keygen.Config
+	for configs == nil || configs[partyIDs[0]].PublicKey.(*curve.Secp256k1Point).HasEvenY() {
+		configs = generateConfigs(group, partyIDs, 1, false)
+	}
+	_, err := Start(configs[partyIDs[0]], partyIDs, []byte("hash"), true)(nil)
+	assert.Error(t, err, "taproot signing should require an even public key")
+}
+
+// zRule makes culprit broadcast an invalid share z in round 3.
+type zRule struct {
+	culprit party.ID
+}
+
+func (zRule) ModifyBefore(round.Session) {}
+
+func (zRule) ModifyAfter(round.Session) {}
+
+func (r *zRule) ModifyContent(rNext round.Session, _ party.ID, content round.Content) {
+	body, ok := content.(*broadcast3)
+	if !ok || rNext.SelfID() != r.culprit {
+		return
+	}
+	body.Z = sample.Scalar(rand.Reader, rNext.Group())
+}
+
+func TestSignInvalidShare(t *testing.T) {
+	group := curve.Secp256k1{}
+	partyIDs := test.PartyIDs(3)
+	configs := generateConfigs(group, partyIDs, 1, false)
+
+	_, err := runSign(t, configs, partyIDs, []byte("hash"), false, &zRule{culprit: partyIDs[0]})
+	assert.Error(t, err, "an invalid signature share should be detected")
+}
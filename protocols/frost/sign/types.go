@@ -0,0 +1,117 @@
+package sign
+
+import (
+	"crypto/sha512"
+	"errors"
+
+	"github.com/w3-key/mps-lean/pkg/hash"
+	"github.com/w3-key/mps-lean/pkg/math/curve"
+	"github.com/w3-key/mps-lean/pkg/math/sample"
+	"github.com/w3-key/mps-lean/pkg/taproot"
+)
+
+// Signature represents the result of a Schnorr signature.
+//
+// This signature claims to satisfy:
+//
+//	z * G = R + H(R, Y, m) * Y
+//
+// for a public key Y.
+//
+// Over Edwards25519, H is the challenge of RFC 8032, and the signature can be
+// converted to a standard Ed25519 signature with the Ed25519 method.
+type Signature struct {
+	// R is the commitment point.
+	R curve.Point
+	// Z is the response scalar.
+	Z curve.Scalar
+}
+
+// EmptySignature creates a Signature with a fixed group, ready for unmarshalling.
+func EmptySignature(group curve.Curve) Signature {
+	return Signature{R: group.NewPoint(), Z: group.NewScalar()}
+}
+
+// Verify checks if a signature equation actually holds.
+//
+// Note that m is the hash of a message, and not the message itself,
+// except over Edwards25519, where m is the message, as in RFC 8032.
+func (sig Signature) Verify(public curve.Point, m []byte) bool {
+	group := public.Curve()
+
+	challenge, err := computeChallenge(sig.R, public, m)
+	if err != nil {
+		return false
+	}
+
+	expected := challenge.Act(public)
+	expected = expected.Add(sig.R)
+
+	actual := sig.Z.ActOnBase()
+
+	return expected.Equal(actual) && !sig.R.IsIdentity() && group.Name() == sig.R.Curve().Name()
+}
+
+// Ed25519 returns the 64 byte encoding R || z of this signature, as defined in RFC 8032.
+//
+// The result can be verified with crypto/ed25519, using the encoding of the public key.
+func (sig Signature) Ed25519() ([]byte, error) {
+	R, ok := sig.R.(*curve.Edwards25519Point)
+	if !ok {
+		return nil, errors.New("signature is not over edwards25519")
+	}
+	z, ok := sig.Z.(*curve.Edwards25519Scalar)
+	if !ok {
+		return nil, errors.New("signature is not over edwards25519")
+	}
+	RBytes, err := R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, 64)
+	out = append(out, RBytes...)
+	return append(out, z.Bytes()...), nil
+}
+
+// computeChallenge returns c = H(R, Y, m).
+//
+// Over Edwards25519, this is the challenge SHA-512(R || Y || m) of RFC 8032.
+func computeChallenge(R, Y curve.Point, m []byte) (curve.Scalar, error) {
+	if _, ok := R.(*curve.Edwards25519Point); ok {
+		return computeEd25519Challenge(R, Y, m)
+	}
+	h := hash.New()
+	if err := h.WriteAny(R, Y, m); err != nil {
+		return nil, err
+	}
+	return sample.Scalar(h.Digest(), R.Curve()), nil
+}
+
+// computeTaprootChallenge returns c = H("BIP0340/challenge", R.x, Y.x, m).
+func computeTaprootChallenge(R, Y *curve.Secp256k1Point, m []byte) curve.Scalar {
+	cHash := taproot.TaggedHash("BIP0340/challenge", R.XBytes(), Y.XBytes(), m)
+	c := new(curve.Secp256k1Scalar)
+	_ = c.UnmarshalBinary(cHash)
+	return c
+}
+
+// computeEd25519Challenge returns c = SHA-512(R || Y || m) mod ℓ.
+func computeEd25519Challenge(R, Y curve.Point, m []byte) (curve.Scalar, error) {
+	RBytes, err := R.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	YBytes, err := Y.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	h := sha512.New()
+	_, _ = h.Write(RBytes)
+	_, _ = h.Write(YBytes)
+	_, _ = h.Write(m)
+	c, err := new(curve.Edwards25519Scalar).SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}